@@ -0,0 +1,120 @@
+package achievements
+
+import "time"
+
+// Kind is what an achievement measures
+type Kind string
+
+const (
+	// BestScore is the player's best score in one game
+	BestScore Kind = "bestScore"
+	// GamesPlayed is the number of different games the player has played
+	GamesPlayed Kind = "gamesPlayed"
+	// DayStreak is the longest run of consecutive days the player played on
+	DayStreak Kind = "dayStreak"
+	// SessionsStarted is the number of game sessions the player has started
+	SessionsStarted Kind = "sessionsStarted"
+	// ScoresSubmitted is the number of scores the player has submitted
+	ScoresSubmitted Kind = "scoresSubmitted"
+)
+
+// Definition declares an achievement: it unlocks once what Kind measures
+// reaches Target. Game is the slug of the game a BestScore achievement is for.
+type Definition struct {
+	ID          string
+	Name        string
+	Description string
+	Kind        Kind
+	Game        string
+	Target      int
+}
+
+// Catalog is every achievement a player can unlock. IDs are stored with
+// unlocks, so an achievement must keep its ID once released.
+var Catalog = []Definition{
+	{ID: "first-session", Name: "Insert Coin", Description: "Start your first game",
+		Kind: SessionsStarted, Target: 1},
+	{ID: "first-score", Name: "On the Board", Description: "Submit your first score",
+		Kind: ScoresSubmitted, Target: 1},
+	{ID: "regular", Name: "Regular", Description: "Submit 50 scores",
+		Kind: ScoresSubmitted, Target: 50},
+	{ID: "explorer", Name: "Explorer", Description: "Play 5 different games",
+		Kind: GamesPlayed, Target: 5},
+	{ID: "streak-3", Name: "Hat Trick", Description: "Play three days in a row",
+		Kind: DayStreak, Target: 3},
+	{ID: "streak-7", Name: "Dedicated", Description: "Play seven days in a row",
+		Kind: DayStreak, Target: 7},
+	{ID: "snake-1000", Name: "Snake Charmer", Description: "Score 1,000 or more in Snake",
+		Kind: BestScore, Game: "snake", Target: 1000},
+	{ID: "tetris-10000", Name: "Line Clearer", Description: "Score 10,000 or more in Tetris",
+		Kind: BestScore, Game: "tetris", Target: 10000},
+	{ID: "pac-man-20000", Name: "Ghost Buster", Description: "Score 20,000 or more in Pac-Man",
+		Kind: BestScore, Game: "pac-man", Target: 20000},
+	{ID: "space-invaders-25000", Name: "Earth Defender", Description: "Score 25,000 or more in Space Invaders",
+		Kind: BestScore, Game: "space-invaders", Target: 25000},
+}
+
+// Activity is what a player has done, as achievements judge it. A game is
+// played by starting a session for it or submitting a score to it.
+type Activity struct {
+	Sessions int
+	Scores   int
+	// BestScores maps game slugs to the player's best score in the game
+	BestScores map[string]int
+	Games      int
+	// Days are the distinct days the player played on, as YYYY-MM-DD in the
+	// leaderboard time zone, in ascending order
+	Days []string
+}
+
+// Progress returns how far activity has got toward d, at most d.Target. The
+// achievement is unlocked when it reaches d.Target.
+func (d Definition) Progress(activity Activity) int {
+	var current int
+	switch d.Kind {
+	case BestScore:
+		current = activity.BestScores[d.Game]
+	case GamesPlayed:
+		current = activity.Games
+	case DayStreak:
+		current = longestStreak(activity.Days)
+	case SessionsStarted:
+		current = activity.Sessions
+	case ScoresSubmitted:
+		current = activity.Scores
+	}
+	return min(current, d.Target)
+}
+
+// Reached returns the definitions whose targets activity has reached
+func Reached(definitions []Definition, activity Activity) []Definition {
+	reached := []Definition{}
+	for _, d := range definitions {
+		if d.Progress(activity) >= d.Target {
+			reached = append(reached, d)
+		}
+	}
+	return reached
+}
+
+// longestStreak returns the length of the longest run of consecutive days in
+// days, which must be distinct and ascending
+func longestStreak(days []string) int {
+	longest, run := 0, 0
+	var previous time.Time
+	for _, day := range days {
+		date, err := time.Parse("2006-01-02", day)
+		if err != nil {
+			run = 0
+			continue
+		}
+		if run > 0 && previous.AddDate(0, 0, 1).Equal(date) {
+			run++
+		} else {
+			run = 1
+		}
+		previous = date
+		longest = max(longest, run)
+	}
+	return longest
+}
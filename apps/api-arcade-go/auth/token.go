@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidToken is returned for an access token that is malformed or was
+	// not signed with the server's secret
+	ErrInvalidToken = errors.New("invalid access token")
+	// ErrExpiredToken is returned for a correctly signed access token past its expiry
+	ErrExpiredToken = errors.New("access token has expired")
+)
+
+// jwtHeader is the only header access tokens are issued with or accepted under
+const jwtHeader = `{"alg":"HS256","typ":"JWT"}`
+
+var encodedHeader = base64.RawURLEncoding.EncodeToString([]byte(jwtHeader))
+
+// Claims identify the player an access token was issued to
+type Claims struct {
+	PlayerID  string `json:"sub"`
+	Guest     bool   `json:"guest,omitempty"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Expires returns when the access token stops being accepted
+func (c Claims) Expires() time.Time {
+	return time.Unix(c.ExpiresAt, 0)
+}
+
+// IssueAccessToken signs claims with secret into an HS256 JWT
+func IssueAccessToken(claims Claims, secret []byte) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signed := encodedHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signed + "." + sign(signed, secret), nil
+}
+
+// VerifyAccessToken checks an access token's header, signature and expiry and
+// returns its claims
+func VerifyAccessToken(token string, secret []byte, now time.Time) (Claims, error) {
+	header, rest, ok := strings.Cut(token, ".")
+	if !ok || header != encodedHeader {
+		return Claims{}, ErrInvalidToken
+	}
+	payload, signature, ok := strings.Cut(rest, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(sign(header+"."+payload, secret))) {
+		return Claims{}, ErrInvalidToken
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(decoded, &claims); err != nil || claims.PlayerID == "" {
+		return Claims{}, ErrInvalidToken
+	}
+	if !now.Before(claims.Expires()) {
+		return claims, ErrExpiredToken
+	}
+	return claims, nil
+}
+
+// NewRefreshToken returns a random refresh token and the hash it is stored
+// under. Only the hash is kept, so a leaked database cannot be replayed.
+func NewRefreshToken() (token, hash string, err error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(raw)
+	return token, HashRefreshToken(token), nil
+}
+
+// HashRefreshToken returns the hash a refresh token is stored under
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func sign(payload string, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var secret = []byte("test-auth-secret")
+
+// jwt assembles a token from a raw header and payload, signed with secret
+// whatever the header says
+func jwt(header, payload string) string {
+	signed := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return signed + "." + sign(signed, secret)
+}
+
+func TestVerifyAccessTokenChecksExpiry(t *testing.T) {
+	issued := time.Unix(1700000000, 0)
+	claims := Claims{PlayerID: "player-1", IssuedAt: issued.Unix(), ExpiresAt: issued.Add(15 * time.Minute).Unix()}
+	token, err := IssueAccessToken(claims, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		now  time.Time
+		want error
+	}{
+		// Tokens carry no not-before time, so clock skew toward the past is fine
+		{"before issue", issued.Add(-time.Minute), nil},
+		{"at issue", issued, nil},
+		{"last second", claims.Expires().Add(-time.Second), nil},
+		{"at expiry", claims.Expires(), ErrExpiredToken},
+		{"long expired", claims.Expires().Add(30 * 24 * time.Hour), ErrExpiredToken},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := VerifyAccessToken(token, secret, tc.now)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("error %v, want %v", err, tc.want)
+			}
+			if got != claims {
+				t.Fatalf("claims %+v, want %+v", got, claims)
+			}
+		})
+	}
+
+	// A token without exp has expired since 1970
+	if _, err := VerifyAccessToken(jwt(jwtHeader, `{"sub":"player-1"}`), secret, issued); !errors.Is(err, ErrExpiredToken) {
+		t.Fatalf("no exp: error %v, want %v", err, ErrExpiredToken)
+	}
+}
+
+func TestVerifyAccessTokenAcceptsOnlyHS256(t *testing.T) {
+	payload := `{"sub":"player-1","exp":4000000000}`
+	now := time.Unix(1700000000, 0)
+	if _, err := VerifyAccessToken(jwt(jwtHeader, payload), secret, now); err != nil {
+		t.Fatalf("HS256: %v", err)
+	}
+
+	// Each is signed with the right secret, so only the header can reject it
+	for _, header := range []string{
+		`{"alg":"none","typ":"JWT"}`,
+		`{"alg":"HS512","typ":"JWT"}`,
+		`{"alg":"RS256","typ":"JWT"}`,
+		`{"alg":"HS256"}`,
+		`{"typ":"JWT","alg":"HS256"}`,
+		`{"alg":"HS256","typ":"JWT","kid":"other"}`,
+	} {
+		if _, err := VerifyAccessToken(jwt(header, payload), secret, now); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: error %v, want %v", header, err, ErrInvalidToken)
+		}
+	}
+
+	// An unsigned token under the none algorithm
+	none := jwt(`{"alg":"none","typ":"JWT"}`, payload)
+	unsigned := none[:strings.LastIndex(none, ".")+1]
+	if _, err := VerifyAccessToken(unsigned, secret, now); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("unsigned: error %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestVerifyAccessTokenReadsClaims(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	cases := []struct {
+		payload string
+		want    Claims
+		err     error
+	}{
+		{`{"sub":"player-1","iat":1700000000,"exp":4000000000}`, Claims{PlayerID: "player-1", IssuedAt: 1700000000, ExpiresAt: 4000000000}, nil},
+		{`{"sub":"guest-1","guest":true,"exp":4000000000}`, Claims{PlayerID: "guest-1", Guest: true, ExpiresAt: 4000000000}, nil},
+		{`{"exp":4000000000}`, Claims{}, ErrInvalidToken},
+		{`{"sub":"","exp":4000000000}`, Claims{}, ErrInvalidToken},
+		{`{"sub":42,"exp":4000000000}`, Claims{}, ErrInvalidToken},
+		{`not json`, Claims{}, ErrInvalidToken},
+	}
+	for _, tc := range cases {
+		got, err := VerifyAccessToken(jwt(jwtHeader, tc.payload), secret, now)
+		if !errors.Is(err, tc.err) {
+			t.Fatalf("%s: error %v, want %v", tc.payload, err, tc.err)
+		}
+		if got != tc.want {
+			t.Fatalf("%s: claims %+v, want %+v", tc.payload, got, tc.want)
+		}
+	}
+
+	if _, err := VerifyAccessToken(jwt(jwtHeader, cases[0].payload), []byte("other-secret"), now); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("other secret: error %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestRefreshTokens(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		token, hash, err := NewRefreshToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[token] {
+			t.Fatalf("refresh token %q issued twice", token)
+		}
+		seen[token] = true
+
+		// 32 random bytes, stored as a SHA-256 hex digest that is not the token
+		if raw, err := base64.RawURLEncoding.DecodeString(token); err != nil || len(raw) != 32 {
+			t.Fatalf("token %q is not 32 base64url bytes", token)
+		}
+		if sum, err := hex.DecodeString(hash); err != nil || len(sum) != 32 {
+			t.Fatalf("hash %q is not a SHA-256 hex digest", hash)
+		}
+		if HashRefreshToken(token) != hash {
+			t.Fatalf("token %q does not hash to its stored hash", token)
+		}
+		if strings.Contains(hash, token) {
+			t.Fatalf("hash %q contains its token", hash)
+		}
+	}
+}
@@ -10,6 +10,22 @@ type Config struct {
 	Environment string
 	AppName     string
 	Version     string
+	// StorageBackend is memory (the default, lost on restart) or sqlite
+	StorageBackend string
+	// DatabasePath is the SQLite database file used by the sqlite backend
+	DatabasePath string
+	// SessionSecret signs game session tokens. When unset a random secret is
+	// used, so sessions started before a restart cannot submit scores.
+	SessionSecret string
+	// AuthSecret signs player access tokens. When unset a random secret is
+	// used, so players must log in again after a restart.
+	AuthSecret string
+	// AdminToken authorizes the game catalog administration API, which is
+	// disabled when it is unset
+	AdminToken string
+	// LeaderboardTimeZone is the IANA time zone daily, weekly and monthly
+	// leaderboards roll over at midnight in
+	LeaderboardTimeZone string
 }
 
 var AppConfig Config
@@ -18,13 +34,30 @@ func LoadConfig() {
 	log.Println("Loading configuration...")
 	
 	AppConfig = Config{
-		Port:        getEnv("PORT", "8002"),
-		Environment: getEnv("ENVIRONMENT", "development"),
-		AppName:     "Arcade API",
-		Version:     "1.0.0",
+		Port:           getEnv("PORT", "8002"),
+		Environment:    getEnv("ENVIRONMENT", "development"),
+		AppName:        "Arcade API",
+		Version:        "1.0.0",
+		StorageBackend: getEnv("STORAGE_BACKEND", "memory"),
+		DatabasePath:   getEnv("DATABASE_PATH", "arcade.db"),
+		SessionSecret:  getEnv("SESSION_SECRET", ""),
+		AuthSecret:     getEnv("AUTH_SECRET", ""),
+		AdminToken:     getEnv("ADMIN_TOKEN", ""),
+
+		LeaderboardTimeZone: getEnv("LEADERBOARD_TIMEZONE", "UTC"),
 	}
-	
-	log.Printf("Configuration loaded: %+v", AppConfig)
+
+	logged := AppConfig
+	if logged.SessionSecret != "" {
+		logged.SessionSecret = "[redacted]"
+	}
+	if logged.AuthSecret != "" {
+		logged.AuthSecret = "[redacted]"
+	}
+	if logged.AdminToken != "" {
+		logged.AdminToken = "[redacted]"
+	}
+	log.Printf("Configuration loaded: %+v", logged)
 }
 
 func getEnv(key, defaultValue string) string {
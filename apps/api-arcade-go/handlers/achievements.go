@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"arcade-api/achievements"
+	"arcade-api/models"
+	"arcade-api/utils"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetPlayerAchievements returns every achievement with a player's progress
+// toward it and when they unlocked it
+func (h *Handler) GetPlayerAchievements(c *gin.Context) {
+	player, ok := h.findPlayer(c)
+	if !ok {
+		return
+	}
+
+	activity, err := h.repo.PlayerActivity(player.ID)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to load achievements", err.Error())
+		return
+	}
+	unlocked, err := h.repo.ListAchievements(player.ID)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to load achievements", err.Error())
+		return
+	}
+
+	response := models.PlayerAchievementsResponse{
+		PlayerID:     player.ID,
+		DisplayName:  player.DisplayName,
+		Achievements: make([]models.Achievement, 0, len(achievements.Catalog)),
+		Total:        len(achievements.Catalog),
+	}
+	for _, definition := range achievements.Catalog {
+		achievement := newAchievement(definition)
+		achievement.Progress = definition.Progress(activity)
+		// An unlocked achievement stays complete even if, say, its game's
+		// slug has since changed
+		if unlockedAt, ok := unlocked[definition.ID]; ok {
+			achievement.Unlocked = true
+			achievement.UnlockedAt = &unlockedAt
+			achievement.Progress = definition.Target
+			response.Unlocked++
+		}
+		response.Achievements = append(response.Achievements, achievement)
+	}
+
+	utils.SendSuccessResponse(c, response, "Achievements retrieved successfully")
+}
+
+// checkAchievements unlocks the achievements a player's activity has reached
+// and returns those newly unlocked. Achievements are a side effect of the
+// request that triggers them, so a failure is logged rather than failing it.
+func (h *Handler) checkAchievements(playerID string) []models.Achievement {
+	activity, err := h.repo.PlayerActivity(playerID)
+	if err != nil {
+		log.Printf("Failed to check achievements of player %s: %v", playerID, err)
+		return nil
+	}
+	reached := achievements.Reached(achievements.Catalog, activity)
+	if len(reached) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(reached))
+	for i, definition := range reached {
+		ids[i] = definition.ID
+	}
+	// Storage keeps whole seconds
+	unlockedAt := time.Now().Truncate(time.Second)
+	newlyUnlocked, err := h.repo.UnlockAchievements(playerID, ids, unlockedAt)
+	if err != nil {
+		log.Printf("Failed to unlock achievements of player %s: %v", playerID, err)
+		return nil
+	}
+
+	isNew := make(map[string]bool, len(newlyUnlocked))
+	for _, id := range newlyUnlocked {
+		isNew[id] = true
+	}
+	var unlocked []models.Achievement
+	for _, definition := range reached {
+		if isNew[definition.ID] {
+			achievement := newAchievement(definition)
+			achievement.Unlocked = true
+			achievement.UnlockedAt = &unlockedAt
+			achievement.Progress = definition.Target
+			unlocked = append(unlocked, achievement)
+		}
+	}
+	return unlocked
+}
+
+// newAchievement returns definition as a locked achievement with no progress
+func newAchievement(definition achievements.Definition) models.Achievement {
+	return models.Achievement{
+		ID:          definition.ID,
+		Name:        definition.Name,
+		Description: definition.Description,
+		Target:      definition.Target,
+	}
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"arcade-api/models"
+	"arcade-api/repository"
+	"arcade-api/utils"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	// slugPattern matches the lowercase, hyphenated IDs the arcade frontend
+	// gives its games
+	slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	// thumbnailPattern matches a path of plain segments. No segment may start
+	// with a dot, which rules out .. and hidden files.
+	thumbnailPattern = regexp.MustCompile(`^/?[A-Za-z0-9_-][A-Za-z0-9_.-]*(/[A-Za-z0-9_-][A-Za-z0-9_.-]*)*$`)
+)
+
+// ListAllGames returns every game, including disabled ones
+func (h *Handler) ListAllGames(c *gin.Context) {
+	games, err := h.repo.ListGames(repository.GameFilter{IncludeDisabled: true})
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to load games", err.Error())
+		return
+	}
+
+	response := models.GamesResponse{
+		Games: games,
+		Total: len(games),
+	}
+
+	utils.SendSuccessResponse(c, response, "Games retrieved successfully")
+}
+
+// CreateGame adds a game to the catalog
+func (h *Handler) CreateGame(c *gin.Context) {
+	game, ok := bindGame(c)
+	if !ok {
+		return
+	}
+
+	game, err := h.repo.CreateGame(game)
+	if errors.Is(err, repository.ErrGameExists) {
+		utils.SendErrorResponse(c, http.StatusConflict, "Game already exists", "another game has the same name or slug")
+		return
+	}
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to create game", err.Error())
+		return
+	}
+
+	utils.SendSuccessResponse(c, game, "Game created successfully")
+}
+
+// UpdateGame replaces a game's metadata and scoring rules. Its scores and high
+// score are kept.
+func (h *Handler) UpdateGame(c *gin.Context) {
+	gameID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid game ID", err.Error())
+		return
+	}
+	game, ok := bindGame(c)
+	if !ok {
+		return
+	}
+	game.ID = gameID
+
+	game, err = h.repo.UpdateGame(game)
+	if errors.Is(err, repository.ErrNotFound) {
+		utils.SendErrorResponse(c, http.StatusNotFound, "Game not found", "No game found with the specified ID")
+		return
+	}
+	if errors.Is(err, repository.ErrGameExists) {
+		utils.SendErrorResponse(c, http.StatusConflict, "Game already exists", "another game has the same name or slug")
+		return
+	}
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to update game", err.Error())
+		return
+	}
+
+	utils.SendSuccessResponse(c, game, "Game updated successfully")
+}
+
+// DeleteGame removes a game that has no scores from the catalog
+func (h *Handler) DeleteGame(c *gin.Context) {
+	gameID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid game ID", err.Error())
+		return
+	}
+
+	err = h.repo.DeleteGame(gameID)
+	if errors.Is(err, repository.ErrNotFound) {
+		utils.SendErrorResponse(c, http.StatusNotFound, "Game not found", "No game found with the specified ID")
+		return
+	}
+	if errors.Is(err, repository.ErrGameHasScores) {
+		utils.SendErrorResponse(c, http.StatusConflict, "Game has scores",
+			"a game with scores cannot be deleted; disable it to hide it and keep its leaderboard")
+		return
+	}
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to delete game", err.Error())
+		return
+	}
+
+	utils.SendSuccessResponse(c, nil, "Game deleted successfully")
+}
+
+// bindGame reads and validates a game from the request body. It responds with
+// an error and reports false if the request is invalid.
+func bindGame(c *gin.Context) (models.Game, bool) {
+	var req models.GameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return models.Game{}, false
+	}
+
+	game, err := gameFromRequest(req)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid game", err.Error())
+		return models.Game{}, false
+	}
+	return game, true
+}
+
+// gameFromRequest checks the fields a request binding cannot and returns the
+// game it describes, with text fields trimmed
+func gameFromRequest(req models.GameRequest) (models.Game, error) {
+	if !slugPattern.MatchString(req.Slug) {
+		return models.Game{}, errors.New("slugs are lowercase letters and digits, in words joined by '-'")
+	}
+	if req.Thumbnail != "" && !thumbnailPattern.MatchString(req.Thumbnail) {
+		return models.Game{}, errors.New("thumbnails are paths of letters, digits, '.', '_' and '-', with no segment starting with '.'")
+	}
+
+	game := models.Game{
+		Slug:        req.Slug,
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+		Controls:    make([]string, len(req.Controls)),
+		Category:    strings.TrimSpace(req.Category),
+		Difficulty:  strings.TrimSpace(req.Difficulty),
+		Rules:       req.Rules,
+		Enabled:     req.Enabled == nil || *req.Enabled,
+		Thumbnail:   req.Thumbnail,
+	}
+	for i, control := range req.Controls {
+		game.Controls[i] = strings.TrimSpace(control)
+	}
+
+	fields := []struct{ name, value string }{
+		{"name", game.Name}, {"category", game.Category}, {"difficulty", game.Difficulty},
+	}
+	for _, control := range game.Controls {
+		fields = append(fields, struct{ name, value string }{"controls", control})
+	}
+	for _, field := range fields {
+		if field.value == "" {
+			return models.Game{}, fmt.Errorf("%s cannot be blank", field.name)
+		}
+		if strings.IndexFunc(field.value, func(r rune) bool { return !unicode.IsPrint(r) }) >= 0 {
+			return models.Game{}, fmt.Errorf("%s can only contain printable characters", field.name)
+		}
+	}
+	return game, nil
+}
@@ -2,39 +2,55 @@ package handlers
 
 import (
 	"arcade-api/config"
+	"arcade-api/leaderboard"
 	"arcade-api/models"
+	"arcade-api/repository"
+	"arcade-api/session"
+	"arcade-api/stream"
 	"arcade-api/utils"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log"
+	"math"
 	"net/http"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
-var (
-	startTime = time.Now()
-	
-	// Sample game data
-	games = []models.Game{
-		{ID: 1, Name: "Snake", Category: "classic", Difficulty: "easy", HighScore: 1250},
-		{ID: 2, Name: "Tetris", Category: "puzzle", Difficulty: "medium", HighScore: 8900},
-		{ID: 3, Name: "Pac-Man", Category: "arcade", Difficulty: "medium", HighScore: 15600},
-		{ID: 4, Name: "Space Invaders", Category: "shooter", Difficulty: "hard", HighScore: 23400},
-		{ID: 5, Name: "Pong", Category: "classic", Difficulty: "easy", HighScore: 21},
-	}
-	
-	// Sample leaderboard data
-	leaderboard = []models.LeaderboardEntry{
-		{Player: "Player1", Game: "Snake", Score: 1250, Date: "2024-01-15"},
-		{Player: "GameMaster", Game: "Tetris", Score: 8900, Date: "2024-01-14"},
-		{Player: "ArcadeKing", Game: "Pac-Man", Score: 15600, Date: "2024-01-13"},
-	}
+var startTime = time.Now()
+
+const (
+	// defaultSessionSeconds limits sessions of games without a MaxSessionSeconds rule
+	defaultSessionSeconds = 2 * 60 * 60
+	// sessionGraceSeconds allows for the delay between a game ending and its
+	// score arriving
+	sessionGraceSeconds = 30
 )
 
+// Handler serves the arcade API from a repository
+type Handler struct {
+	repo repository.Repository
+	// location is the time zone daily, weekly and monthly leaderboards roll
+	// over in
+	location *time.Location
+	// hub carries submitted scores to leaderboard streams
+	hub *stream.Hub
+}
+
+// New returns a Handler backed by repo, with leaderboard periods starting at
+// midnight in location
+func New(repo repository.Repository, location *time.Location) *Handler {
+	return &Handler{repo: repo, location: location, hub: stream.NewHub()}
+}
+
 // GetServiceInfo returns service information
-func GetServiceInfo(c *gin.Context) {
+func (h *Handler) GetServiceInfo(c *gin.Context) {
 	info := models.ServiceInfo{
 		Service:     config.AppConfig.AppName,
 		Version:     config.AppConfig.Version,
@@ -42,10 +58,30 @@ func GetServiceInfo(c *gin.Context) {
 		Endpoints: []string{
 			"GET /api/v1/ - Service info",
 			"GET /api/v1/health - Health check",
-			"GET /api/v1/games - List all games",
+			"GET /api/v1/games - List enabled games",
 			"GET /api/v1/games/:id - Get specific game",
-			"GET /api/v1/leaderboard - Get leaderboard",
-			"POST /api/v1/score - Submit new score",
+			"POST /api/v1/games/:id/sessions - Start a game session (authenticated)",
+			"GET /api/v1/leaderboard - Get leaderboard (period=daily|weekly|monthly|alltime, offset=periods back, up to 52)",
+			"GET /api/v1/leaderboard/archive - Get winners of past periods",
+			"GET /api/v1/leaderboard/around/:player - Get the scores ranked around a player's best",
+			"GET /api/v1/leaderboard/stream - Stream a game's leaderboard as Server-Sent Events",
+			"GET /api/v1/leaderboard/ws - Stream a game's leaderboard over WebSocket",
+			"POST /api/v1/score - Submit new score (authenticated)",
+			"POST /api/v1/auth/register - Register a player",
+			"POST /api/v1/auth/login - Log in",
+			"POST /api/v1/auth/guest - Play as a guest",
+			"POST /api/v1/auth/refresh - Exchange a refresh token",
+			"POST /api/v1/auth/logout - Revoke a refresh token",
+			"GET /api/v1/players/me - Get the current player (authenticated)",
+			"PATCH /api/v1/players/me - Change display name (authenticated)",
+			"POST /api/v1/players/me/claim - Claim a guest's scores (authenticated)",
+			"GET /api/v1/players/:id - Get a player's profile",
+			"GET /api/v1/players/:id/stats - Get a player's statistics and recent activity",
+			"GET /api/v1/players/:id/achievements - Get a player's achievements and progress",
+			"GET /api/v1/admin/games - List all games, including disabled ones (admin)",
+			"POST /api/v1/admin/games - Add a game (admin)",
+			"PUT /api/v1/admin/games/:id - Replace a game's details (admin)",
+			"DELETE /api/v1/admin/games/:id - Delete a game without scores (admin)",
 		},
 	}
 
@@ -53,9 +89,15 @@ func GetServiceInfo(c *gin.Context) {
 }
 
 // HealthCheck returns the health status of the service
-func HealthCheck(c *gin.Context) {
+func (h *Handler) HealthCheck(c *gin.Context) {
 	uptime := time.Since(startTime)
-	
+
+	games, err := h.repo.ListGames(repository.GameFilter{})
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to load games", err.Error())
+		return
+	}
+
 	health := models.HealthResponse{
 		Status:     "healthy",
 		Service:    config.AppConfig.AppName,
@@ -69,38 +111,28 @@ func HealthCheck(c *gin.Context) {
 }
 
 // GetGames returns all games with optional filtering
-func GetGames(c *gin.Context) {
-	category := c.Query("category")
-	difficulty := c.Query("difficulty")
-	
-	filteredGames := games
+func (h *Handler) GetGames(c *gin.Context) {
+	filter := repository.GameFilter{
+		Category:   c.Query("category"),
+		Difficulty: c.Query("difficulty"),
+	}
 	filters := make(map[string]interface{})
-	
-	if category != "" {
-		filters["category"] = category
-		var filtered []models.Game
-		for _, game := range filteredGames {
-			if strings.EqualFold(game.Category, category) {
-				filtered = append(filtered, game)
-			}
-		}
-		filteredGames = filtered
-	}
-	
-	if difficulty != "" {
-		filters["difficulty"] = difficulty
-		var filtered []models.Game
-		for _, game := range filteredGames {
-			if strings.EqualFold(game.Difficulty, difficulty) {
-				filtered = append(filtered, game)
-			}
-		}
-		filteredGames = filtered
+	if filter.Category != "" {
+		filters["category"] = filter.Category
+	}
+	if filter.Difficulty != "" {
+		filters["difficulty"] = filter.Difficulty
+	}
+
+	games, err := h.repo.ListGames(filter)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to load games", err.Error())
+		return
 	}
 
 	response := models.GamesResponse{
-		Games:   filteredGames,
-		Total:   len(filteredGames),
+		Games:   games,
+		Total:   len(games),
 		Filters: filters,
 	}
 
@@ -108,7 +140,7 @@ func GetGames(c *gin.Context) {
 }
 
 // GetGameByID returns a specific game with its leaderboard
-func GetGameByID(c *gin.Context) {
+func (h *Handler) GetGameByID(c *gin.Context) {
 	idParam := c.Param("id")
 	gameID, err := strconv.Atoi(idParam)
 	if err != nil {
@@ -116,164 +148,442 @@ func GetGameByID(c *gin.Context) {
 		return
 	}
 
-	var game *models.Game
-	for _, g := range games {
-		if g.ID == gameID {
-			game = &g
-			break
-		}
+	// Disabled games are hidden from players
+	game, err := h.repo.GetGame(gameID)
+	if errors.Is(err, repository.ErrNotFound) || err == nil && !game.Enabled {
+		utils.SendErrorResponse(c, http.StatusNotFound, "Game not found", "No game found with the specified ID")
+		return
+	}
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to load game", err.Error())
+		return
+	}
+
+	// Get the game's top 10 scores
+	gameLeaderboard, err := h.repo.ListScores(repository.ScoreFilter{GameID: game.ID, Limit: 10})
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to load leaderboard", err.Error())
+		return
+	}
+
+	response := models.GameDetailResponse{
+		Game:        game,
+		Leaderboard: gameLeaderboard,
+	}
+
+	utils.SendSuccessResponse(c, response, "Game details retrieved successfully")
+}
+
+// StartSession starts a game session for the authenticated player, returning
+// the signed token a score for it must be submitted with
+func (h *Handler) StartSession(c *gin.Context) {
+	gameID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid game ID", err.Error())
+		return
+	}
+
+	// A claimed guest's access token outlives the guest
+	player, ok := h.currentPlayer(c)
+	if !ok {
+		return
 	}
 
-	if game == nil {
+	// Disabled games are hidden from players
+	game, err := h.repo.GetGame(gameID)
+	if errors.Is(err, repository.ErrNotFound) || err == nil && !game.Enabled {
 		utils.SendErrorResponse(c, http.StatusNotFound, "Game not found", "No game found with the specified ID")
 		return
 	}
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to load game", err.Error())
+		return
+	}
 
-	// Get game-specific leaderboard
-	var gameLeaderboard []models.LeaderboardEntry
-	for _, entry := range leaderboard {
-		if entry.Game == game.Name {
-			gameLeaderboard = append(gameLeaderboard, entry)
-		}
+	var seed [8]byte
+	if _, err := rand.Read(seed[:]); err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to start session", err.Error())
+		return
 	}
 
-	// Sort by score (descending) and limit to top 10
-	sort.Slice(gameLeaderboard, func(i, j int) bool {
-		return gameLeaderboard[i].Score > gameLeaderboard[j].Score
+	sessionSeconds := game.Rules.MaxSessionSeconds
+	if sessionSeconds == 0 {
+		sessionSeconds = defaultSessionSeconds
+	}
+	startedAt := time.Now()
+	claims := session.Claims{
+		ID:        uuid.New().String(),
+		GameID:    game.ID,
+		PlayerID:  player.ID,
+		Seed:      int64(binary.BigEndian.Uint64(seed[:]) >> 1),
+		StartedAt: startedAt.Unix(),
+		ExpiresAt: startedAt.Unix() + int64(sessionSeconds+sessionGraceSeconds),
+	}
+	token, err := session.Issue(claims, []byte(config.AppConfig.SessionSecret))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to start session", err.Error())
+		return
+	}
+	err = h.repo.RecordSession(repository.SessionStart{
+		ID:        claims.ID,
+		PlayerID:  claims.PlayerID,
+		GameID:    game.ID,
+		Date:      startedAt.In(h.location).Format("2006-01-02"),
+		StartedAt: claims.Started(),
 	})
-	if len(gameLeaderboard) > 10 {
-		gameLeaderboard = gameLeaderboard[:10]
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to start session", err.Error())
+		return
 	}
 
-	response := models.GameDetailResponse{
-		Game:        *game,
-		Leaderboard: gameLeaderboard,
+	response := models.GameSessionResponse{
+		SessionToken: token,
+		SessionID:    claims.ID,
+		GameID:       game.ID,
+		Seed:         claims.Seed,
+		StartedAt:    claims.Started(),
+		ExpiresAt:    claims.Expires(),
+		Rules:        game.Rules,
+		Achievements: h.checkAchievements(claims.PlayerID),
 	}
 
-	utils.SendSuccessResponse(c, response, "Game details retrieved successfully")
+	utils.SendSuccessResponse(c, response, "Game session started successfully")
 }
 
-// GetLeaderboard returns the leaderboard with optional filtering
-func GetLeaderboard(c *gin.Context) {
+// GetLeaderboard returns the leaderboard with optional filtering. period
+// selects a daily, weekly or monthly leaderboard and offset how many periods
+// back, so period=weekly&offset=1 is last week's.
+func (h *Handler) GetLeaderboard(c *gin.Context) {
 	game := c.Query("game")
 	limitParam := c.DefaultQuery("limit", "10")
-	
+
 	limit, err := strconv.Atoi(limitParam)
 	if err != nil || limit <= 0 {
 		limit = 10
 	}
 
-	filteredLeaderboard := leaderboard
+	period, err := leaderboard.ParsePeriod(c.Query("period"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid period", err.Error())
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 || offset > maxArchivePeriods {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid offset",
+			fmt.Sprintf("offset must be a whole number of periods back, from 0 for the current one to %d", maxArchivePeriods))
+		return
+	}
+	gameID, ok := h.filterGame(c, game)
+	if !ok {
+		return
+	}
+
 	filters := make(map[string]interface{})
-	
 	if game != "" {
 		filters["game"] = game
-		var filtered []models.LeaderboardEntry
-		for _, entry := range filteredLeaderboard {
-			if strings.EqualFold(entry.Game, game) {
-				filtered = append(filtered, entry)
-			}
-		}
-		filteredLeaderboard = filtered
+	}
+	filters["limit"] = limit
+	filters["period"] = period
+	if offset > 0 {
+		filters["offset"] = offset
 	}
 
-	// Sort by score (descending)
-	sort.Slice(filteredLeaderboard, func(i, j int) bool {
-		return filteredLeaderboard[i].Score > filteredLeaderboard[j].Score
-	})
-
-	// Apply limit
-	if len(filteredLeaderboard) > limit {
-		filteredLeaderboard = filteredLeaderboard[:limit]
+	window := period.WindowAt(time.Now(), h.location)
+	for i := 0; i < offset; i++ {
+		window = window.Previous()
 	}
 
-	filters["limit"] = limit
+	scores, err := h.repo.ListScores(repository.ScoreFilter{GameID: gameID, From: window.Start, To: window.End, Limit: limit})
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to load leaderboard", err.Error())
+		return
+	}
 
 	response := models.LeaderboardResponse{
-		Leaderboard: filteredLeaderboard,
-		Total:       len(filteredLeaderboard),
+		Leaderboard: scores,
+		Total:       len(scores),
 		Filters:     filters,
 	}
+	if period != leaderboard.AllTime {
+		response.Window = leaderboardWindow(window)
+	}
+
+	utils.SendSuccessResponse(c, response, "Leaderboard retrieved successfully")
+}
+
+const (
+	defaultAroundCount = 5
+	maxAroundCount     = 50
+)
+
+// GetLeaderboardAround returns a player's best score in a game with the n
+// scores ranked immediately above and below it on the all-time leaderboard.
+// The player is a player ID, or the name of a score from before player accounts.
+func (h *Handler) GetLeaderboardAround(c *gin.Context) {
+	gameName := c.Query("game")
+	if gameName == "" {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Missing game", "ranks are per game; pass game=<name or slug>")
+		return
+	}
+	n, err := strconv.Atoi(c.DefaultQuery("n", strconv.Itoa(defaultAroundCount)))
+	if err != nil || n < 0 {
+		n = defaultAroundCount
+	}
+	n = min(n, maxAroundCount)
+
+	game, err := h.repo.FindGame(gameName)
+	if errors.Is(err, repository.ErrNotFound) {
+		h.sendInvalidGame(c)
+		return
+	}
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to load game", err.Error())
+		return
+	}
+
+	player := c.Param("player")
+	scores, rank, err := h.repo.ScoresAround(game.ID, player, n)
+	if errors.Is(err, repository.ErrNotFound) {
+		utils.SendErrorResponse(c, http.StatusNotFound, "Player not ranked", "the player has no score in "+game.Name)
+		return
+	}
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to load leaderboard", err.Error())
+		return
+	}
+
+	response := models.LeaderboardAroundResponse{
+		Player:      player,
+		Game:        game.Name,
+		Rank:        rank,
+		Leaderboard: scores,
+		Total:       len(scores),
+	}
 
 	utils.SendSuccessResponse(c, response, "Leaderboard retrieved successfully")
 }
 
-// SubmitScore handles score submission
-func SubmitScore(c *gin.Context) {
+// maxArchivePeriods limits how many past periods one archive request covers,
+// and how far back one leaderboard request can reach
+const maxArchivePeriods = 52
+
+// GetLeaderboardArchive returns the top scores of each of the most recent
+// completed daily, weekly or monthly periods, most recent first
+func (h *Handler) GetLeaderboardArchive(c *gin.Context) {
+	game := c.Query("game")
+
+	period, err := leaderboard.ParsePeriod(c.DefaultQuery("period", string(leaderboard.Weekly)))
+	if err == nil && period == leaderboard.AllTime {
+		err = errors.New("the all-time leaderboard has no past periods")
+	}
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid period", err.Error())
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "3"))
+	if err != nil || limit <= 0 {
+		limit = 3
+	}
+	periods, err := strconv.Atoi(c.DefaultQuery("periods", "4"))
+	if err != nil || periods <= 0 {
+		periods = 4
+	}
+	periods = min(periods, maxArchivePeriods)
+	gameID, ok := h.filterGame(c, game)
+	if !ok {
+		return
+	}
+
+	filters := map[string]interface{}{
+		"period":  period,
+		"periods": periods,
+		"limit":   limit,
+	}
+	if game != "" {
+		filters["game"] = game
+	}
+
+	archive := []models.ArchivedLeaderboard{}
+	window := period.WindowAt(time.Now(), h.location)
+	for i := 0; i < periods; i++ {
+		window = window.Previous()
+		winners, err := h.repo.ListScores(repository.ScoreFilter{GameID: gameID, From: window.Start, To: window.End, Limit: limit})
+		if err != nil {
+			utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to load leaderboard", err.Error())
+			return
+		}
+		archive = append(archive, models.ArchivedLeaderboard{
+			LeaderboardWindow: *leaderboardWindow(window),
+			Winners:           winners,
+		})
+	}
+
+	response := models.LeaderboardArchiveResponse{
+		Archive: archive,
+		Total:   len(archive),
+		Filters: filters,
+	}
+
+	utils.SendSuccessResponse(c, response, "Leaderboard archive retrieved successfully")
+}
+
+// filterGame returns the ID of the game a leaderboard is filtered to by name or
+// slug, or zero for every game when name is empty. It responds with an error
+// and reports false if there is no such game.
+func (h *Handler) filterGame(c *gin.Context, name string) (int, bool) {
+	if name == "" {
+		return 0, true
+	}
+	game, err := h.repo.FindGame(name)
+	if errors.Is(err, repository.ErrNotFound) {
+		h.sendInvalidGame(c)
+		return 0, false
+	}
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to load game", err.Error())
+		return 0, false
+	}
+	return game.ID, true
+}
+
+func leaderboardWindow(window leaderboard.Window) *models.LeaderboardWindow {
+	return &models.LeaderboardWindow{
+		Period: string(window.Period),
+		Start:  window.Start,
+		End:    window.End,
+	}
+}
+
+// SubmitScore handles score submission by the authenticated player
+func (h *Handler) SubmitScore(c *gin.Context) {
 	var req models.ScoreSubmission
-	
+
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
 		return
 	}
 
-	// Validate game exists
-	var gameExists *models.Game
-	for _, g := range games {
-		if strings.EqualFold(g.Name, req.Game) {
-			gameExists = &g
-			break
-		}
+	player, ok := h.currentPlayer(c)
+	if !ok {
+		return
 	}
 
-	if gameExists == nil {
-		var gameNames []string
-		for _, g := range games {
-			gameNames = append(gameNames, g.Name)
-		}
-		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid game", "Available games: "+strings.Join(gameNames, ", "))
+	// Validate game exists and can be played
+	game, err := h.repo.FindGame(req.Game)
+	if errors.Is(err, repository.ErrNotFound) || err == nil && !game.Enabled {
+		h.sendInvalidGame(c)
 		return
 	}
-
-	// Create new leaderboard entry
-	newEntry := models.LeaderboardEntry{
-		Player: strings.TrimSpace(req.Player),
-		Game:   gameExists.Name,
-		Score:  req.Score,
-		Date:   time.Now().Format("2006-01-02"),
-	}
-
-	// Add to leaderboard
-	leaderboard = append(leaderboard, newEntry)
-
-	// Update high score if necessary
-	isNewHighScore := req.Score > gameExists.HighScore
-	if isNewHighScore {
-		for i := range games {
-			if games[i].ID == gameExists.ID {
-				games[i].HighScore = req.Score
-				break
-			}
-		}
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to load game", err.Error())
+		return
 	}
 
-	// Calculate rank
-	var gameLeaderboard []models.LeaderboardEntry
-	for _, entry := range leaderboard {
-		if entry.Game == gameExists.Name {
-			gameLeaderboard = append(gameLeaderboard, entry)
-		}
+	// Reject scores without a valid, unused session or beyond the game's limits
+	claims, rejection := h.checkSession(req, game, player.ID)
+	if rejection != nil {
+		utils.SendErrorResponse(c, rejection.status, rejection.message, rejection.detail)
+		return
 	}
 
-	sort.Slice(gameLeaderboard, func(i, j int) bool {
-		return gameLeaderboard[i].Score > gameLeaderboard[j].Score
-	})
+	// Create new leaderboard entry. Storage keeps whole seconds, so the time is
+	// truncated here for the entry to match what is stored.
+	submittedAt := time.Now().Truncate(time.Second)
+	newEntry := models.LeaderboardEntry{
+		Player:      player.DisplayName,
+		PlayerID:    player.ID,
+		Game:        game.Name,
+		Score:       req.Score,
+		Date:        submittedAt.In(h.location).Format("2006-01-02"),
+		SubmittedAt: submittedAt,
+	}
 
-	rank := 1
-	for i, entry := range gameLeaderboard {
-		if entry.Player == newEntry.Player && entry.Score == newEntry.Score && entry.Date == newEntry.Date {
-			rank = i + 1
-			break
-		}
+	// Add to leaderboard, updating the high score if necessary
+	result, err := h.repo.AddScore(game.ID, newEntry)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to save score", err.Error())
+		return
+	}
+	// Play time is a statistic, so failing to record it does not fail the score
+	if err := h.repo.EndSession(claims.ID, submittedAt); err != nil {
+		log.Printf("Failed to end session %s: %v", claims.ID, err)
 	}
+	h.hub.Publish(game.ID, stream.Score{
+		Entry:             result.Entry,
+		Rank:              result.Rank,
+		IsNewHighScore:    result.IsNewHighScore,
+		PreviousHighScore: game.HighScore,
+	})
 
 	response := models.ScoreResponse{
 		Message:        "Score submitted successfully",
-		Entry:          newEntry,
-		Rank:           rank,
-		IsNewHighScore: isNewHighScore,
-		GameHighScore:  gameExists.HighScore,
+		Entry:          result.Entry,
+		Rank:           result.Rank,
+		IsNewHighScore: result.IsNewHighScore,
+		GameHighScore:  result.Game.HighScore,
+		Achievements:   h.checkAchievements(player.ID),
 	}
 
 	utils.SendSuccessResponse(c, response, "Score submitted successfully")
 }
+
+// scoreRejection describes why a score submission was refused
+type scoreRejection struct {
+	status  int
+	message string
+	detail  string
+}
+
+// checkSession verifies that the submission's session token was issued for the
+// game and player, marks the session used and applies the game's plausibility
+// rules, returning the session's claims. A session is used up by any correctly
+// signed submission, so an implausible score cannot be retried lower.
+func (h *Handler) checkSession(req models.ScoreSubmission, game models.Game, playerID string) (session.Claims, *scoreRejection) {
+	now := time.Now()
+	claims, err := session.Verify(req.SessionToken, []byte(config.AppConfig.SessionSecret), now)
+	if errors.Is(err, session.ErrExpired) {
+		return claims, &scoreRejection{http.StatusBadRequest, "Session expired",
+			"the session expired at " + claims.Expires().UTC().Format(time.RFC3339) + "; start a new one"}
+	}
+	if err != nil {
+		return claims, &scoreRejection{http.StatusBadRequest, "Invalid session", err.Error()}
+	}
+	if claims.GameID != game.ID {
+		return claims, &scoreRejection{http.StatusBadRequest, "Invalid session", "the session was started for a different game"}
+	}
+	if claims.PlayerID != playerID {
+		return claims, &scoreRejection{http.StatusForbidden, "Invalid session", "the session was started by a different player"}
+	}
+
+	err = h.repo.UseSession(claims.ID, claims.Expires())
+	if errors.Is(err, repository.ErrSessionUsed) {
+		return claims, &scoreRejection{http.StatusConflict, "Session already used", "each session can submit one score"}
+	}
+	if err != nil {
+		return claims, &scoreRejection{http.StatusInternalServerError, "Failed to record session", err.Error()}
+	}
+
+	// Count at least one second so a score submitted instantly is still judged
+	elapsed := math.Max(now.Sub(claims.Started()).Seconds(), 1)
+	if limit := game.Rules.MaxPointsPerSecond; limit > 0 && float64(req.Score) > limit*elapsed {
+		return claims, &scoreRejection{http.StatusUnprocessableEntity, "Implausible score",
+			fmt.Sprintf("%d points in %.0f seconds exceeds %s's limit of %g points per second", req.Score, elapsed, game.Name, limit)}
+	}
+	return claims, nil
+}
+
+// sendInvalidGame rejects a request naming an unknown game, listing the
+// available ones
+func (h *Handler) sendInvalidGame(c *gin.Context) {
+	games, err := h.repo.ListGames(repository.GameFilter{})
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to load games", err.Error())
+		return
+	}
+
+	var gameNames []string
+	for _, g := range games {
+		gameNames = append(gameNames, g.Name)
+	}
+	utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid game", "Available games: "+strings.Join(gameNames, ", "))
+}
@@ -0,0 +1,438 @@
+package handlers
+
+import (
+	"arcade-api/auth"
+	"arcade-api/config"
+	"arcade-api/middleware"
+	"arcade-api/models"
+	"arcade-api/repository"
+	"arcade-api/utils"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	accessTokenTTL       = 15 * time.Minute
+	refreshTokenTTL      = 30 * 24 * time.Hour
+	maxDisplayNameLength = 32
+)
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{3,32}$`)
+
+// Register creates a player account and logs it in
+func (h *Handler) Register(c *gin.Context) {
+	var req models.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+	if !usernamePattern.MatchString(req.Username) {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid username",
+			"usernames are 3 to 32 letters, digits, '.', '_' or '-'")
+		return
+	}
+
+	displayName := req.DisplayName
+	if strings.TrimSpace(displayName) == "" {
+		displayName = req.Username
+	}
+	displayName, err := cleanDisplayName(displayName)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid display name", err.Error())
+		return
+	}
+
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to register player", err.Error())
+		return
+	}
+	player := models.Player{
+		ID:           uuid.New().String(),
+		Username:     req.Username,
+		DisplayName:  displayName,
+		CreatedAt:    time.Now().UTC(),
+		PasswordHash: hash,
+	}
+	err = h.repo.CreatePlayer(player)
+	if errors.Is(err, repository.ErrUsernameTaken) {
+		utils.SendErrorResponse(c, http.StatusConflict, "Username taken", "choose another username")
+		return
+	}
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to register player", err.Error())
+		return
+	}
+
+	h.sendTokens(c, player, "Player registered successfully")
+}
+
+// Login exchanges a username and password for tokens
+func (h *Handler) Login(c *gin.Context) {
+	var req models.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	// An unknown username is checked against an empty hash, which takes as long
+	// as a wrong password, so responses do not reveal which usernames exist
+	player, err := h.repo.FindPlayer(req.Username)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to log in", err.Error())
+		return
+	}
+	ok, err := auth.CheckPassword(req.Password, player.PasswordHash)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to log in", err.Error())
+		return
+	}
+	if !ok {
+		utils.SendErrorResponse(c, http.StatusUnauthorized, "Invalid credentials", "the username or password is incorrect")
+		return
+	}
+
+	h.sendTokens(c, player, "Logged in successfully")
+}
+
+// PlayAsGuest creates an anonymous guest player. A registered player can later
+// claim the guest's scores with its refresh token.
+func (h *Handler) PlayAsGuest(c *gin.Context) {
+	var req models.GuestRequest
+	// The body is optional
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	id := uuid.New().String()
+	displayName := req.DisplayName
+	if strings.TrimSpace(displayName) == "" {
+		displayName = "Guest-" + id[:6]
+	}
+	displayName, err := cleanDisplayName(displayName)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid display name", err.Error())
+		return
+	}
+
+	player := models.Player{
+		ID:          id,
+		DisplayName: displayName,
+		Guest:       true,
+		CreatedAt:   time.Now().UTC(),
+	}
+	if err := h.repo.CreatePlayer(player); err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to create guest", err.Error())
+		return
+	}
+
+	h.sendTokens(c, player, "Guest player created successfully")
+}
+
+// RefreshTokens exchanges a refresh token for a new access and refresh token.
+// Each refresh token works once.
+func (h *Handler) RefreshTokens(c *gin.Context) {
+	var req models.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	playerID, err := h.repo.UseRefreshToken(auth.HashRefreshToken(req.RefreshToken))
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to refresh tokens", err.Error())
+		return
+	}
+	player, err := h.repo.GetPlayer(playerID)
+	if errors.Is(err, repository.ErrNotFound) {
+		utils.SendErrorResponse(c, http.StatusUnauthorized, "Invalid refresh token",
+			"the refresh token is unknown, expired or already used; log in again")
+		return
+	}
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to refresh tokens", err.Error())
+		return
+	}
+
+	h.sendTokens(c, player, "Tokens refreshed successfully")
+}
+
+// Logout revokes a refresh token. Access tokens already issued stay valid
+// until they expire.
+func (h *Handler) Logout(c *gin.Context) {
+	var req models.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	_, err := h.repo.UseRefreshToken(auth.HashRefreshToken(req.RefreshToken))
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to log out", err.Error())
+		return
+	}
+
+	utils.SendSuccessResponse(c, nil, "Logged out successfully")
+}
+
+// GetCurrentPlayer returns the authenticated player
+func (h *Handler) GetCurrentPlayer(c *gin.Context) {
+	player, ok := h.currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	utils.SendSuccessResponse(c, player, "Player retrieved successfully")
+}
+
+const (
+	defaultStatsHistory = 20
+	maxStatsHistory     = 100
+	defaultStatsRecent  = 10
+	maxStatsRecent      = 50
+)
+
+// GetPlayerProfile returns a player's public profile. Usernames are used to
+// log in, so only the display name is shown.
+func (h *Handler) GetPlayerProfile(c *gin.Context) {
+	player, ok := h.findPlayer(c)
+	if !ok {
+		return
+	}
+
+	activity, err := h.repo.PlayerActivity(player.ID)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to load player", err.Error())
+		return
+	}
+	unlocked, err := h.repo.ListAchievements(player.ID)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to load player", err.Error())
+		return
+	}
+
+	player.Username = ""
+	response := models.PlayerProfileResponse{
+		Player:               player,
+		GamesPlayed:          activity.Games,
+		ScoresSubmitted:      activity.Scores,
+		AchievementsUnlocked: len(unlocked),
+	}
+
+	utils.SendSuccessResponse(c, response, "Player retrieved successfully")
+}
+
+// GetPlayerStats returns a player's statistics: per game their best score with
+// its rank and percentile, play time and latest scores, and their most recent
+// sessions and scores. history and recent set how many of each to return.
+func (h *Handler) GetPlayerStats(c *gin.Context) {
+	player, ok := h.findPlayer(c)
+	if !ok {
+		return
+	}
+	history, err := strconv.Atoi(c.DefaultQuery("history", strconv.Itoa(defaultStatsHistory)))
+	if err != nil || history < 0 {
+		history = defaultStatsHistory
+	}
+	recent, err := strconv.Atoi(c.DefaultQuery("recent", strconv.Itoa(defaultStatsRecent)))
+	if err != nil || recent < 0 {
+		recent = defaultStatsRecent
+	}
+
+	stats, err := h.repo.PlayerStats(player.ID, min(history, maxStatsHistory), min(recent, maxStatsRecent))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to load statistics", err.Error())
+		return
+	}
+	stats.PlayerID = player.ID
+	stats.DisplayName = player.DisplayName
+	for i := range stats.Games {
+		stats.Games[i].Percentile = percentile(stats.Games[i].Rank, stats.Games[i].TotalScores)
+	}
+
+	utils.SendSuccessResponse(c, stats, "Player statistics retrieved successfully")
+}
+
+// percentile returns the percentage of a game's other scores that a score at
+// rank ranks above, or zero for no rank
+func percentile(rank, total int) float64 {
+	if rank == 0 {
+		return 0
+	}
+	if total <= 1 {
+		return 100
+	}
+	return utils.RoundToTwoDecimals(float64(total-rank) / float64(total-1) * 100)
+}
+
+// UpdateCurrentPlayer changes the authenticated player's display name. Their
+// existing scores are listed under the new name.
+func (h *Handler) UpdateCurrentPlayer(c *gin.Context) {
+	var req models.UpdatePlayerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+	displayName, err := cleanDisplayName(req.DisplayName)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid display name", err.Error())
+		return
+	}
+
+	player, err := h.repo.RenamePlayer(middleware.Player(c).PlayerID, displayName)
+	if errors.Is(err, repository.ErrNotFound) {
+		sendPlayerGone(c)
+		return
+	}
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to update player", err.Error())
+		return
+	}
+
+	utils.SendSuccessResponse(c, player, "Player updated successfully")
+}
+
+// ClaimGuestScores moves a guest's scores to the authenticated registered
+// player. The guest's refresh token proves the caller played as the guest; the
+// guest is deleted afterwards.
+func (h *Handler) ClaimGuestScores(c *gin.Context) {
+	if middleware.Player(c).Guest {
+		utils.SendErrorResponse(c, http.StatusForbidden, "Registration required",
+			"register or log in before claiming a guest's scores")
+		return
+	}
+
+	var req models.ClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	player, ok := h.currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	guestID, err := h.repo.UseRefreshToken(auth.HashRefreshToken(req.GuestRefreshToken))
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to claim scores", err.Error())
+		return
+	}
+	claimed, err := h.repo.ClaimGuest(guestID, player.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid guest token",
+			"the token is unknown, expired, already used or does not belong to a guest")
+		return
+	}
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to claim scores", err.Error())
+		return
+	}
+
+	response := models.ClaimResponse{
+		Player:        player,
+		ClaimedScores: claimed,
+		Achievements:  h.checkAchievements(player.ID),
+	}
+
+	utils.SendSuccessResponse(c, response, "Guest scores claimed successfully")
+}
+
+// currentPlayer loads the authenticated player. A token can outlive its player
+// when a guest is claimed, in which case it responds 401 and reports false.
+func (h *Handler) currentPlayer(c *gin.Context) (models.Player, bool) {
+	player, err := h.repo.GetPlayer(middleware.Player(c).PlayerID)
+	if errors.Is(err, repository.ErrNotFound) {
+		sendPlayerGone(c)
+		return models.Player{}, false
+	}
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to load player", err.Error())
+		return models.Player{}, false
+	}
+	return player, true
+}
+
+// findPlayer loads the player named by the id path parameter, responding 404
+// and reporting false if there is none
+func (h *Handler) findPlayer(c *gin.Context) (models.Player, bool) {
+	player, err := h.repo.GetPlayer(c.Param("id"))
+	if errors.Is(err, repository.ErrNotFound) {
+		utils.SendErrorResponse(c, http.StatusNotFound, "Player not found", "No player found with the specified ID")
+		return models.Player{}, false
+	}
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to load player", err.Error())
+		return models.Player{}, false
+	}
+	return player, true
+}
+
+func sendPlayerGone(c *gin.Context) {
+	utils.SendErrorResponse(c, http.StatusUnauthorized, "Invalid access token", "the player no longer exists; log in again")
+}
+
+// sendTokens issues a new access and refresh token to player
+func (h *Handler) sendTokens(c *gin.Context, player models.Player, message string) {
+	now := time.Now()
+	claims := auth.Claims{
+		PlayerID:  player.ID,
+		Guest:     player.Guest,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(accessTokenTTL).Unix(),
+	}
+	accessToken, err := auth.IssueAccessToken(claims, []byte(config.AppConfig.AuthSecret))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to issue tokens", err.Error())
+		return
+	}
+
+	refreshToken, hash, err := auth.NewRefreshToken()
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to issue tokens", err.Error())
+		return
+	}
+	refreshExpiresAt := now.Add(refreshTokenTTL)
+	if err := h.repo.SaveRefreshToken(hash, player.ID, refreshExpiresAt); err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to issue tokens", err.Error())
+		return
+	}
+
+	response := models.AuthResponse{
+		Player:                player,
+		TokenType:             "Bearer",
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  claims.Expires(),
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: refreshExpiresAt,
+	}
+
+	utils.SendSuccessResponse(c, response, message)
+}
+
+// cleanDisplayName trims a display name and checks it is 1 to
+// maxDisplayNameLength printable characters
+func cleanDisplayName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if length := utf8.RuneCountInString(name); length == 0 || length > maxDisplayNameLength {
+		return "", fmt.Errorf("display names are 1 to %d characters", maxDisplayNameLength)
+	}
+	for _, r := range name {
+		if !unicode.IsPrint(r) {
+			return "", errors.New("display names can only contain printable characters")
+		}
+	}
+	return name, nil
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"arcade-api/repository"
+	"arcade-api/stream"
+	"arcade-api/utils"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	defaultStreamTop = 10
+	maxStreamTop     = 100
+	// streamBuffer is how many scores a subscriber can fall behind by before
+	// it is dropped, so a slow client never holds up score submission
+	streamBuffer = 64
+	// streamHeartbeat is how often an idle stream is pinged to keep proxies
+	// from closing it and to notice clients that have gone away
+	streamHeartbeat = 15 * time.Second
+	wsWriteTimeout  = 10 * time.Second
+)
+
+var upgrader = websocket.Upgrader{
+	// Any origin may connect, matching the API's CORS policy
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// StreamLeaderboard streams a game's leaderboard as Server-Sent Events: a
+// snapshot of the top entries, then entry, ranks and highscore events as
+// scores are submitted. A lagged event means the client fell behind and should
+// reconnect.
+func (h *Handler) StreamLeaderboard(c *gin.Context) {
+	sub, feed, ok := h.subscribe(c)
+	if !ok {
+		return
+	}
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	c.SSEvent("snapshot", feed.Snapshot())
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case score, ok := <-sub.Scores():
+			if !ok {
+				if sub.Lagged() {
+					c.SSEvent("lagged", feed.Lagged())
+					c.Writer.Flush()
+				}
+				return
+			}
+			for _, event := range feed.Apply(score) {
+				c.SSEvent(event.Type, event)
+			}
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			// A comment line, which clients ignore
+			fmt.Fprint(c.Writer, ": heartbeat\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
+
+// StreamLeaderboardWebSocket streams the same events as StreamLeaderboard over
+// a WebSocket, one JSON message per event. Messages from the client are ignored.
+func (h *Handler) StreamLeaderboardWebSocket(c *gin.Context) {
+	if !websocket.IsWebSocketUpgrade(c.Request) {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "WebSocket required",
+			"connect with a WebSocket client, or use /leaderboard/stream for Server-Sent Events")
+		return
+	}
+
+	sub, feed, ok := h.subscribe(c)
+	if !ok {
+		return
+	}
+	defer sub.Close()
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already responded
+		return
+	}
+	defer conn.Close()
+
+	// Reading processes pings, pongs and close frames; an error means the
+	// client has gone away or stopped answering pings
+	gone := make(chan struct{})
+	go func() {
+		defer close(gone)
+		conn.SetReadLimit(512)
+		conn.SetReadDeadline(time.Now().Add(2 * streamHeartbeat))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(2 * streamHeartbeat))
+		})
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	send := func(event interface{}) bool {
+		conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+		return conn.WriteJSON(event) == nil
+	}
+	if !send(feed.Snapshot()) {
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-gone:
+			return
+		case score, ok := <-sub.Scores():
+			if !ok {
+				if sub.Lagged() && send(feed.Lagged()) {
+					conn.WriteControl(websocket.CloseMessage,
+						websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "fell behind; reconnect"),
+						time.Now().Add(wsWriteTimeout))
+				}
+				return
+			}
+			for _, event := range feed.Apply(score) {
+				if !send(event) {
+					return
+				}
+			}
+		case <-heartbeat.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// subscribe subscribes to the leaderboard of the game named in the request and
+// returns a feed starting from its current top entries. It responds with an
+// error and reports false if the request is invalid.
+func (h *Handler) subscribe(c *gin.Context) (*stream.Subscription, *stream.Feed, bool) {
+	gameName := c.Query("game")
+	if gameName == "" {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Missing game", "leaderboards are streamed per game; pass game=<name or slug>")
+		return nil, nil, false
+	}
+	top, err := strconv.Atoi(c.DefaultQuery("top", strconv.Itoa(defaultStreamTop)))
+	if err != nil || top <= 0 {
+		top = defaultStreamTop
+	}
+	top = min(top, maxStreamTop)
+
+	game, err := h.repo.FindGame(gameName)
+	if errors.Is(err, repository.ErrNotFound) {
+		h.sendInvalidGame(c)
+		return nil, nil, false
+	}
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to load game", err.Error())
+		return nil, nil, false
+	}
+
+	// Subscribing before reading the snapshot means no score falls between
+	// them; scores in both are recognised by the feed and skipped
+	sub := h.hub.Subscribe(game.ID, streamBuffer)
+	snapshot, err := h.repo.ListScores(repository.ScoreFilter{GameID: game.ID, Limit: top})
+	if err != nil {
+		sub.Close()
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to load leaderboard", err.Error())
+		return nil, nil, false
+	}
+	return sub, stream.NewFeed(game.Name, snapshot, top), true
+}
@@ -0,0 +1,79 @@
+package leaderboard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Period is the span of time a leaderboard covers
+type Period string
+
+const (
+	Daily   Period = "daily"
+	Weekly  Period = "weekly"
+	Monthly Period = "monthly"
+	AllTime Period = "alltime"
+)
+
+// Periods lists the periods ParsePeriod accepts
+var Periods = []Period{Daily, Weekly, Monthly, AllTime}
+
+// ParsePeriod returns the named period, matched case-insensitively. An empty
+// name means AllTime.
+func ParsePeriod(name string) (Period, error) {
+	if name == "" {
+		return AllTime, nil
+	}
+	for _, period := range Periods {
+		if strings.EqualFold(name, string(period)) {
+			return period, nil
+		}
+	}
+	names := make([]string, len(Periods))
+	for i, period := range Periods {
+		names[i] = string(period)
+	}
+	return "", fmt.Errorf("unknown period %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// Window is one occurrence of a period: scores submitted from Start up to but
+// not including End. Both are zero for AllTime.
+type Window struct {
+	Period Period
+	Start  time.Time
+	End    time.Time
+}
+
+// WindowAt returns the window of period containing t, with boundaries at
+// midnight in loc. Weeks start on Monday.
+func (p Period) WindowAt(t time.Time, loc *time.Location) Window {
+	t = t.In(loc)
+	year, month, day := t.Date()
+	var start, end time.Time
+	switch p {
+	case Daily:
+		start = time.Date(year, month, day, 0, 0, 0, 0, loc)
+		end = start.AddDate(0, 0, 1)
+	case Weekly:
+		sinceMonday := (int(t.Weekday()) + 6) % 7
+		start = time.Date(year, month, day-sinceMonday, 0, 0, 0, 0, loc)
+		end = start.AddDate(0, 0, 7)
+	case Monthly:
+		start = time.Date(year, month, 1, 0, 0, 0, 0, loc)
+		end = start.AddDate(0, 1, 0)
+	default:
+		return Window{Period: AllTime}
+	}
+	return Window{Period: p, Start: start, End: end}
+}
+
+// Previous returns the window before w. The previous AllTime window is AllTime.
+func (w Window) Previous() Window {
+	if w.Period == AllTime {
+		return w
+	}
+	// The instant before Start lies in the previous window however long it is,
+	// so days lengthened or shortened by daylight saving are handled
+	return w.Period.WindowAt(w.Start.Add(-time.Nanosecond), w.Start.Location())
+}
@@ -0,0 +1,142 @@
+package leaderboard
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestWindowAt(t *testing.T) {
+	newYork := mustLoad(t, "America/New_York")
+	at := func(loc *time.Location, year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, loc)
+	}
+
+	cases := []struct {
+		name       string
+		period     Period
+		t          time.Time
+		loc        *time.Location
+		start, end time.Time
+		hours      float64
+	}{
+		// Daylight saving began on 10 March 2024 and ended on 3 November 2024
+		{"day clocks go forward", Daily, at(newYork, 2024, 3, 10, 12), newYork,
+			at(newYork, 2024, 3, 10, 0), at(newYork, 2024, 3, 11, 0), 23},
+		{"day clocks go back", Daily, at(newYork, 2024, 11, 3, 12), newYork,
+			at(newYork, 2024, 11, 3, 0), at(newYork, 2024, 11, 4, 0), 25},
+		{"week clocks go forward", Weekly, at(newYork, 2024, 3, 10, 12), newYork,
+			at(newYork, 2024, 3, 4, 0), at(newYork, 2024, 3, 11, 0), 7*24 - 1},
+		{"utc instant on the previous local day", Daily, time.Date(2024, 3, 1, 3, 0, 0, 0, time.UTC), newYork,
+			at(newYork, 2024, 2, 29, 0), at(newYork, 2024, 3, 1, 0), 24},
+
+		// ISO weeks start on Monday, so a week can span two years
+		{"week across new year from december", Weekly, at(time.UTC, 2024, 12, 31, 9), time.UTC,
+			at(time.UTC, 2024, 12, 30, 0), at(time.UTC, 2025, 1, 6, 0), 7 * 24},
+		{"week across new year from january", Weekly, at(time.UTC, 2025, 1, 5, 23), time.UTC,
+			at(time.UTC, 2024, 12, 30, 0), at(time.UTC, 2025, 1, 6, 0), 7 * 24},
+		{"sunday ends the week", Weekly, at(time.UTC, 2024, 12, 29, 23), time.UTC,
+			at(time.UTC, 2024, 12, 23, 0), at(time.UTC, 2024, 12, 30, 0), 7 * 24},
+		{"monday midnight starts the week", Weekly, at(time.UTC, 2024, 12, 30, 0), time.UTC,
+			at(time.UTC, 2024, 12, 30, 0), at(time.UTC, 2025, 1, 6, 0), 7 * 24},
+
+		{"last second of a 31-day month", Monthly, time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC), time.UTC,
+			at(time.UTC, 2024, 1, 1, 0), at(time.UTC, 2024, 2, 1, 0), 31 * 24},
+		{"leap february", Monthly, at(time.UTC, 2024, 2, 29, 12), time.UTC,
+			at(time.UTC, 2024, 2, 1, 0), at(time.UTC, 2024, 3, 1, 0), 29 * 24},
+		{"common february", Monthly, at(time.UTC, 2023, 2, 28, 12), time.UTC,
+			at(time.UTC, 2023, 2, 1, 0), at(time.UTC, 2023, 3, 1, 0), 28 * 24},
+		{"december", Monthly, at(time.UTC, 2024, 12, 31, 12), time.UTC,
+			at(time.UTC, 2024, 12, 1, 0), at(time.UTC, 2025, 1, 1, 0), 31 * 24},
+		{"month clocks go back", Monthly, at(newYork, 2024, 11, 30, 12), newYork,
+			at(newYork, 2024, 11, 1, 0), at(newYork, 2024, 12, 1, 0), 30*24 + 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := tc.period.WindowAt(tc.t, tc.loc)
+			if !w.Start.Equal(tc.start) || !w.End.Equal(tc.end) {
+				t.Fatalf("window %v to %v, want %v to %v", w.Start, w.End, tc.start, tc.end)
+			}
+			if hours := w.End.Sub(w.Start).Hours(); hours != tc.hours {
+				t.Fatalf("window lasts %g hours, want %g", hours, tc.hours)
+			}
+			if w.Start.After(tc.t) || !tc.t.Before(w.End) {
+				t.Fatalf("window %v to %v does not contain %v", w.Start, w.End, tc.t)
+			}
+		})
+	}
+
+	if w := AllTime.WindowAt(time.Now(), newYork); !w.Start.IsZero() || !w.End.IsZero() {
+		t.Fatalf("all-time window %v to %v, want no bounds", w.Start, w.End)
+	}
+}
+
+func TestWindowPrevious(t *testing.T) {
+	newYork := mustLoad(t, "America/New_York")
+	midnight := func(loc *time.Location, year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, loc)
+	}
+
+	cases := []struct {
+		name   string
+		period Period
+		from   time.Time
+		loc    *time.Location
+		// starts lists the starts of the windows reached by stepping back
+		starts []time.Time
+	}{
+		{"days across clocks going forward", Daily, midnight(newYork, 2024, 3, 11).Add(time.Hour), newYork,
+			[]time.Time{midnight(newYork, 2024, 3, 10), midnight(newYork, 2024, 3, 9)}},
+		{"days across clocks going back", Daily, midnight(newYork, 2024, 11, 4).Add(time.Hour), newYork,
+			[]time.Time{midnight(newYork, 2024, 11, 3), midnight(newYork, 2024, 11, 2)}},
+		{"weeks into the previous year", Weekly, midnight(time.UTC, 2025, 1, 8), time.UTC,
+			[]time.Time{midnight(time.UTC, 2024, 12, 30), midnight(time.UTC, 2024, 12, 23)}},
+		{"months through a leap february", Monthly, midnight(time.UTC, 2024, 3, 31), time.UTC,
+			[]time.Time{midnight(time.UTC, 2024, 2, 1), midnight(time.UTC, 2024, 1, 1), midnight(time.UTC, 2023, 12, 1)}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := tc.period.WindowAt(tc.from, tc.loc)
+			for i, start := range tc.starts {
+				next := w
+				w = w.Previous()
+				if !w.Start.Equal(start) {
+					t.Fatalf("step %d: window starts %v, want %v", i+1, w.Start, start)
+				}
+				// Windows tile time with no gap or overlap
+				if !w.End.Equal(next.Start) {
+					t.Fatalf("step %d: window ends %v, want %v", i+1, w.End, next.Start)
+				}
+			}
+		})
+	}
+
+	if w := AllTime.WindowAt(time.Now(), time.UTC).Previous(); w.Period != AllTime || !w.Start.IsZero() {
+		t.Fatalf("previous all-time window %+v, want all time", w)
+	}
+}
+
+func TestWindowPreviousReachesTheArchiveLimit(t *testing.T) {
+	newYork := mustLoad(t, "America/New_York")
+	// 52 weeks back from a week in late March crosses both daylight saving
+	// changes of the year before
+	w := Weekly.WindowAt(time.Date(2025, 3, 26, 12, 0, 0, 0, newYork), newYork)
+	for i := 0; i < 52; i++ {
+		w = w.Previous()
+	}
+	want := time.Date(2024, 3, 25, 0, 0, 0, 0, newYork)
+	if !w.Start.Equal(want) || w.Start.Weekday() != time.Monday {
+		t.Fatalf("52 weeks back starts %v, want %v", w.Start, want)
+	}
+}
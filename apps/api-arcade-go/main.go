@@ -2,9 +2,16 @@ package main
 
 import (
 	"arcade-api/config"
+	"arcade-api/handlers"
+	"arcade-api/repository"
 	"arcade-api/routes"
 	"arcade-api/server"
+	"crypto/rand"
+	"encoding/hex"
 	"log"
+	"time"
+	// Embed the time zone database so LEADERBOARD_TIMEZONE works on hosts without one
+	_ "time/tzdata"
 )
 
 func main() {
@@ -13,12 +20,39 @@ func main() {
 	// Load configuration
 	config.LoadConfig()
 
+	ensureSecret(&config.AppConfig.SessionSecret, "SESSION_SECRET", "game sessions do not survive a restart")
+	ensureSecret(&config.AppConfig.AuthSecret, "AUTH_SECRET", "players must log in again after a restart")
+
 	// Initialize server
 	server.InitServer()
 
+	// Create the game and score repository
+	repo, err := repository.Open(config.AppConfig.StorageBackend, config.AppConfig.DatabasePath)
+	if err != nil {
+		log.Fatalf("Failed to open %s storage: %v", config.AppConfig.StorageBackend, err)
+	}
+
+	location, err := time.LoadLocation(config.AppConfig.LeaderboardTimeZone)
+	if err != nil {
+		log.Fatalf("Invalid LEADERBOARD_TIMEZONE: %v", err)
+	}
+
 	// Register routes
-	routes.RegisterRoutes()
+	routes.RegisterRoutes(handlers.New(repo, location))
 
 	// Start server
 	server.StartServer()
 }
+
+// ensureSecret fills an unset secret with random bytes, warning what that costs
+func ensureSecret(secret *string, name, consequence string) {
+	if *secret != "" {
+		return
+	}
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		log.Fatalf("Failed to generate %s: %v", name, err)
+	}
+	*secret = hex.EncodeToString(random)
+	log.Printf("%s is not set: using a random secret, so %s", name, consequence)
+}
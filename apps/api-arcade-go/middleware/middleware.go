@@ -1,6 +1,13 @@
 package middleware
 
 import (
+	"arcade-api/auth"
+	"arcade-api/utils"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -18,3 +25,54 @@ func RequestID() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// Authenticate requires a valid access token in the Authorization header and
+// makes its claims available to handlers through Player
+func Authenticate(secret []byte) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scheme, token, _ := strings.Cut(c.GetHeader("Authorization"), " ")
+		if !strings.EqualFold(scheme, "Bearer") || token == "" {
+			c.Header("WWW-Authenticate", "Bearer")
+			utils.SendErrorResponse(c, http.StatusUnauthorized, "Authentication required", "send an access token as Authorization: Bearer <token>")
+			c.Abort()
+			return
+		}
+
+		claims, err := auth.VerifyAccessToken(token, secret, time.Now())
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			utils.SendErrorResponse(c, http.StatusUnauthorized, "Invalid access token", err.Error())
+			c.Abort()
+			return
+		}
+
+		c.Set("Player", claims)
+		c.Next()
+	}
+}
+
+// RequireAdmin requires token, the administrator token, in the Authorization
+// header. With no token configured every request is refused.
+func RequireAdmin(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			utils.SendErrorResponse(c, http.StatusForbidden, "Administration disabled", "set ADMIN_TOKEN to enable the admin API")
+			c.Abort()
+			return
+		}
+
+		scheme, sent, _ := strings.Cut(c.GetHeader("Authorization"), " ")
+		if !strings.EqualFold(scheme, "Bearer") || subtle.ConstantTimeCompare([]byte(sent), []byte(token)) != 1 {
+			c.Header("WWW-Authenticate", "Bearer")
+			utils.SendErrorResponse(c, http.StatusUnauthorized, "Admin token required", "send the admin token as Authorization: Bearer <token>")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// Player returns the claims of the access token Authenticate accepted
+func Player(c *gin.Context) auth.Claims {
+	return c.MustGet("Player").(auth.Claims)
+}
@@ -2,37 +2,237 @@ package models
 
 import "time"
 
-// Game represents a game in the arcade
+// Game represents a game in the arcade. Slug is the game's ID in the arcade
+// frontend, and Thumbnail a path to its image there. Disabled games are hidden
+// from the catalog and cannot be played, but keep their leaderboards.
 type Game struct {
-	ID         int    `json:"id"`
-	Name       string `json:"name"`
-	Category   string `json:"category"`
-	Difficulty string `json:"difficulty"`
-	HighScore  int    `json:"highScore"`
+	ID          int          `json:"id"`
+	Slug        string       `json:"slug"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Controls    []string     `json:"controls"`
+	Category    string       `json:"category"`
+	Difficulty  string       `json:"difficulty"`
+	HighScore   int          `json:"highScore"`
+	Rules       ScoringRules `json:"rules"`
+	Enabled     bool         `json:"enabled"`
+	Thumbnail   string       `json:"thumbnail"`
 }
 
-// LeaderboardEntry represents a leaderboard entry
+// ScoringRules are the plausibility limits a submitted score must satisfy.
+// Zero means no limit.
+type ScoringRules struct {
+	MaxPointsPerSecond float64 `json:"maxPointsPerSecond" binding:"gte=0"`
+	MaxSessionSeconds  int     `json:"maxSessionSeconds" binding:"gte=0"`
+}
+
+// GameRequest represents an administrator creating or replacing a game.
+// Enabled defaults to true; the high score is kept by the server.
+type GameRequest struct {
+	Slug        string       `json:"slug" binding:"required,max=64"`
+	Name        string       `json:"name" binding:"required,max=64"`
+	Description string       `json:"description" binding:"max=500"`
+	Controls    []string     `json:"controls" binding:"max=10,dive,required,max=32"`
+	Category    string       `json:"category" binding:"required,max=32"`
+	Difficulty  string       `json:"difficulty" binding:"required,max=32"`
+	Rules       ScoringRules `json:"rules"`
+	Enabled     *bool        `json:"enabled"`
+	Thumbnail   string       `json:"thumbnail" binding:"max=256"`
+}
+
+// LeaderboardEntry represents a leaderboard entry. Player is the current
+// display name of the player with PlayerID; scores from before player accounts
+// have no PlayerID and keep the name they were submitted under. Date is the
+// day of SubmittedAt in the leaderboard time zone. Rank is only set where a
+// listing does not already give each entry's position.
 type LeaderboardEntry struct {
-	Player string `json:"player"`
-	Game   string `json:"game"`
-	Score  int    `json:"score"`
-	Date   string `json:"date"`
+	ID          int64     `json:"id"`
+	Rank        int       `json:"rank,omitempty"`
+	Player      string    `json:"player"`
+	PlayerID    string    `json:"playerId,omitempty"`
+	Game        string    `json:"game"`
+	Score       int       `json:"score"`
+	Date        string    `json:"date"`
+	SubmittedAt time.Time `json:"submittedAt"`
 }
 
-// ScoreSubmission represents a score submission request
+// ScoreSubmission represents a score submission request. The score is credited
+// to the authenticated player; SessionToken is the token issued when they
+// started the game.
 type ScoreSubmission struct {
-	Player string `json:"player" binding:"required"`
-	Game   string `json:"game" binding:"required"`
-	Score  int    `json:"score" binding:"required,gte=0"`
+	Game         string `json:"game" binding:"required"`
+	Score        int    `json:"score" binding:"required,gte=0"`
+	SessionToken string `json:"sessionToken" binding:"required"`
+}
+
+// Player is a registered or guest player. Scores belong to the ID, so a player
+// keeps their history when they change display name. Guests have no username
+// or password.
+type Player struct {
+	ID           string    `json:"id"`
+	Username     string    `json:"username,omitempty"`
+	DisplayName  string    `json:"displayName"`
+	Guest        bool      `json:"guest"`
+	CreatedAt    time.Time `json:"createdAt"`
+	PasswordHash string    `json:"-"`
+}
+
+// RegisterRequest represents a player registration request. DisplayName
+// defaults to the username.
+type RegisterRequest struct {
+	Username    string `json:"username" binding:"required"`
+	Password    string `json:"password" binding:"required,min=8,max=128"`
+	DisplayName string `json:"displayName"`
 }
 
-// ScoreResponse represents a score submission response
+// LoginRequest represents a login request
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// GuestRequest represents a request to play as a guest
+type GuestRequest struct {
+	DisplayName string `json:"displayName"`
+}
+
+// RefreshRequest represents a request to exchange or revoke a refresh token
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+// UpdatePlayerRequest represents a display name change
+type UpdatePlayerRequest struct {
+	DisplayName string `json:"displayName" binding:"required"`
+}
+
+// ClaimRequest represents a registered player claiming a guest's scores.
+// GuestRefreshToken proves the caller played as that guest.
+type ClaimRequest struct {
+	GuestRefreshToken string `json:"guestRefreshToken" binding:"required"`
+}
+
+// AuthResponse represents the tokens issued on registration, login or refresh.
+// The access token is sent as a Bearer token; the refresh token can be
+// exchanged once for a new pair.
+type AuthResponse struct {
+	Player                Player    `json:"player"`
+	TokenType             string    `json:"tokenType"`
+	AccessToken           string    `json:"accessToken"`
+	AccessTokenExpiresAt  time.Time `json:"accessTokenExpiresAt"`
+	RefreshToken          string    `json:"refreshToken"`
+	RefreshTokenExpiresAt time.Time `json:"refreshTokenExpiresAt"`
+}
+
+// ClaimResponse represents the result of claiming a guest's scores
+type ClaimResponse struct {
+	Player        Player        `json:"player"`
+	ClaimedScores int           `json:"claimedScores"`
+	Achievements  []Achievement `json:"achievements,omitempty"`
+}
+
+// GameSessionResponse represents a newly started game session. The token must
+// be sent back with the score before ExpiresAt.
+type GameSessionResponse struct {
+	SessionToken string       `json:"sessionToken"`
+	SessionID    string       `json:"sessionId"`
+	GameID       int          `json:"gameId"`
+	Seed         int64        `json:"seed"`
+	StartedAt    time.Time    `json:"startedAt"`
+	ExpiresAt    time.Time    `json:"expiresAt"`
+	Rules        ScoringRules `json:"rules"`
+	// Achievements are those starting the session unlocked
+	Achievements []Achievement `json:"achievements,omitempty"`
+}
+
+// ScoreResponse represents a score submission response. Achievements are those
+// the score unlocked.
 type ScoreResponse struct {
-	Message       string           `json:"message"`
-	Entry         LeaderboardEntry `json:"entry"`
-	Rank          int              `json:"rank"`
-	IsNewHighScore bool            `json:"isNewHighScore"`
-	GameHighScore int              `json:"gameHighScore"`
+	Message        string           `json:"message"`
+	Entry          LeaderboardEntry `json:"entry"`
+	Rank           int              `json:"rank"`
+	IsNewHighScore bool             `json:"isNewHighScore"`
+	GameHighScore  int              `json:"gameHighScore"`
+	Achievements   []Achievement    `json:"achievements,omitempty"`
+}
+
+// Achievement is an achievement as it stands for one player. Progress counts
+// toward Target, and UnlockedAt is set once the achievement is unlocked.
+type Achievement struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Unlocked    bool       `json:"unlocked"`
+	UnlockedAt  *time.Time `json:"unlockedAt,omitempty"`
+	Progress    int        `json:"progress"`
+	Target      int        `json:"target"`
+}
+
+// PlayerProfileResponse represents a player's public profile with a summary of
+// their play
+type PlayerProfileResponse struct {
+	Player               Player `json:"player"`
+	GamesPlayed          int    `json:"gamesPlayed"`
+	ScoresSubmitted      int    `json:"scoresSubmitted"`
+	AchievementsUnlocked int    `json:"achievementsUnlocked"`
+}
+
+// PlayerStats represents a player's statistics, overall and per game. Play
+// time counts sessions a score was submitted for, from start to submission.
+type PlayerStats struct {
+	PlayerID        string            `json:"playerId"`
+	DisplayName     string            `json:"displayName"`
+	GamesPlayed     int               `json:"gamesPlayed"`
+	SessionsStarted int               `json:"sessionsStarted"`
+	ScoresSubmitted int               `json:"scoresSubmitted"`
+	PlayTimeSeconds int64             `json:"playTimeSeconds"`
+	Games           []PlayerGameStats `json:"games"`
+	RecentActivity  []ActivityEvent   `json:"recentActivity"`
+}
+
+// PlayerGameStats is a player's record in one game. Rank is the all-time rank
+// of BestScore among the game's TotalScores, and Percentile the share of the
+// game's other scores it ranks above; without a score they are zero. History
+// holds the player's latest scores, oldest first.
+type PlayerGameStats struct {
+	GameID          int          `json:"gameId"`
+	Game            string       `json:"game"`
+	Slug            string       `json:"slug"`
+	SessionsStarted int          `json:"sessionsStarted"`
+	ScoresSubmitted int          `json:"scoresSubmitted"`
+	BestScore       int          `json:"bestScore"`
+	Rank            int          `json:"rank"`
+	TotalScores     int          `json:"totalScores"`
+	Percentile      float64      `json:"percentile"`
+	PlayTimeSeconds int64        `json:"playTimeSeconds"`
+	LastPlayedAt    time.Time    `json:"lastPlayedAt"`
+	History         []ScorePoint `json:"history"`
+}
+
+// ScorePoint is one score in a player's history
+type ScorePoint struct {
+	EntryID     int64     `json:"entryId"`
+	Score       int       `json:"score"`
+	SubmittedAt time.Time `json:"submittedAt"`
+}
+
+// ActivityEvent is a session a player started or a score they submitted.
+// Score is only set for a score.
+type ActivityEvent struct {
+	Type  string    `json:"type"`
+	Game  string    `json:"game"`
+	Score *int      `json:"score,omitempty"`
+	At    time.Time `json:"at"`
+}
+
+// PlayerAchievementsResponse represents every achievement with a player's
+// progress toward it
+type PlayerAchievementsResponse struct {
+	PlayerID     string        `json:"playerId"`
+	DisplayName  string        `json:"displayName"`
+	Achievements []Achievement `json:"achievements"`
+	Unlocked     int           `json:"unlocked"`
+	Total        int           `json:"total"`
 }
 
 // GamesResponse represents a games list response
@@ -42,13 +242,85 @@ type GamesResponse struct {
 	Filters map[string]interface{} `json:"filters,omitempty"`
 }
 
-// LeaderboardResponse represents a leaderboard response
+// LeaderboardResponse represents a leaderboard response. Window is the period
+// the leaderboard covers, absent for the all-time leaderboard.
 type LeaderboardResponse struct {
 	Leaderboard []LeaderboardEntry     `json:"leaderboard"`
 	Total       int                    `json:"total"`
+	Window      *LeaderboardWindow     `json:"window,omitempty"`
 	Filters     map[string]interface{} `json:"filters,omitempty"`
 }
 
+// LeaderboardAroundResponse represents the scores ranked around a player's best
+// score in a game on the all-time leaderboard
+type LeaderboardAroundResponse struct {
+	Player      string             `json:"player"`
+	Game        string             `json:"game"`
+	Rank        int                `json:"rank"`
+	Leaderboard []LeaderboardEntry `json:"leaderboard"`
+	Total       int                `json:"total"`
+}
+
+// LeaderboardEvent represents one message on a game's leaderboard stream.
+// Type is snapshot, entry, ranks, highscore or lagged, and Data holds a
+// LeaderboardResponse, ScoreEvent, RankChangesEvent or HighScoreEvent in turn;
+// lagged has no data.
+type LeaderboardEvent struct {
+	Type string      `json:"type"`
+	Game string      `json:"game"`
+	Data interface{} `json:"data,omitempty"`
+}
+
+// ScoreEvent represents a newly submitted score and its all-time rank
+type ScoreEvent struct {
+	Entry LeaderboardEntry `json:"entry"`
+	Rank  int              `json:"rank"`
+}
+
+// RankChangesEvent represents how a new score moved the entries of the
+// subscriber's top N
+type RankChangesEvent struct {
+	Changes []RankChange `json:"changes"`
+}
+
+// RankChange represents one entry moving within the top N. From is 0 for an
+// entry entering the top N and To is 0 for one pushed out of it.
+type RankChange struct {
+	EntryID int64  `json:"entryId"`
+	Player  string `json:"player"`
+	Score   int    `json:"score"`
+	From    int    `json:"from"`
+	To      int    `json:"to"`
+}
+
+// HighScoreEvent represents a score beating the game's high score
+type HighScoreEvent struct {
+	Entry             LeaderboardEntry `json:"entry"`
+	PreviousHighScore int              `json:"previousHighScore"`
+}
+
+// LeaderboardWindow represents one daily, weekly or monthly period: scores
+// submitted from Start up to but not including End
+type LeaderboardWindow struct {
+	Period string    `json:"period"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+}
+
+// ArchivedLeaderboard represents the winners of a past period
+type ArchivedLeaderboard struct {
+	LeaderboardWindow
+	Winners []LeaderboardEntry `json:"winners"`
+}
+
+// LeaderboardArchiveResponse represents the winners of recent past periods,
+// most recent first
+type LeaderboardArchiveResponse struct {
+	Archive []ArchivedLeaderboard  `json:"archive"`
+	Total   int                    `json:"total"`
+	Filters map[string]interface{} `json:"filters,omitempty"`
+}
+
 // GameDetailResponse represents a detailed game response
 type GameDetailResponse struct {
 	Game        Game                 `json:"game"`
@@ -0,0 +1,149 @@
+package ranking
+
+import "math/rand"
+
+// Item is one score in an Index. Seq breaks ties between equal scores: the
+// lower sequence number, the earlier submission, ranks higher.
+type Item struct {
+	Score int
+	Seq   int64
+}
+
+// before reports whether a ranks ahead of b
+func (a Item) before(b Item) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	return a.Seq < b.Seq
+}
+
+const (
+	maxLevel = 32
+	// Each node is promoted to the next level with probability 1/promoteOneIn
+	promoteOneIn = 4
+)
+
+type node struct {
+	item Item
+	next []*node
+	// span[i] is how many positions next[i] is ahead of this node, or the
+	// distance past the last item when next[i] is nil
+	span []int
+}
+
+// Index holds items in rank order: highest score first, earlier submissions
+// first among equal scores. It is an indexable skip list, so inserting,
+// finding an item's rank and finding the item at a rank take O(log n)
+// expected time. An Index is not safe for concurrent use.
+type Index struct {
+	head   *node
+	level  int
+	length int
+	rand   *rand.Rand
+}
+
+// NewIndex returns an empty index
+func NewIndex() *Index {
+	return &Index{
+		head:  &node{next: make([]*node, maxLevel), span: make([]int, maxLevel)},
+		level: 1,
+		// Levels only need to be unpredictable enough to stay balanced
+		rand: rand.New(rand.NewSource(1)),
+	}
+}
+
+// Len returns the number of items in the index
+func (x *Index) Len() int {
+	return x.length
+}
+
+// Insert adds item, whose Seq must not already be in the index, and returns
+// its 1-based rank
+func (x *Index) Insert(item Item) int {
+	// update[i] is the last node on level i ahead of item; rank[i] is its position
+	var update [maxLevel]*node
+	var rank [maxLevel]int
+	n := x.head
+	for i := x.level - 1; i >= 0; i-- {
+		if i < x.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for n.next[i] != nil && n.next[i].item.before(item) {
+			rank[i] += n.span[i]
+			n = n.next[i]
+		}
+		update[i] = n
+	}
+
+	level := x.randomLevel()
+	for i := x.level; i < level; i++ {
+		update[i] = x.head
+		update[i].span[i] = x.length
+	}
+	x.level = max(x.level, level)
+
+	created := &node{item: item, next: make([]*node, level), span: make([]int, level)}
+	for i := 0; i < level; i++ {
+		created.next[i] = update[i].next[i]
+		update[i].next[i] = created
+		created.span[i] = update[i].span[i] - (rank[0] - rank[i])
+		update[i].span[i] = rank[0] - rank[i] + 1
+	}
+	// Links on higher levels now pass over one more item
+	for i := level; i < x.level; i++ {
+		update[i].span[i]++
+	}
+	x.length++
+	return rank[0] + 1
+}
+
+// Rank returns the 1-based rank of item, or 0 if it is not in the index
+func (x *Index) Rank(item Item) int {
+	rank := 0
+	n := x.head
+	for i := x.level - 1; i >= 0; i-- {
+		for n.next[i] != nil && !item.before(n.next[i].item) {
+			rank += n.span[i]
+			n = n.next[i]
+		}
+		if n != x.head && n.item == item {
+			return rank
+		}
+	}
+	return 0
+}
+
+// Range returns up to count items in rank order, starting at the 1-based rank
+// from
+func (x *Index) Range(from, count int) []Item {
+	items := []Item{}
+	if from < 1 || count <= 0 {
+		return items
+	}
+
+	// Walk down to the node at rank from, skipping as far as each level allows
+	traversed := 0
+	n := x.head
+	for i := x.level - 1; i >= 0 && traversed < from; i-- {
+		for n.next[i] != nil && traversed+n.span[i] <= from {
+			traversed += n.span[i]
+			n = n.next[i]
+		}
+	}
+	if traversed != from {
+		return items
+	}
+
+	for ; n != nil && len(items) < count; n = n.next[0] {
+		items = append(items, n.item)
+	}
+	return items
+}
+
+func (x *Index) randomLevel() int {
+	level := 1
+	for level < maxLevel && x.rand.Intn(promoteOneIn) == 0 {
+		level++
+	}
+	return level
+}
@@ -0,0 +1,159 @@
+package ranking
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// sorted returns items in rank order, the order an Index must hold them in
+func sorted(items []Item) []Item {
+	ordered := append([]Item{}, items...)
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].before(ordered[j]) })
+	return ordered
+}
+
+// checkIndex compares every rank and the full range of x against want, which
+// must be in rank order
+func checkIndex(t *testing.T, x *Index, want []Item) {
+	t.Helper()
+	if x.Len() != len(want) {
+		t.Fatalf("Len() = %d, want %d", x.Len(), len(want))
+	}
+	for i, item := range want {
+		if rank := x.Rank(item); rank != i+1 {
+			t.Fatalf("Rank(%+v) = %d, want %d", item, rank, i+1)
+		}
+	}
+	if got := x.Range(1, len(want)+1); !reflect.DeepEqual(got, append([]Item{}, want...)) {
+		t.Fatalf("Range(1, %d) = %+v, want %+v", len(want)+1, got, want)
+	}
+}
+
+func TestIndexRanksTiesBySubmissionOrder(t *testing.T) {
+	cases := []struct {
+		name  string
+		items []Item // in insertion order
+		ranks []int  // rank Insert returns for each
+	}{
+		{"descending", []Item{{300, 1}, {200, 2}, {100, 3}}, []int{1, 2, 3}},
+		{"ascending", []Item{{100, 1}, {200, 2}, {300, 3}}, []int{1, 1, 1}},
+		{"equal scores", []Item{{100, 1}, {100, 2}, {100, 3}}, []int{1, 2, 3}},
+		{"tie with a higher score between", []Item{{100, 1}, {200, 2}, {100, 3}}, []int{1, 1, 3}},
+		// Seeded scores can be inserted after newer ones; the lower Seq still wins
+		{"earlier seq inserted later", []Item{{100, 5}, {100, 2}, {50, 1}, {100, 9}}, []int{1, 1, 3, 3}},
+		{"zero scores", []Item{{0, 1}, {0, 2}, {10, 3}}, []int{1, 2, 1}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			x := NewIndex()
+			for i, item := range tc.items {
+				if rank := x.Insert(item); rank != tc.ranks[i] {
+					t.Fatalf("Insert(%+v) = %d, want %d", item, rank, tc.ranks[i])
+				}
+			}
+			checkIndex(t, x, sorted(tc.items))
+		})
+	}
+}
+
+func TestIndexRankOfMissingItem(t *testing.T) {
+	x := NewIndex()
+	if rank := x.Rank(Item{Score: 100, Seq: 1}); rank != 0 {
+		t.Fatalf("Rank on an empty index = %d, want 0", rank)
+	}
+	x.Insert(Item{Score: 100, Seq: 1})
+	x.Insert(Item{Score: 50, Seq: 2})
+
+	for _, item := range []Item{{100, 2}, {50, 1}, {75, 3}, {200, 4}, {0, 5}} {
+		if rank := x.Rank(item); rank != 0 {
+			t.Errorf("Rank(%+v) = %d, want 0", item, rank)
+		}
+	}
+}
+
+func TestIndexRanksDoNotDependOnInsertionOrder(t *testing.T) {
+	items := []Item{{500, 1}, {300, 2}, {300, 3}, {100, 4}, {300, 5}, {900, 6}, {100, 7}}
+	want := sorted(items)
+
+	r := rand.New(rand.NewSource(46))
+	for i := 0; i < 50; i++ {
+		x := NewIndex()
+		for _, j := range r.Perm(len(items)) {
+			x.Insert(items[j])
+		}
+		checkIndex(t, x, want)
+	}
+}
+
+func TestIndexRangeBounds(t *testing.T) {
+	x := NewIndex()
+	items := []Item{{50, 1}, {40, 2}, {30, 3}, {20, 4}, {10, 5}}
+	for _, item := range items {
+		x.Insert(item)
+	}
+
+	cases := []struct {
+		from, count int
+		want        []Item
+	}{
+		{1, 5, items},
+		{1, 2, items[:2]},
+		{2, 3, items[1:4]},
+		{4, 10, items[3:]},
+		{5, 1, items[4:]},
+		{6, 1, []Item{}},
+		{100, 5, []Item{}},
+		{0, 3, []Item{}},
+		{-1, 3, []Item{}},
+		{1, 0, []Item{}},
+		{3, -1, []Item{}},
+	}
+
+	for _, tc := range cases {
+		if got := x.Range(tc.from, tc.count); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Range(%d, %d) = %+v, want %+v", tc.from, tc.count, got, tc.want)
+		}
+	}
+	if got := NewIndex().Range(1, 1); len(got) != 0 {
+		t.Errorf("Range on an empty index = %+v, want none", got)
+	}
+}
+
+// TestIndexMatchesSortedSlice applies random inserts to an index and a sorted
+// slice and checks they always agree
+func TestIndexMatchesSortedSlice(t *testing.T) {
+	r := rand.New(rand.NewSource(46))
+	x := NewIndex()
+	var want []Item
+	seqs := make(map[int64]bool)
+
+	for op := 0; op < 2000; op++ {
+		// A narrow score range makes ties common; sequence numbers arrive out
+		// of order, as seeded scores can
+		item := Item{Score: r.Intn(50), Seq: int64(r.Intn(1 << 30))}
+		if seqs[item.Seq] {
+			continue
+		}
+		seqs[item.Seq] = true
+		want = sorted(append(want, item))
+		if rank := x.Insert(item); want[rank-1] != item {
+			t.Fatalf("op %d: Insert(%+v) = %d, where the slice has %+v", op, item, rank, want[rank-1])
+		}
+
+		if op%100 == 0 {
+			checkIndex(t, x, want)
+			from, count := 1+r.Intn(len(want)+2), r.Intn(20)
+			expected := []Item{}
+			if from <= len(want) && count > 0 {
+				expected = append(expected, want[from-1:min(from-1+count, len(want))]...)
+			}
+			if got := x.Range(from, count); !reflect.DeepEqual(got, expected) {
+				t.Fatalf("op %d: Range(%d, %d) = %+v, want %+v", op, from, count, got, expected)
+			}
+		}
+	}
+	checkIndex(t, x, want)
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"arcade-api/models"
+)
+
+// TestMemoryIsSafeForConcurrentUse is meant for go test -race: it reads and
+// writes one repository from many goroutines, then checks no score was lost
+func TestMemoryIsSafeForConcurrentUse(t *testing.T) {
+	repo := NewMemory(SeedGames(), SeedScores())
+	games, err := repo.ListGames(GameFilter{IncludeDisabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, err := repo.ListScores(ScoreFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const writers, scoresEach = 8, 50
+	var wg sync.WaitGroup
+	errs := make(chan error, 4*writers)
+	for w := 0; w < writers; w++ {
+		playerID := fmt.Sprintf("player-%d", w)
+		if err := repo.CreatePlayer(models.Player{ID: playerID, DisplayName: playerID}); err != nil {
+			t.Fatal(err)
+		}
+		game := games[w%len(games)]
+
+		wg.Add(4)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < scoresEach; i++ {
+				entry := models.LeaderboardEntry{Player: playerID, PlayerID: playerID, Score: i * 10, SubmittedAt: time.Now()}
+				if _, err := repo.AddScore(game.ID, entry); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < scoresEach; i++ {
+				if _, err := repo.ListScores(ScoreFilter{GameID: game.ID, Limit: 10}); err != nil {
+					errs <- err
+					return
+				}
+				if _, err := repo.ListScores(ScoreFilter{From: time.Now().Add(-time.Hour)}); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < scoresEach; i++ {
+				if _, err := repo.GetGame(game.ID); err != nil {
+					errs <- err
+					return
+				}
+				if _, err := repo.FindGame(game.Slug); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < scoresEach; i++ {
+				// The player may have no score yet
+				if _, _, err := repo.ScoresAround(game.ID, playerID, 2); err != nil && !errors.Is(err, ErrNotFound) {
+					errs <- err
+					return
+				}
+				if _, err := repo.PlayerStats(playerID, 5, 5); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+
+	after, err := repo.ListScores(ScoreFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := len(before) + writers*scoresEach; len(after) != want {
+		t.Fatalf("%d scores after concurrent submissions, want %d", len(after), want)
+	}
+	seen := make(map[int64]bool, len(after))
+	for _, entry := range after {
+		if seen[entry.ID] {
+			t.Fatalf("score ID %d given out twice", entry.ID)
+		}
+		seen[entry.ID] = true
+	}
+}
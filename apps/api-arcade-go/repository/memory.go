@@ -0,0 +1,678 @@
+package repository
+
+import (
+	"arcade-api/achievements"
+	"arcade-api/models"
+	"arcade-api/ranking"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// minSessionPrune is the number of remembered sessions below which expired
+// ones are not pruned
+const minSessionPrune = 1024
+
+// Memory is a Repository held in process memory. Data is lost on restart.
+type Memory struct {
+	mu    sync.RWMutex
+	games []models.Game
+	// scores holds every score in submission order; a score's ID is its
+	// position plus one
+	scores   []storedScore
+	rankings *rankings
+	// usedSessions maps used session IDs to when they expire
+	usedSessions map[string]time.Time
+	pruneAt      int
+	// sessions maps player IDs to the sessions they started, in order, and
+	// sessionEnds session IDs to when their score was submitted
+	sessions    map[string][]SessionStart
+	sessionEnds map[string]time.Time
+	// playerScores maps player IDs to the IDs of their scores, in order
+	playerScores map[string][]int64
+	// bestScores maps each player of a game to the ID of their best score in it
+	bestScores map[scoreOwner]int64
+
+	players map[string]models.Player
+	// usernames maps lowercased usernames to player IDs
+	usernames     map[string]string
+	refreshTokens map[string]refreshToken
+	refreshPrune  int
+	// achievements maps player IDs to when they unlocked each achievement
+	achievements map[string]map[string]time.Time
+}
+
+// storedScore is a score with the ID of its game, which names the game in
+// listings so scores follow it when it is renamed
+type storedScore struct {
+	models.LeaderboardEntry
+	gameID int
+}
+
+// scoreOwner identifies a player of a game: by player ID, or for scores from
+// before player accounts, by the name the score was submitted under
+type scoreOwner struct {
+	gameID   int
+	playerID string
+	name     string
+}
+
+// ownerOf returns the owner of a score of the game with ID gameID
+func ownerOf(entry models.LeaderboardEntry, gameID int) scoreOwner {
+	if entry.PlayerID != "" {
+		return scoreOwner{gameID: gameID, playerID: entry.PlayerID}
+	}
+	return scoreOwner{gameID: gameID, name: entry.Player}
+}
+
+type refreshToken struct {
+	playerID  string
+	expiresAt time.Time
+}
+
+// NewMemory returns an in-memory repository holding copies of games and scores
+func NewMemory(games []models.Game, scores []models.LeaderboardEntry) *Memory {
+	m := &Memory{
+		rankings: newRankings(),
+
+		usedSessions: make(map[string]time.Time),
+		pruneAt:      minSessionPrune,
+		sessions:     make(map[string][]SessionStart),
+		sessionEnds:  make(map[string]time.Time),
+		playerScores: make(map[string][]int64),
+		bestScores:   make(map[scoreOwner]int64),
+
+		players:       make(map[string]models.Player),
+		usernames:     make(map[string]string),
+		refreshTokens: make(map[string]refreshToken),
+		refreshPrune:  minSessionPrune,
+		achievements:  make(map[string]map[string]time.Time),
+	}
+	for _, game := range games {
+		m.games = append(m.games, copyGame(game))
+	}
+	for _, entry := range scores {
+		if game, ok := m.findGame(entry.Game); ok {
+			m.addScore(entry, game.ID)
+		}
+	}
+	return m
+}
+
+func (m *Memory) ListGames(filter GameFilter) ([]models.Game, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	games := []models.Game{}
+	for _, game := range m.games {
+		if !game.Enabled && !filter.IncludeDisabled {
+			continue
+		}
+		if filter.Category != "" && !strings.EqualFold(game.Category, filter.Category) {
+			continue
+		}
+		if filter.Difficulty != "" && !strings.EqualFold(game.Difficulty, filter.Difficulty) {
+			continue
+		}
+		games = append(games, copyGame(game))
+	}
+	sort.SliceStable(games, func(i, j int) bool {
+		return games[i].ID < games[j].ID
+	})
+	return games, nil
+}
+
+func (m *Memory) GetGame(id int) (models.Game, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if i, ok := m.gameIndex(id); ok {
+		return copyGame(m.games[i]), nil
+	}
+	return models.Game{}, ErrNotFound
+}
+
+func (m *Memory) FindGame(name string) (models.Game, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if game, ok := m.findGame(name); ok {
+		return copyGame(*game), nil
+	}
+	return models.Game{}, ErrNotFound
+}
+
+// findGame returns the game with the given name or slug, matched
+// case-insensitively. The caller must hold m.mu.
+func (m *Memory) findGame(name string) (*models.Game, bool) {
+	for i := range m.games {
+		if strings.EqualFold(m.games[i].Name, name) || strings.EqualFold(m.games[i].Slug, name) {
+			return &m.games[i], true
+		}
+	}
+	return nil, false
+}
+
+// gameIndex returns the position in m.games of the game with the given ID. The
+// caller must hold m.mu.
+func (m *Memory) gameIndex(id int) (int, bool) {
+	for i := range m.games {
+		if m.games[i].ID == id {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// gameExists reports whether a game other than the one with ID except has the
+// name or slug of game as its name or slug, so that every name and slug finds
+// one game. The caller must hold m.mu.
+func (m *Memory) gameExists(game models.Game, except int) bool {
+	for _, other := range m.games {
+		if other.ID == except {
+			continue
+		}
+		for _, taken := range []string{other.Name, other.Slug} {
+			if strings.EqualFold(taken, game.Name) || strings.EqualFold(taken, game.Slug) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (m *Memory) CreateGame(game models.Game) (models.Game, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.gameExists(game, 0) {
+		return models.Game{}, ErrGameExists
+	}
+	game.ID = 1
+	for _, other := range m.games {
+		game.ID = max(game.ID, other.ID+1)
+	}
+	game = copyGame(game)
+	m.games = append(m.games, game)
+	return copyGame(game), nil
+}
+
+func (m *Memory) UpdateGame(game models.Game) (models.Game, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i, ok := m.gameIndex(game.ID)
+	if !ok {
+		return models.Game{}, ErrNotFound
+	}
+	if m.gameExists(game, game.ID) {
+		return models.Game{}, ErrGameExists
+	}
+	game.HighScore = m.games[i].HighScore
+	m.games[i] = copyGame(game)
+	return copyGame(game), nil
+}
+
+func (m *Memory) DeleteGame(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i, ok := m.gameIndex(id)
+	if !ok {
+		return ErrNotFound
+	}
+	for _, stored := range m.scores {
+		if stored.gameID == id {
+			return ErrGameHasScores
+		}
+	}
+	for playerID, started := range m.sessions {
+		kept := started[:0]
+		for _, session := range started {
+			if session.GameID == id {
+				delete(m.sessionEnds, session.ID)
+			} else {
+				kept = append(kept, session)
+			}
+		}
+		m.sessions[playerID] = kept
+	}
+	m.games = append(m.games[:i], m.games[i+1:]...)
+	return nil
+}
+
+// copyGame returns game with its own copy of Controls, so the stored game and
+// the caller's never share one
+func copyGame(game models.Game) models.Game {
+	game.Controls = append([]string{}, game.Controls...)
+	return game
+}
+
+func (m *Memory) AddScore(gameID int, entry models.LeaderboardEntry) (ScoreResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i, ok := m.gameIndex(gameID)
+	if !ok {
+		return ScoreResult{}, ErrNotFound
+	}
+	game := &m.games[i]
+	entry, rank := m.addScore(entry, game.ID)
+	isNewHighScore := entry.Score > game.HighScore
+	if isNewHighScore {
+		game.HighScore = entry.Score
+	}
+	return ScoreResult{Entry: entry, Game: copyGame(*game), Rank: rank, IsNewHighScore: isNewHighScore}, nil
+}
+
+// addScore stores and ranks a score of the game with ID gameID, returning the
+// stored entry and its rank. The caller must hold m.mu for writing.
+func (m *Memory) addScore(entry models.LeaderboardEntry, gameID int) (models.LeaderboardEntry, int) {
+	entry.ID = int64(len(m.scores) + 1)
+	m.scores = append(m.scores, storedScore{LeaderboardEntry: entry, gameID: gameID})
+	if entry.PlayerID != "" {
+		m.playerScores[entry.PlayerID] = append(m.playerScores[entry.PlayerID], entry.ID)
+	}
+	m.noteBest(ownerOf(entry, gameID), entry.ID)
+	rank := m.rankings.add(gameID, ranking.Item{Score: entry.Score, Seq: entry.ID})
+	return m.entry(entry.ID), rank
+}
+
+// noteBest records the score with the given ID as owner's best if it beats
+// their current one. The earlier of two equal scores is the better, as on the
+// leaderboard. The caller must hold m.mu for writing.
+func (m *Memory) noteBest(owner scoreOwner, id int64) {
+	best, ok := m.bestScores[owner]
+	if !ok || m.scores[id-1].Score > m.scores[best-1].Score ||
+		(m.scores[id-1].Score == m.scores[best-1].Score && id < best) {
+		m.bestScores[owner] = id
+	}
+}
+
+func (m *Memory) ListScores(filter ScoreFilter) ([]models.LeaderboardEntry, error) {
+	m.mu.RLock()
+	scores := []models.LeaderboardEntry{}
+
+	// One game's all-time leaderboard is read in order from its ranking index
+	if filter.GameID != 0 && filter.From.IsZero() && filter.To.IsZero() {
+		defer m.mu.RUnlock()
+		for _, item := range m.rankings.top(filter.GameID, filter.Limit) {
+			scores = append(scores, m.entry(item.Seq))
+		}
+		return scores, nil
+	}
+
+	for _, stored := range m.scores {
+		if (filter.GameID == 0 || stored.gameID == filter.GameID) && filter.includes(stored.SubmittedAt) {
+			scores = append(scores, m.entry(stored.ID))
+		}
+	}
+	m.mu.RUnlock()
+
+	// Sorting a copy outside the lock keeps readers from blocking writers
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Score > scores[j].Score
+	})
+	if filter.Limit > 0 && len(scores) > filter.Limit {
+		scores = scores[:filter.Limit]
+	}
+	return scores, nil
+}
+
+func (m *Memory) ScoresAround(gameID int, player string, n int) ([]models.LeaderboardEntry, int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	owner := scoreOwner{gameID: gameID, name: player}
+	if _, registered := m.players[player]; registered {
+		owner = scoreOwner{gameID: gameID, playerID: player}
+	}
+	bestID, ok := m.bestScores[owner]
+	if !ok {
+		return nil, 0, ErrNotFound
+	}
+	best := m.scores[bestID-1]
+
+	rank, from, items := m.rankings.around(gameID, ranking.Item{Score: best.Score, Seq: best.ID}, n)
+	scores := make([]models.LeaderboardEntry, len(items))
+	for i, item := range items {
+		scores[i] = m.entry(item.Seq)
+		scores[i].Rank = from + i
+	}
+	return scores, rank, nil
+}
+
+// entry returns a copy of the score with the given ID, listed under its game's
+// current name and its player's current display name. The caller must hold m.mu.
+func (m *Memory) entry(id int64) models.LeaderboardEntry {
+	stored := m.scores[id-1]
+	entry := stored.LeaderboardEntry
+	if i, ok := m.gameIndex(stored.gameID); ok {
+		entry.Game = m.games[i].Name
+	}
+	if player, ok := m.players[entry.PlayerID]; ok {
+		entry.Player = player.DisplayName
+	}
+	return entry
+}
+
+func (m *Memory) UseSession(id string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, used := m.usedSessions[id]; used {
+		return ErrSessionUsed
+	}
+	m.usedSessions[id] = expiresAt
+
+	// Forget expired sessions once the map doubles, so pruning stays amortized O(1)
+	if len(m.usedSessions) >= m.pruneAt {
+		now := time.Now()
+		for sessionID, expiry := range m.usedSessions {
+			if now.After(expiry) {
+				delete(m.usedSessions, sessionID)
+			}
+		}
+		m.pruneAt = max(2*len(m.usedSessions), minSessionPrune)
+	}
+	return nil
+}
+
+func (m *Memory) RecordSession(session SessionStart) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sessions[session.PlayerID] = append(m.sessions[session.PlayerID], session)
+	return nil
+}
+
+func (m *Memory) EndSession(id string, endedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sessionEnds[id] = endedAt
+	return nil
+}
+
+func (m *Memory) CreatePlayer(player models.Player) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if player.Username != "" {
+		key := strings.ToLower(player.Username)
+		if _, taken := m.usernames[key]; taken {
+			return ErrUsernameTaken
+		}
+		m.usernames[key] = player.ID
+	}
+	m.players[player.ID] = player
+	return nil
+}
+
+func (m *Memory) GetPlayer(id string) (models.Player, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	player, ok := m.players[id]
+	if !ok {
+		return models.Player{}, ErrNotFound
+	}
+	return player, nil
+}
+
+func (m *Memory) FindPlayer(username string) (models.Player, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	player, ok := m.players[m.usernames[strings.ToLower(username)]]
+	if !ok {
+		return models.Player{}, ErrNotFound
+	}
+	return player, nil
+}
+
+func (m *Memory) RenamePlayer(id, displayName string) (models.Player, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	player, ok := m.players[id]
+	if !ok {
+		return models.Player{}, ErrNotFound
+	}
+	player.DisplayName = displayName
+	m.players[id] = player
+	return player, nil
+}
+
+func (m *Memory) ClaimGuest(guestID, playerID string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if guest, ok := m.players[guestID]; !ok || !guest.Guest {
+		return 0, ErrNotFound
+	}
+	if _, ok := m.players[playerID]; !ok {
+		return 0, ErrNotFound
+	}
+
+	claimed := m.playerScores[guestID]
+	for _, id := range claimed {
+		m.scores[id-1].PlayerID = playerID
+	}
+	scores := append(m.playerScores[playerID], claimed...)
+	sort.Slice(scores, func(i, j int) bool { return scores[i] < scores[j] })
+	m.playerScores[playerID] = scores
+	delete(m.playerScores, guestID)
+	for _, game := range m.games {
+		guest := scoreOwner{gameID: game.ID, playerID: guestID}
+		if best, ok := m.bestScores[guest]; ok {
+			m.noteBest(scoreOwner{gameID: game.ID, playerID: playerID}, best)
+			delete(m.bestScores, guest)
+		}
+	}
+
+	sessions := m.sessions[playerID]
+	for _, session := range m.sessions[guestID] {
+		session.PlayerID = playerID
+		sessions = append(sessions, session)
+	}
+	sort.SliceStable(sessions, func(i, j int) bool { return sessions[i].StartedAt.Before(sessions[j].StartedAt) })
+	m.sessions[playerID] = sessions
+	delete(m.sessions, guestID)
+	for id, unlockedAt := range m.achievements[guestID] {
+		m.unlock(playerID, id, unlockedAt)
+	}
+	delete(m.achievements, guestID)
+	delete(m.players, guestID)
+	for hash, token := range m.refreshTokens {
+		if token.playerID == guestID {
+			delete(m.refreshTokens, hash)
+		}
+	}
+	return len(claimed), nil
+}
+
+func (m *Memory) SaveRefreshToken(hash, playerID string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.refreshTokens[hash] = refreshToken{playerID: playerID, expiresAt: expiresAt}
+
+	// Forget expired tokens the same way as used sessions
+	if len(m.refreshTokens) >= m.refreshPrune {
+		now := time.Now()
+		for tokenHash, token := range m.refreshTokens {
+			if now.After(token.expiresAt) {
+				delete(m.refreshTokens, tokenHash)
+			}
+		}
+		m.refreshPrune = max(2*len(m.refreshTokens), minSessionPrune)
+	}
+	return nil
+}
+
+func (m *Memory) UseRefreshToken(hash string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	token, ok := m.refreshTokens[hash]
+	if !ok {
+		return "", ErrNotFound
+	}
+	delete(m.refreshTokens, hash)
+	if time.Now().After(token.expiresAt) {
+		return "", ErrNotFound
+	}
+	return token.playerID, nil
+}
+
+func (m *Memory) PlayerActivity(playerID string) (achievements.Activity, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	activity := achievements.Activity{BestScores: make(map[string]int)}
+	games := make(map[int]bool)
+	days := make(map[string]bool)
+	for _, session := range m.sessions[playerID] {
+		activity.Sessions++
+		games[session.GameID] = true
+		days[session.Date] = true
+	}
+	for _, id := range m.playerScores[playerID] {
+		stored := m.scores[id-1]
+		activity.Scores++
+		days[stored.Date] = true
+		if i, ok := m.gameIndex(stored.gameID); ok {
+			slug := m.games[i].Slug
+			games[stored.gameID] = true
+			if best, ok := activity.BestScores[slug]; !ok || stored.Score > best {
+				activity.BestScores[slug] = stored.Score
+			}
+		}
+	}
+	activity.Games = len(games)
+	for day := range days {
+		activity.Days = append(activity.Days, day)
+	}
+	sort.Strings(activity.Days)
+	return activity, nil
+}
+
+func (m *Memory) UnlockAchievements(playerID string, ids []string, unlockedAt time.Time) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	unlocked := []string{}
+	for _, id := range ids {
+		if m.unlock(playerID, id, unlockedAt) {
+			unlocked = append(unlocked, id)
+		}
+	}
+	return unlocked, nil
+}
+
+// unlock records an achievement for a player unless they already have it, and
+// reports whether it did. The caller must hold m.mu for writing.
+func (m *Memory) unlock(playerID, id string, unlockedAt time.Time) bool {
+	if m.achievements[playerID] == nil {
+		m.achievements[playerID] = make(map[string]time.Time)
+	}
+	if _, ok := m.achievements[playerID][id]; ok {
+		return false
+	}
+	m.achievements[playerID][id] = unlockedAt
+	return true
+}
+
+func (m *Memory) ListAchievements(playerID string) (map[string]time.Time, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	unlocked := make(map[string]time.Time, len(m.achievements[playerID]))
+	for id, unlockedAt := range m.achievements[playerID] {
+		unlocked[id] = unlockedAt
+	}
+	return unlocked, nil
+}
+
+func (m *Memory) PlayerStats(playerID string, historyLength, recentLength int) (models.PlayerStats, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	byGame := make(map[int]*models.PlayerGameStats)
+	gameStats := func(gameID int) *models.PlayerGameStats {
+		if stats, ok := byGame[gameID]; ok {
+			return stats
+		}
+		i, ok := m.gameIndex(gameID)
+		if !ok {
+			return nil
+		}
+		game := m.games[i]
+		byGame[gameID] = &models.PlayerGameStats{GameID: game.ID, Game: game.Name, Slug: game.Slug, History: []models.ScorePoint{}}
+		return byGame[gameID]
+	}
+	recent := []models.ActivityEvent{}
+
+	for _, session := range m.sessions[playerID] {
+		stats := gameStats(session.GameID)
+		if stats == nil {
+			continue
+		}
+		stats.SessionsStarted++
+		if endedAt, ok := m.sessionEnds[session.ID]; ok {
+			stats.PlayTimeSeconds += int64(endedAt.Sub(session.StartedAt).Seconds())
+		}
+		if session.StartedAt.After(stats.LastPlayedAt) {
+			stats.LastPlayedAt = session.StartedAt
+		}
+		recent = append(recent, models.ActivityEvent{Type: "session", Game: stats.Game, At: session.StartedAt})
+	}
+
+	best := make(map[int]int64)
+	for _, id := range m.playerScores[playerID] {
+		entry := m.scores[id-1]
+		stats := gameStats(entry.gameID)
+		if stats == nil {
+			continue
+		}
+		stats.ScoresSubmitted++
+		if stats.ScoresSubmitted == 1 || entry.Score > stats.BestScore {
+			stats.BestScore = entry.Score
+			best[entry.gameID] = entry.ID
+		}
+		stats.History = append(stats.History, models.ScorePoint{EntryID: entry.ID, Score: entry.Score, SubmittedAt: entry.SubmittedAt})
+		if len(stats.History) > historyLength {
+			stats.History = stats.History[1:]
+		}
+		if entry.SubmittedAt.After(stats.LastPlayedAt) {
+			stats.LastPlayedAt = entry.SubmittedAt
+		}
+		score := entry.Score
+		recent = append(recent, models.ActivityEvent{Type: "score", Game: stats.Game, Score: &score, At: entry.SubmittedAt})
+	}
+
+	stats := models.PlayerStats{Games: []models.PlayerGameStats{}}
+	for gameID, game := range byGame {
+		if id, ok := best[gameID]; ok {
+			game.Rank, game.TotalScores = m.rankings.rank(gameID, ranking.Item{Score: game.BestScore, Seq: id})
+		}
+		stats.Games = append(stats.Games, *game)
+	}
+	sort.Slice(stats.Games, func(i, j int) bool {
+		return stats.Games[i].GameID < stats.Games[j].GameID
+	})
+	addTotals(&stats)
+
+	// Times are whole seconds. Within one, a session is likelier to have been
+	// started after a score than to have lasted under a second, so it is newer.
+	sort.SliceStable(recent, func(i, j int) bool {
+		if !recent[i].At.Equal(recent[j].At) {
+			return recent[i].At.After(recent[j].At)
+		}
+		return recent[i].Type == "session" && recent[j].Type != "session"
+	})
+	stats.RecentActivity = recent[:min(len(recent), recentLength)]
+	return stats, nil
+}
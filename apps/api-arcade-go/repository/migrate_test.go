@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"database/sql"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "arcade.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func schemaVersions(t *testing.T, db *sql.DB) []int {
+	t.Helper()
+	rows, err := db.Query(`SELECT version FROM schema_migrations ORDER BY version`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var versions []int
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			t.Fatal(err)
+		}
+		versions = append(versions, version)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return versions
+}
+
+func tableExists(t *testing.T, db *sql.DB, name string) bool {
+	t.Helper()
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, name).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	return count > 0
+}
+
+func TestLoadMigrationsNumbersVersionsFromOne(t *testing.T) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("no migrations embedded")
+	}
+	for i, m := range migrations {
+		if m.version != i+1 {
+			t.Errorf("migration %d has version %d, want %d", i, m.version, i+1)
+		}
+		if m.description == "" || m.statements == "" {
+			t.Errorf("migration %04d has no description or statements", m.version)
+		}
+	}
+}
+
+func TestMigrateAppliesEveryMigrationOnce(t *testing.T) {
+	db := openTestDB(t)
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for run := 1; run <= 2; run++ {
+		if err := migrate(db); err != nil {
+			t.Fatalf("run %d: %v", run, err)
+		}
+		versions := schemaVersions(t, db)
+		if len(versions) != len(migrations) || versions[len(versions)-1] != migrations[len(migrations)-1].version {
+			t.Fatalf("run %d: applied versions %v, want 1 to %d", run, versions, len(migrations))
+		}
+	}
+	for _, table := range []string{"games", "scores", "players", "game_sessions"} {
+		if !tableExists(t, db, table) {
+			t.Errorf("table %s was not created", table)
+		}
+	}
+}
+
+func TestMigrateResumesFromRecordedVersion(t *testing.T) {
+	db := openTestDB(t)
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A database last opened by an older build has only the earlier migrations
+	if _, err := db.Exec(`CREATE TABLE schema_migrations (
+		version     INTEGER PRIMARY KEY,
+		description TEXT    NOT NULL,
+		applied_at  TEXT
+	)`); err != nil {
+		t.Fatal(err)
+	}
+	older := len(migrations) / 2
+	for _, m := range migrations[:older] {
+		if err := applyMigration(db, m); err != nil {
+			t.Fatalf("migration %04d: %v", m.version, err)
+		}
+	}
+
+	// Reapplying an earlier migration would fail on its existing tables
+	if err := migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	if versions := schemaVersions(t, db); len(versions) != len(migrations) {
+		t.Errorf("applied versions %v, want 1 to %d", versions, len(migrations))
+	}
+}
+
+func TestApplyMigrationRollsBackOnError(t *testing.T) {
+	db := openTestDB(t)
+	if err := migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	before := schemaVersions(t, db)
+
+	broken := migration{
+		version:     before[len(before)-1] + 1,
+		description: "broken",
+		statements: `CREATE TABLE half_applied (id INTEGER PRIMARY KEY);
+			INSERT INTO no_such_table VALUES (1);`,
+	}
+	if err := applyMigration(db, broken); err == nil {
+		t.Fatal("broken migration applied without error")
+	}
+	if tableExists(t, db, "half_applied") {
+		t.Error("statements before the failure were not rolled back")
+	}
+	if after := schemaVersions(t, db); len(after) != len(before) {
+		t.Errorf("versions %v after a failed migration, want %v", after, before)
+	}
+}
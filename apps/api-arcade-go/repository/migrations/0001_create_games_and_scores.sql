@@ -0,0 +1,15 @@
+CREATE TABLE games (
+    id         INTEGER PRIMARY KEY,
+    name       TEXT    NOT NULL UNIQUE COLLATE NOCASE,
+    category   TEXT    NOT NULL,
+    difficulty TEXT    NOT NULL,
+    high_score INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE scores (
+    id      INTEGER PRIMARY KEY AUTOINCREMENT,
+    game_id INTEGER NOT NULL REFERENCES games (id),
+    player  TEXT    NOT NULL,
+    score   INTEGER NOT NULL,
+    date    TEXT    NOT NULL
+);
@@ -0,0 +1,3 @@
+-- Serves per-game leaderboards and rank lookups: highest score first, earlier
+-- submissions first among equal scores
+CREATE INDEX scores_game_rank ON scores (game_id, score DESC, id);
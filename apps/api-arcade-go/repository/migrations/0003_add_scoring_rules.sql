@@ -0,0 +1,9 @@
+ALTER TABLE games ADD COLUMN max_points_per_second REAL NOT NULL DEFAULT 0;
+ALTER TABLE games ADD COLUMN max_session_seconds INTEGER NOT NULL DEFAULT 0;
+
+-- Give the sample catalog the same limits a new database is seeded with
+UPDATE games SET max_points_per_second = 25, max_session_seconds = 1800 WHERE name = 'Snake';
+UPDATE games SET max_points_per_second = 150, max_session_seconds = 3600 WHERE name = 'Tetris';
+UPDATE games SET max_points_per_second = 100, max_session_seconds = 3600 WHERE name = 'Pac-Man';
+UPDATE games SET max_points_per_second = 120, max_session_seconds = 3600 WHERE name = 'Space Invaders';
+UPDATE games SET max_points_per_second = 0.5, max_session_seconds = 1200 WHERE name = 'Pong';
@@ -0,0 +1,8 @@
+-- Game sessions that have already been used to submit a score. Rows are only
+-- needed until the session would have expired anyway.
+CREATE TABLE used_sessions (
+    id         TEXT    PRIMARY KEY,
+    expires_at INTEGER NOT NULL
+);
+
+CREATE INDEX used_sessions_expiry ON used_sessions (expires_at);
@@ -0,0 +1,24 @@
+-- Registered and guest players. Guests have no username or password.
+CREATE TABLE players (
+    id            TEXT    PRIMARY KEY,
+    username      TEXT    UNIQUE COLLATE NOCASE,
+    display_name  TEXT    NOT NULL,
+    password_hash TEXT    NOT NULL DEFAULT '',
+    guest         INTEGER NOT NULL DEFAULT 0,
+    created_at    INTEGER NOT NULL
+);
+
+-- Hashes of unused refresh tokens; each is deleted when exchanged
+CREATE TABLE refresh_tokens (
+    hash       TEXT    PRIMARY KEY,
+    player_id  TEXT    NOT NULL REFERENCES players (id) ON DELETE CASCADE,
+    expires_at INTEGER NOT NULL
+);
+
+CREATE INDEX refresh_tokens_expiry ON refresh_tokens (expires_at);
+
+-- Scores submitted since player accounts belong to a player; older scores
+-- keep only the name they were submitted under
+ALTER TABLE scores ADD COLUMN player_id TEXT REFERENCES players (id);
+
+CREATE INDEX scores_player ON scores (player_id);
@@ -0,0 +1,7 @@
+-- Full submission times, in Unix seconds, for daily, weekly and monthly
+-- leaderboards. Scores recorded before now only have a date, taken as midnight UTC.
+ALTER TABLE scores ADD COLUMN submitted_at INTEGER NOT NULL DEFAULT 0;
+
+UPDATE scores SET submitted_at = CAST(strftime('%s', date) AS INTEGER);
+
+CREATE INDEX scores_game_time ON scores (game_id, submitted_at);
@@ -0,0 +1,22 @@
+ALTER TABLE games ADD COLUMN slug TEXT NOT NULL DEFAULT '';
+ALTER TABLE games ADD COLUMN description TEXT NOT NULL DEFAULT '';
+-- A JSON array of strings
+ALTER TABLE games ADD COLUMN controls TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE games ADD COLUMN enabled INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE games ADD COLUMN thumbnail TEXT NOT NULL DEFAULT '';
+
+UPDATE games SET slug = lower(replace(name, ' ', '-'));
+
+-- Give the sample catalog the same metadata a new database is seeded with
+UPDATE games SET description = 'Classic snake game - eat food and grow longer!',
+    controls = '["Arrow Keys","WASD"]' WHERE name = 'Snake';
+UPDATE games SET description = 'Stack falling blocks to clear lines and score points!',
+    controls = '["Arrow Keys","Space","P"]' WHERE name = 'Tetris';
+UPDATE games SET description = 'Eat every dot in the maze while dodging the ghosts!',
+    controls = '["Arrow Keys"]' WHERE name = 'Pac-Man';
+UPDATE games SET description = 'Defend Earth from alien invaders!',
+    controls = '["Arrow Keys","AD","Space"]' WHERE name = 'Space Invaders';
+UPDATE games SET description = 'Classic paddle game - first to 5 points wins!',
+    controls = '["Arrow Keys","WS","Space"]' WHERE name = 'Pong';
+
+CREATE UNIQUE INDEX games_slug ON games (slug);
@@ -0,0 +1,19 @@
+-- Game sessions players have started, which count toward their achievements
+-- whether or not a score was submitted for them
+CREATE TABLE game_sessions (
+    id         TEXT    PRIMARY KEY,
+    player_id  TEXT    NOT NULL REFERENCES players (id) ON DELETE CASCADE,
+    game_id    INTEGER NOT NULL REFERENCES games (id) ON DELETE CASCADE,
+    date       TEXT    NOT NULL,
+    started_at INTEGER NOT NULL
+);
+
+CREATE INDEX game_sessions_player ON game_sessions (player_id);
+
+-- Achievements players have unlocked; each unlocks once per player
+CREATE TABLE player_achievements (
+    player_id      TEXT    NOT NULL REFERENCES players (id) ON DELETE CASCADE,
+    achievement_id TEXT    NOT NULL,
+    unlocked_at    INTEGER NOT NULL,
+    PRIMARY KEY (player_id, achievement_id)
+);
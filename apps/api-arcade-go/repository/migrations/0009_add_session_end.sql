@@ -0,0 +1,3 @@
+-- When a score was submitted for the session, which ends it; sessions
+-- abandoned without a score have none
+ALTER TABLE game_sessions ADD COLUMN ended_at INTEGER;
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"arcade-api/ranking"
+	"sync"
+)
+
+// rankings holds the ranking index of each game's scores, keyed by game ID,
+// so ranks are found without sorting a whole leaderboard
+type rankings struct {
+	mu    sync.RWMutex
+	games map[int]*ranking.Index
+}
+
+func newRankings() *rankings {
+	return &rankings{games: make(map[int]*ranking.Index)}
+}
+
+// add indexes a score of a game and returns its rank
+func (r *rankings) add(gameID int, item ranking.Item) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.insert(gameID, item)
+}
+
+// addOnCommit runs commit, which stores a score of a game, and indexes the
+// score if it succeeds, returning its rank. The index stays locked while
+// commit runs, so no reader can find the stored score before it is ranked.
+func (r *rankings) addOnCommit(gameID int, item ranking.Item, commit func() error) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := commit(); err != nil {
+		return 0, err
+	}
+	return r.insert(gameID, item), nil
+}
+
+// insert indexes a score of a game and returns its rank. The caller must hold
+// r.mu for writing.
+func (r *rankings) insert(gameID int, item ranking.Item) int {
+	index, ok := r.games[gameID]
+	if !ok {
+		index = ranking.NewIndex()
+		r.games[gameID] = index
+	}
+	return index.Insert(item)
+}
+
+// around returns the rank of item within its game and the items ranked from n
+// places above it to n places below, along with the rank of the first
+func (r *rankings) around(gameID int, item ranking.Item, n int) (rank, from int, items []ranking.Item) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	index, ok := r.games[gameID]
+	if !ok {
+		return 0, 0, nil
+	}
+	rank = index.Rank(item)
+	if rank == 0 {
+		return 0, 0, nil
+	}
+	from = max(rank-n, 1)
+	return rank, from, index.Range(from, rank-from+n+1)
+}
+
+// top returns the limit highest ranked items of a game, or all of them when
+// limit is zero
+func (r *rankings) top(gameID, limit int) []ranking.Item {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	index, ok := r.games[gameID]
+	if !ok {
+		return []ranking.Item{}
+	}
+	if limit <= 0 {
+		limit = index.Len()
+	}
+	return index.Range(1, limit)
+}
+
+// rank returns the rank of item within its game and the number of scores the
+// game has, or a rank of zero if item is not indexed
+func (r *rankings) rank(gameID int, item ranking.Item) (rank, total int) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	index, ok := r.games[gameID]
+	if !ok {
+		return 0, 0
+	}
+	return index.Rank(item), index.Len()
+}
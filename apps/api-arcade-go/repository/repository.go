@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"arcade-api/achievements"
+	"arcade-api/models"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when a game, player or refresh token does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrUsernameTaken is returned when registering a username already in use
+	ErrUsernameTaken = errors.New("username taken")
+	// ErrSessionUsed is returned when a game session has already been used
+	ErrSessionUsed = errors.New("session already used")
+	// ErrGameExists is returned when storing a game whose name or slug another
+	// game already has
+	ErrGameExists = errors.New("game already exists")
+	// ErrGameHasScores is returned when deleting a game that has scores
+	ErrGameHasScores = errors.New("game has scores")
+)
+
+// GameFilter narrows a game listing. Empty fields match every game; matching is
+// case-insensitive. Disabled games are left out unless IncludeDisabled is set.
+type GameFilter struct {
+	Category        string
+	Difficulty      string
+	IncludeDisabled bool
+}
+
+// ScoreFilter narrows a score listing. A GameID other than zero matches the
+// scores of that game; From and To bound the submission time, From inclusive
+// and To exclusive, when set; a Limit of zero returns every score.
+type ScoreFilter struct {
+	GameID int
+	From   time.Time
+	To     time.Time
+	Limit  int
+}
+
+// includes reports whether a score submitted at t falls within the filter's
+// time bounds
+func (f ScoreFilter) includes(t time.Time) bool {
+	return (f.From.IsZero() || !t.Before(f.From)) && (f.To.IsZero() || t.Before(f.To))
+}
+
+// Repository stores the game catalog and submitted scores. Implementations must
+// be safe for concurrent use and return copies that callers are free to modify.
+type Repository interface {
+	// ListGames returns the games matching filter, ordered by ID
+	ListGames(filter GameFilter) ([]models.Game, error)
+	// GetGame returns the game with the given ID, or ErrNotFound
+	GetGame(id int) (models.Game, error)
+	// FindGame returns the game with the given name or slug, matched
+	// case-insensitively, or ErrNotFound
+	FindGame(name string) (models.Game, error)
+	// CreateGame stores a new game with the next free ID and returns it, or
+	// returns ErrGameExists if its name or slug is, ignoring case, the name or
+	// slug of another game
+	CreateGame(game models.Game) (models.Game, error)
+	// UpdateGame replaces the game with game.ID, keeping its high score, and
+	// returns it as stored. It returns ErrNotFound or ErrGameExists.
+	UpdateGame(game models.Game) (models.Game, error)
+	// DeleteGame deletes the game with the given ID. It returns ErrNotFound, or
+	// ErrGameHasScores if the game has scores, which disabling it keeps.
+	DeleteGame(id int) error
+	// AddScore records a score for the game with ID gameID and raises the
+	// game's high score when the score beats it, as one atomic step. It
+	// returns ErrNotFound if the game does not exist.
+	AddScore(gameID int, entry models.LeaderboardEntry) (ScoreResult, error)
+	// ListScores returns the scores matching filter, highest first; equal
+	// scores keep submission order
+	ListScores(filter ScoreFilter) ([]models.LeaderboardEntry, error)
+	// ScoresAround returns the best score in a game of player, matched by player
+	// ID or, for scores from before player accounts, by the name they were
+	// submitted under, with up to n scores ranked above and below it. Entries
+	// carry their all-time rank, and the player's rank is returned too. It
+	// returns ErrNotFound if the player has no score in the game.
+	ScoresAround(gameID int, player string, n int) ([]models.LeaderboardEntry, int, error)
+	// UseSession marks a game session as used, or returns ErrSessionUsed if it
+	// already was. A session need only be remembered until expiresAt.
+	UseSession(id string, expiresAt time.Time) error
+	// RecordSession stores a game session a player started, which counts
+	// toward their activity
+	RecordSession(session SessionStart) error
+	// EndSession records when the score of a recorded session was submitted.
+	// Sessions that were never recorded are ignored.
+	EndSession(id string, endedAt time.Time) error
+
+	// CreatePlayer stores a new player, or returns ErrUsernameTaken if another
+	// player has the same username, compared case-insensitively
+	CreatePlayer(player models.Player) error
+	// GetPlayer returns the player with the given ID, or ErrNotFound
+	GetPlayer(id string) (models.Player, error)
+	// FindPlayer returns the registered player with the given username,
+	// matched case-insensitively, or ErrNotFound
+	FindPlayer(username string) (models.Player, error)
+	// RenamePlayer changes a player's display name, which their scores are
+	// listed under from then on, and returns the updated player
+	RenamePlayer(id, displayName string) (models.Player, error)
+	// ClaimGuest moves every score, session and achievement of the guest
+	// guestID to playerID and deletes the guest with its refresh tokens, as one
+	// atomic step; achievements playerID already has keep their unlock time.
+	// It returns the number of scores moved, or ErrNotFound if guestID is not
+	// a guest.
+	ClaimGuest(guestID, playerID string) (int, error)
+	// SaveRefreshToken stores the hash of a refresh token issued to playerID
+	SaveRefreshToken(hash, playerID string, expiresAt time.Time) error
+	// UseRefreshToken deletes a refresh token and returns the player it was
+	// issued to, or ErrNotFound if it does not exist or has expired
+	UseRefreshToken(hash string) (string, error)
+
+	// PlayerActivity returns what a player has done, counting the sessions
+	// they started and the scores submitted under their ID
+	PlayerActivity(playerID string) (achievements.Activity, error)
+	// PlayerStats returns a player's statistics per game, with up to
+	// historyLength of their latest scores in each and their recentLength
+	// latest sessions and scores, newest first. It reads only the player's own
+	// scores and sessions; the player ID and display name are left unset.
+	PlayerStats(playerID string, historyLength, recentLength int) (models.PlayerStats, error)
+	// UnlockAchievements records that a player unlocked the achievements with
+	// the given IDs at unlockedAt, ignoring those they already have, and
+	// returns the IDs newly unlocked
+	UnlockAchievements(playerID string, ids []string, unlockedAt time.Time) ([]string, error)
+	// ListAchievements returns when a player unlocked each achievement they
+	// have, by achievement ID
+	ListAchievements(playerID string) (map[string]time.Time, error)
+}
+
+// ScoreResult is the outcome of recording a score
+type ScoreResult struct {
+	// Entry is the score as stored, with its ID
+	Entry models.LeaderboardEntry
+	// Game is the game as updated
+	Game models.Game
+	// Rank is the score's position on the game's all-time leaderboard
+	Rank           int
+	IsNewHighScore bool
+}
+
+// SessionStart is a game session a player started. Date is the day of
+// StartedAt in the leaderboard time zone.
+type SessionStart struct {
+	ID        string
+	PlayerID  string
+	GameID    int
+	Date      string
+	StartedAt time.Time
+}
+
+// addTotals sets a player's overall statistics from those of each game
+func addTotals(stats *models.PlayerStats) {
+	stats.GamesPlayed = len(stats.Games)
+	for _, game := range stats.Games {
+		stats.SessionsStarted += game.SessionsStarted
+		stats.ScoresSubmitted += game.ScoresSubmitted
+		stats.PlayTimeSeconds += game.PlayTimeSeconds
+	}
+}
+
+// Backends lists the storage backends Open accepts
+var Backends = []string{"memory", "sqlite"}
+
+// Open returns the repository for backend, seeded with the sample catalog when
+// it starts empty. path is the database file for the sqlite backend.
+func Open(backend, path string) (Repository, error) {
+	switch backend {
+	case "memory":
+		return NewMemory(SeedGames(), SeedScores()), nil
+	case "sqlite":
+		repo, err := OpenSQLite(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := repo.SeedIfEmpty(SeedGames(), SeedScores()); err != nil {
+			repo.Close()
+			return nil, fmt.Errorf("failed to seed %s: %w", path, err)
+		}
+		return repo, nil
+	}
+	return nil, fmt.Errorf("unknown storage backend %q (available: %s)", backend, strings.Join(Backends, ", "))
+}
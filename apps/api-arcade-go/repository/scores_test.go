@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"arcade-api/models"
+)
+
+// testBackends returns each backend seeded with two games and their scores.
+// Scores are seeded by slug, as well as by name, to cover the seed lookup too.
+func testBackends(t *testing.T, now time.Time) map[string]Repository {
+	t.Helper()
+	games := []models.Game{
+		{ID: 1, Slug: "star-blaster", Name: "Star Blaster", Enabled: true},
+		{ID: 2, Slug: "snake", Name: "Snake", Enabled: true},
+	}
+	scores := []models.LeaderboardEntry{
+		{Player: "ada", Game: "Star Blaster", Score: 300, SubmittedAt: now.Add(-time.Hour)},
+		{Player: "bob", Game: "star-blaster", Score: 200, SubmittedAt: now.Add(-48 * time.Hour)},
+		{Player: "cy", Game: "snake", Score: 100, SubmittedAt: now.Add(-time.Hour)},
+	}
+
+	sqlite, err := OpenSQLite(filepath.Join(t.TempDir(), "arcade.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlite.Close() })
+	if err := sqlite.SeedIfEmpty(games, scores); err != nil {
+		t.Fatal(err)
+	}
+	return map[string]Repository{
+		"memory": NewMemory(games, scores),
+		"sqlite": sqlite,
+	}
+}
+
+func TestListScoresOfGameFoundByNameOrSlug(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		game  string
+		from  time.Time
+		count int
+	}{
+		{"Star Blaster", time.Time{}, 2},
+		{"star-blaster", time.Time{}, 2},
+		{"STAR BLASTER", time.Time{}, 2},
+		{"Star-Blaster", time.Time{}, 2},
+		{"Star Blaster", now.Add(-24 * time.Hour), 1},
+		{"snake", now.Add(-24 * time.Hour), 1},
+	}
+
+	for backend, repo := range testBackends(t, now) {
+		for _, tc := range cases {
+			game, err := repo.FindGame(tc.game)
+			if err != nil {
+				t.Fatalf("%s %+v: %v", backend, tc, err)
+			}
+			filter := ScoreFilter{GameID: game.ID, From: tc.from}
+			if !tc.from.IsZero() {
+				filter.To = now.Add(time.Minute)
+			}
+			scores, err := repo.ListScores(filter)
+			if err != nil {
+				t.Fatalf("%s %+v: %v", backend, tc, err)
+			}
+			if len(scores) != tc.count {
+				t.Fatalf("%s %+v: %d scores, want %d", backend, tc, len(scores), tc.count)
+			}
+			for _, entry := range scores {
+				if entry.Game != game.Name {
+					t.Fatalf("%s %+v: score for game %q, want %q", backend, tc, entry.Game, game.Name)
+				}
+			}
+		}
+
+		if _, err := repo.FindGame("pong"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("%s: finding an unknown game: error %v, want %v", backend, err, ErrNotFound)
+		}
+		if scores, err := repo.ListScores(ScoreFilter{}); err != nil || len(scores) != 3 {
+			t.Fatalf("%s: every game: %d scores, error %v; want 3", backend, len(scores), err)
+		}
+	}
+}
+
+func TestGameNamesAndSlugsFindOneGame(t *testing.T) {
+	cases := []struct {
+		name, slug string
+		want       error
+	}{
+		{"Snake", "python", ErrGameExists},
+		{"Python", "snake", ErrGameExists},
+		// The name of one game as the slug of another, and the other way round
+		{"Boa", "star blaster", ErrGameExists},
+		{"star-blaster", "boa", ErrGameExists},
+		{"SNAKE", "boa", ErrGameExists},
+		{"Boa", "boa", nil},
+	}
+
+	for backend, repo := range testBackends(t, time.Now()) {
+		for _, tc := range cases {
+			_, err := repo.CreateGame(models.Game{Name: tc.name, Slug: tc.slug, Enabled: true})
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("%s: creating %+v: error %v, want %v", backend, tc, err, tc.want)
+			}
+		}
+
+		// Renaming a game to another game's slug is refused too
+		game, err := repo.FindGame("snake")
+		if err != nil {
+			t.Fatal(err)
+		}
+		game.Name = "Star-Blaster"
+		if _, err := repo.UpdateGame(game); !errors.Is(err, ErrGameExists) {
+			t.Fatalf("%s: renaming to another game's slug: error %v, want %v", backend, err, ErrGameExists)
+		}
+		// A game keeping its own name as its slug is no clash
+		game.Name, game.Slug = "snake", "snake"
+		if _, err := repo.UpdateGame(game); err != nil {
+			t.Fatalf("%s: %v", backend, err)
+		}
+	}
+}
+
+func TestAddScoreRecordsTheGameGivenByID(t *testing.T) {
+	for backend, repo := range testBackends(t, time.Now()) {
+		snake, err := repo.FindGame("snake")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The entry's game name is not used to find the game
+		result, err := repo.AddScore(snake.ID, models.LeaderboardEntry{Player: "dee", Game: "Star Blaster", Score: 50, SubmittedAt: time.Now()})
+		if err != nil {
+			t.Fatalf("%s: %v", backend, err)
+		}
+		if result.Entry.Game != "Snake" || result.Game.ID != snake.ID || result.Rank != 2 {
+			t.Fatalf("%s: stored %+v in game %d at rank %d, want Snake at rank 2", backend, result.Entry, result.Game.ID, result.Rank)
+		}
+		if _, err := repo.AddScore(99, models.LeaderboardEntry{Player: "dee", Score: 50, SubmittedAt: time.Now()}); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("%s: scoring an unknown game: error %v, want %v", backend, err, ErrNotFound)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"arcade-api/models"
+	"time"
+)
+
+// SeedGames returns the sample game catalog a new repository starts with
+func SeedGames() []models.Game {
+	return []models.Game{
+		{ID: 1, Slug: "snake", Name: "Snake", Category: "classic", Difficulty: "easy", HighScore: 1250,
+			Description: "Classic snake game - eat food and grow longer!",
+			Controls:    []string{"Arrow Keys", "WASD"}, Enabled: true,
+			Rules: models.ScoringRules{MaxPointsPerSecond: 25, MaxSessionSeconds: 1800}},
+		{ID: 2, Slug: "tetris", Name: "Tetris", Category: "puzzle", Difficulty: "medium", HighScore: 8900,
+			Description: "Stack falling blocks to clear lines and score points!",
+			Controls:    []string{"Arrow Keys", "Space", "P"}, Enabled: true,
+			Rules: models.ScoringRules{MaxPointsPerSecond: 150, MaxSessionSeconds: 3600}},
+		{ID: 3, Slug: "pac-man", Name: "Pac-Man", Category: "arcade", Difficulty: "medium", HighScore: 15600,
+			Description: "Eat every dot in the maze while dodging the ghosts!",
+			Controls:    []string{"Arrow Keys"}, Enabled: true,
+			Rules: models.ScoringRules{MaxPointsPerSecond: 100, MaxSessionSeconds: 3600}},
+		{ID: 4, Slug: "space-invaders", Name: "Space Invaders", Category: "shooter", Difficulty: "hard", HighScore: 23400,
+			Description: "Defend Earth from alien invaders!",
+			Controls:    []string{"Arrow Keys", "AD", "Space"}, Enabled: true,
+			Rules: models.ScoringRules{MaxPointsPerSecond: 120, MaxSessionSeconds: 3600}},
+		{ID: 5, Slug: "pong", Name: "Pong", Category: "classic", Difficulty: "easy", HighScore: 21,
+			Description: "Classic paddle game - first to 5 points wins!",
+			Controls:    []string{"Arrow Keys", "WS", "Space"}, Enabled: true,
+			Rules: models.ScoringRules{MaxPointsPerSecond: 0.5, MaxSessionSeconds: 1200}},
+	}
+}
+
+// SeedScores returns the sample leaderboard a new repository starts with
+func SeedScores() []models.LeaderboardEntry {
+	return []models.LeaderboardEntry{
+		{Player: "Player1", Game: "Snake", Score: 1250, Date: "2024-01-15",
+			SubmittedAt: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{Player: "GameMaster", Game: "Tetris", Score: 8900, Date: "2024-01-14",
+			SubmittedAt: time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)},
+		{Player: "ArcadeKing", Game: "Pac-Man", Score: 15600, Date: "2024-01-13",
+			SubmittedAt: time.Date(2024, 1, 13, 0, 0, 0, 0, time.UTC)},
+	}
+}
@@ -0,0 +1,761 @@
+package repository
+
+import (
+	"arcade-api/achievements"
+	"arcade-api/models"
+	"arcade-api/ranking"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLite is a Repository stored in an embedded SQLite database file. Score
+// ranks come from an index held in memory and rebuilt when the database is
+// opened, so no other process may add scores while it is open.
+type SQLite struct {
+	db       *sql.DB
+	rankings *rankings
+}
+
+// OpenSQLite opens (creating if needed) the database at path and brings its
+// schema up to date
+func OpenSQLite(path string) (*SQLite, error) {
+	// Write transactions take the write lock up front so concurrent score
+	// submissions queue on busy_timeout instead of failing to upgrade a read lock
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Set("_txlock", "immediate")
+
+	db, err := sql.Open("sqlite", "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	s := &SQLite{db: db}
+	if err := s.loadRankings(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to rank scores: %w", err)
+	}
+	return s, nil
+}
+
+// loadRankings rebuilds the ranking index from every stored score
+func (s *SQLite) loadRankings() error {
+	rows, err := s.db.Query(`SELECT game_id, id, score FROM scores`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	loaded := newRankings()
+	for rows.Next() {
+		var gameID int
+		var item ranking.Item
+		if err := rows.Scan(&gameID, &item.Seq, &item.Score); err != nil {
+			return err
+		}
+		loaded.add(gameID, item)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	s.rankings = loaded
+	return nil
+}
+
+// Close closes the database
+func (s *SQLite) Close() error {
+	return s.db.Close()
+}
+
+// SeedIfEmpty loads games and scores into a database that has no games yet
+func (s *SQLite) SeedIfEmpty(games []models.Game, scores []models.LeaderboardEntry) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var count int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM games`).Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	for _, game := range games {
+		if _, err := tx.Exec(`INSERT INTO games (`+gameColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			game.ID, game.Slug, game.Name, game.Description, encodeControls(game.Controls), game.Category,
+			game.Difficulty, game.HighScore, game.Rules.MaxPointsPerSecond, game.Rules.MaxSessionSeconds,
+			game.Enabled, game.Thumbnail); err != nil {
+			return fmt.Errorf("failed to seed game %s: %w", game.Name, err)
+		}
+	}
+	for _, entry := range scores {
+		if _, err := tx.Exec(`INSERT INTO scores (game_id, player, score, date, submitted_at)
+			SELECT id, ?2, ?3, ?4, ?5 FROM games WHERE `+gameByName,
+			entry.Game, entry.Player, entry.Score, entry.Date, entry.SubmittedAt.Unix()); err != nil {
+			return fmt.Errorf("failed to seed score for %s: %w", entry.Game, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return s.loadRankings()
+}
+
+const gameColumns = `id, slug, name, description, controls, category, difficulty, high_score,
+	max_points_per_second, max_session_seconds, enabled, thumbnail`
+
+// gameByName matches the game FindGame returns. Its one parameter is numbered
+// so that both comparisons bind it.
+const gameByName = `name = ?1 OR slug = lower(?1)`
+
+// gameClash selects whether a game other than the one with ID ?1 has the name
+// ?2 or slug ?3 as its name or slug. Names compare without case by the
+// column's collation; slugs are stored in lower case.
+const gameClash = `SELECT EXISTS (SELECT 1 FROM games
+	WHERE id != ?1 AND (name IN (?2, ?3) OR slug IN (lower(?2), lower(?3))))`
+
+func (s *SQLite) ListGames(filter GameFilter) ([]models.Game, error) {
+	rows, err := s.db.Query(`SELECT `+gameColumns+` FROM games
+		WHERE (? = '' OR category = ? COLLATE NOCASE) AND (? = '' OR difficulty = ? COLLATE NOCASE)
+		AND (? OR enabled)
+		ORDER BY id`, filter.Category, filter.Category, filter.Difficulty, filter.Difficulty, filter.IncludeDisabled)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	games := []models.Game{}
+	for rows.Next() {
+		game, err := scanGame(rows)
+		if err != nil {
+			return nil, err
+		}
+		games = append(games, game)
+	}
+	return games, rows.Err()
+}
+
+func (s *SQLite) GetGame(id int) (models.Game, error) {
+	return scanGame(s.db.QueryRow(`SELECT `+gameColumns+` FROM games WHERE id = ?`, id))
+}
+
+func (s *SQLite) FindGame(name string) (models.Game, error) {
+	return scanGame(s.db.QueryRow(`SELECT `+gameColumns+` FROM games WHERE `+gameByName, name))
+}
+
+func (s *SQLite) CreateGame(game models.Game) (models.Game, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return models.Game{}, err
+	}
+	defer tx.Rollback()
+
+	if err := checkGameClash(tx, game, 0); err != nil {
+		return models.Game{}, err
+	}
+	created, err := scanGame(tx.QueryRow(`INSERT INTO games (slug, name, description, controls, category,
+		difficulty, max_points_per_second, max_session_seconds, enabled, thumbnail)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING `+gameColumns,
+		game.Slug, game.Name, game.Description, encodeControls(game.Controls), game.Category, game.Difficulty,
+		game.Rules.MaxPointsPerSecond, game.Rules.MaxSessionSeconds, game.Enabled, game.Thumbnail))
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed: games.") {
+		return models.Game{}, ErrGameExists
+	}
+	if err != nil {
+		return models.Game{}, err
+	}
+	return created, tx.Commit()
+}
+
+func (s *SQLite) UpdateGame(game models.Game) (models.Game, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return models.Game{}, err
+	}
+	defer tx.Rollback()
+
+	if err := checkGameClash(tx, game, game.ID); err != nil {
+		return models.Game{}, err
+	}
+	updated, err := scanGame(tx.QueryRow(`UPDATE games SET slug = ?, name = ?, description = ?, controls = ?,
+		category = ?, difficulty = ?, max_points_per_second = ?, max_session_seconds = ?, enabled = ?, thumbnail = ?
+		WHERE id = ?
+		RETURNING `+gameColumns,
+		game.Slug, game.Name, game.Description, encodeControls(game.Controls), game.Category, game.Difficulty,
+		game.Rules.MaxPointsPerSecond, game.Rules.MaxSessionSeconds, game.Enabled, game.Thumbnail, game.ID))
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed: games.") {
+		return models.Game{}, ErrGameExists
+	}
+	if err != nil {
+		return models.Game{}, err
+	}
+	return updated, tx.Commit()
+}
+
+// checkGameClash returns ErrGameExists if a game other than the one with ID
+// except has the name or slug of game as its name or slug
+func checkGameClash(tx *sql.Tx, game models.Game, except int) error {
+	var clash bool
+	if err := tx.QueryRow(gameClash, except, game.Name, game.Slug).Scan(&clash); err != nil {
+		return err
+	}
+	if clash {
+		return ErrGameExists
+	}
+	return nil
+}
+
+func (s *SQLite) DeleteGame(id int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var hasScores bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM scores WHERE game_id = ?)`, id).Scan(&hasScores); err != nil {
+		return err
+	}
+	if hasScores {
+		return ErrGameHasScores
+	}
+	deleted, err := tx.Exec(`DELETE FROM games WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, err := deleted.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrNotFound
+	}
+	return tx.Commit()
+}
+
+func (s *SQLite) AddScore(gameID int, entry models.LeaderboardEntry) (ScoreResult, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return ScoreResult{}, err
+	}
+	defer tx.Rollback()
+
+	game, err := scanGame(tx.QueryRow(`SELECT `+gameColumns+` FROM games WHERE id = ?`, gameID))
+	if err != nil {
+		return ScoreResult{}, err
+	}
+	entry.Game = game.Name
+	inserted, err := tx.Exec(`INSERT INTO scores (game_id, player, player_id, score, date, submitted_at)
+		VALUES (?, ?, NULLIF(?, ''), ?, ?, ?)`,
+		game.ID, entry.Player, entry.PlayerID, entry.Score, entry.Date, entry.SubmittedAt.Unix())
+	if err != nil {
+		return ScoreResult{}, err
+	}
+	if entry.ID, err = inserted.LastInsertId(); err != nil {
+		return ScoreResult{}, err
+	}
+
+	isNewHighScore := entry.Score > game.HighScore
+	if isNewHighScore {
+		if _, err := tx.Exec(`UPDATE games SET high_score = ? WHERE id = ?`, entry.Score, game.ID); err != nil {
+			return ScoreResult{}, err
+		}
+		game.HighScore = entry.Score
+	}
+	rank, err := s.rankings.addOnCommit(game.ID, ranking.Item{Score: entry.Score, Seq: entry.ID}, tx.Commit)
+	if err != nil {
+		return ScoreResult{}, err
+	}
+	return ScoreResult{Entry: entry, Game: game, Rank: rank, IsNewHighScore: isNewHighScore}, nil
+}
+
+// scoreColumns selects a leaderboard entry from scores s joined to games g and
+// players p, listing a player's scores under their current display name
+const scoreColumns = `s.id, COALESCE(p.display_name, s.player), COALESCE(s.player_id, ''), g.name, s.score,
+	s.date, s.submitted_at`
+
+func (s *SQLite) ListScores(filter ScoreFilter) ([]models.LeaderboardEntry, error) {
+	// SQLite treats a negative LIMIT as no limit
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+	from, to := int64(math.MinInt64), int64(math.MaxInt64)
+	if !filter.From.IsZero() {
+		from = filter.From.Unix()
+	}
+	if !filter.To.IsZero() {
+		to = filter.To.Unix()
+	}
+
+	query := `SELECT ` + scoreColumns + `
+		FROM scores s JOIN games g ON g.id = s.game_id LEFT JOIN players p ON p.id = s.player_id
+		WHERE s.submitted_at >= ? AND s.submitted_at < ?
+		ORDER BY s.score DESC, s.id
+		LIMIT ?`
+	args := []interface{}{from, to, limit}
+	if filter.GameID != 0 {
+		// Matching on game_id lets one game's scores be read in rank order
+		// straight from the scores_game_rank index
+		query = `SELECT ` + scoreColumns + `
+		FROM scores s JOIN games g ON g.id = s.game_id LEFT JOIN players p ON p.id = s.player_id
+		WHERE s.game_id = ? AND s.submitted_at >= ? AND s.submitted_at < ?
+		ORDER BY s.score DESC, s.id
+		LIMIT ?`
+		args = []interface{}{filter.GameID, from, to, limit}
+	}
+
+	return s.queryScores(query, args...)
+}
+
+func (s *SQLite) ScoresAround(gameID int, player string, n int) ([]models.LeaderboardEntry, int, error) {
+	// Scores of a registered player are matched by ID, older scores by name
+	match := `player_id = ?`
+	if _, err := s.GetPlayer(player); errors.Is(err, ErrNotFound) {
+		match = `player_id IS NULL AND player = ?`
+	} else if err != nil {
+		return nil, 0, err
+	}
+	var best ranking.Item
+	err := s.db.QueryRow(`SELECT id, score FROM scores WHERE game_id = ? AND `+match+`
+		ORDER BY score DESC, id LIMIT 1`, gameID, player).Scan(&best.Seq, &best.Score)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, 0, ErrNotFound
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rank, from, items := s.rankings.around(gameID, best, n)
+	if len(items) == 0 {
+		return nil, 0, ErrNotFound
+	}
+	query := `SELECT ` + scoreColumns + `
+		FROM scores s JOIN games g ON g.id = s.game_id LEFT JOIN players p ON p.id = s.player_id
+		WHERE s.id IN (?` + strings.Repeat(", ?", len(items)-1) + `)`
+	args := make([]interface{}, len(items))
+	for i, item := range items {
+		args[i] = item.Seq
+	}
+	found, err := s.queryScores(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Put the scores in rank order
+	byID := make(map[int64]models.LeaderboardEntry, len(found))
+	for _, entry := range found {
+		byID[entry.ID] = entry
+	}
+	scores := make([]models.LeaderboardEntry, 0, len(items))
+	for i, item := range items {
+		if entry, ok := byID[item.Seq]; ok {
+			entry.Rank = from + i
+			scores = append(scores, entry)
+		}
+	}
+	return scores, rank, nil
+}
+
+// queryScores runs a query selecting scoreColumns
+func (s *SQLite) queryScores(query string, args ...interface{}) ([]models.LeaderboardEntry, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	scores := []models.LeaderboardEntry{}
+	for rows.Next() {
+		var entry models.LeaderboardEntry
+		var submittedAt int64
+		if err := rows.Scan(&entry.ID, &entry.Player, &entry.PlayerID, &entry.Game, &entry.Score, &entry.Date,
+			&submittedAt); err != nil {
+			return nil, err
+		}
+		entry.SubmittedAt = time.Unix(submittedAt, 0).UTC()
+		scores = append(scores, entry)
+	}
+	return scores, rows.Err()
+}
+
+func (s *SQLite) UseSession(id string, expiresAt time.Time) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM used_sessions WHERE expires_at < ?`, time.Now().Unix()); err != nil {
+		return err
+	}
+	result, err := tx.Exec(`INSERT INTO used_sessions (id, expires_at) VALUES (?, ?) ON CONFLICT (id) DO NOTHING`,
+		id, expiresAt.Unix())
+	if err != nil {
+		return err
+	}
+	if inserted, err := result.RowsAffected(); err != nil {
+		return err
+	} else if inserted == 0 {
+		return ErrSessionUsed
+	}
+	return tx.Commit()
+}
+
+const playerColumns = `id, COALESCE(username, ''), display_name, guest, created_at, password_hash`
+
+func (s *SQLite) RecordSession(session SessionStart) error {
+	_, err := s.db.Exec(`INSERT INTO game_sessions (id, player_id, game_id, date, started_at) VALUES (?, ?, ?, ?, ?)`,
+		session.ID, session.PlayerID, session.GameID, session.Date, session.StartedAt.Unix())
+	return err
+}
+
+func (s *SQLite) EndSession(id string, endedAt time.Time) error {
+	_, err := s.db.Exec(`UPDATE game_sessions SET ended_at = ? WHERE id = ?`, endedAt.Unix(), id)
+	return err
+}
+
+func (s *SQLite) CreatePlayer(player models.Player) error {
+	_, err := s.db.Exec(`INSERT INTO players (id, username, display_name, guest, created_at, password_hash) VALUES (?, NULLIF(?, ''), ?, ?, ?, ?)`,
+		player.ID, player.Username, player.DisplayName, player.Guest, player.CreatedAt.Unix(), player.PasswordHash)
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed: players.username") {
+		return ErrUsernameTaken
+	}
+	return err
+}
+
+func (s *SQLite) GetPlayer(id string) (models.Player, error) {
+	return scanPlayer(s.db.QueryRow(`SELECT `+playerColumns+` FROM players WHERE id = ?`, id))
+}
+
+func (s *SQLite) FindPlayer(username string) (models.Player, error) {
+	return scanPlayer(s.db.QueryRow(`SELECT `+playerColumns+` FROM players WHERE username = ?`, username))
+}
+
+func (s *SQLite) RenamePlayer(id, displayName string) (models.Player, error) {
+	return scanPlayer(s.db.QueryRow(`UPDATE players SET display_name = ? WHERE id = ? RETURNING `+playerColumns,
+		displayName, id))
+}
+
+func (s *SQLite) ClaimGuest(guestID, playerID string) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var guests int
+	err = tx.QueryRow(`SELECT COUNT(*) FROM players WHERE id = ? AND guest`, guestID).Scan(&guests)
+	if err != nil {
+		return 0, err
+	}
+	if guests == 0 {
+		return 0, ErrNotFound
+	}
+
+	result, err := tx.Exec(`UPDATE scores SET player_id = ? WHERE player_id = ?`, playerID, guestID)
+	if err != nil {
+		return 0, err
+	}
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(`UPDATE game_sessions SET player_id = ? WHERE player_id = ?`, playerID, guestID); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(`INSERT INTO player_achievements (player_id, achievement_id, unlocked_at)
+		SELECT ?, achievement_id, unlocked_at FROM player_achievements WHERE player_id = ?
+		ON CONFLICT DO NOTHING`, playerID, guestID); err != nil {
+		return 0, err
+	}
+	// Refresh tokens and achievements go with the guest by ON DELETE CASCADE
+	if _, err := tx.Exec(`DELETE FROM players WHERE id = ?`, guestID); err != nil {
+		return 0, err
+	}
+	return int(claimed), tx.Commit()
+}
+
+func (s *SQLite) SaveRefreshToken(hash, playerID string, expiresAt time.Time) error {
+	_, err := s.db.Exec(`INSERT INTO refresh_tokens (hash, player_id, expires_at) VALUES (?, ?, ?)`,
+		hash, playerID, expiresAt.Unix())
+	return err
+}
+
+func (s *SQLite) UseRefreshToken(hash string) (string, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM refresh_tokens WHERE expires_at < ?`, time.Now().Unix()); err != nil {
+		return "", err
+	}
+	var playerID string
+	err = tx.QueryRow(`DELETE FROM refresh_tokens WHERE hash = ? RETURNING player_id`, hash).Scan(&playerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return playerID, tx.Commit()
+}
+
+func (s *SQLite) PlayerActivity(playerID string) (achievements.Activity, error) {
+	activity := achievements.Activity{BestScores: make(map[string]int)}
+	err := s.db.QueryRow(`SELECT
+		(SELECT COUNT(*) FROM game_sessions WHERE player_id = ?1),
+		(SELECT COUNT(*) FROM scores WHERE player_id = ?1),
+		(SELECT COUNT(*) FROM (SELECT game_id FROM game_sessions WHERE player_id = ?1
+			UNION SELECT game_id FROM scores WHERE player_id = ?1))`, playerID).
+		Scan(&activity.Sessions, &activity.Scores, &activity.Games)
+	if err != nil {
+		return achievements.Activity{}, err
+	}
+
+	rows, err := s.db.Query(`SELECT g.slug, MAX(s.score) FROM scores s JOIN games g ON g.id = s.game_id
+		WHERE s.player_id = ? GROUP BY g.id`, playerID)
+	if err != nil {
+		return achievements.Activity{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var slug string
+		var best int
+		if err := rows.Scan(&slug, &best); err != nil {
+			return achievements.Activity{}, err
+		}
+		activity.BestScores[slug] = best
+	}
+	if err := rows.Err(); err != nil {
+		return achievements.Activity{}, err
+	}
+
+	days, err := s.db.Query(`SELECT date FROM game_sessions WHERE player_id = ?1
+		UNION SELECT date FROM scores WHERE player_id = ?1 ORDER BY date`, playerID)
+	if err != nil {
+		return achievements.Activity{}, err
+	}
+	defer days.Close()
+	for days.Next() {
+		var day string
+		if err := days.Scan(&day); err != nil {
+			return achievements.Activity{}, err
+		}
+		activity.Days = append(activity.Days, day)
+	}
+	return activity, days.Err()
+}
+
+func (s *SQLite) PlayerStats(playerID string, historyLength, recentLength int) (models.PlayerStats, error) {
+	// One read transaction sees the games, scores and sessions as of one
+	// moment, so a score committed between queries cannot belong to a game the
+	// first query did not list
+	tx, err := s.db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return models.PlayerStats{}, err
+	}
+	defer tx.Rollback()
+
+	stats := models.PlayerStats{Games: []models.PlayerGameStats{}, RecentActivity: []models.ActivityEvent{}}
+
+	// Each subquery reads one player's rows through the player indexes
+	rows, err := tx.Query(`SELECT g.id, g.name, g.slug, COALESCE(se.sessions, 0), COALESCE(sc.scores, 0),
+			COALESCE(se.play_time, 0), MAX(COALESCE(se.last, 0), COALESCE(sc.last, 0))
+		FROM games g
+		LEFT JOIN (SELECT game_id, COUNT(*) AS sessions, SUM(ended_at - started_at) AS play_time,
+				MAX(started_at) AS last
+			FROM game_sessions WHERE player_id = ?1 GROUP BY game_id) se ON se.game_id = g.id
+		LEFT JOIN (SELECT game_id, COUNT(*) AS scores, MAX(submitted_at) AS last
+			FROM scores WHERE player_id = ?1 GROUP BY game_id) sc ON sc.game_id = g.id
+		WHERE se.game_id IS NOT NULL OR sc.game_id IS NOT NULL
+		ORDER BY g.id`, playerID)
+	if err != nil {
+		return models.PlayerStats{}, err
+	}
+	defer rows.Close()
+	byGame := make(map[int]int)
+	for rows.Next() {
+		game := models.PlayerGameStats{History: []models.ScorePoint{}}
+		var lastPlayedAt int64
+		if err := rows.Scan(&game.GameID, &game.Game, &game.Slug, &game.SessionsStarted, &game.ScoresSubmitted,
+			&game.PlayTimeSeconds, &lastPlayedAt); err != nil {
+			return models.PlayerStats{}, err
+		}
+		game.LastPlayedAt = time.Unix(lastPlayedAt, 0).UTC()
+		byGame[game.GameID] = len(stats.Games)
+		stats.Games = append(stats.Games, game)
+	}
+	if err := rows.Err(); err != nil {
+		return models.PlayerStats{}, err
+	}
+
+	// The best score in each game, with ties going to the earliest as on the
+	// leaderboard, and the latest historyLength scores
+	scores, err := tx.Query(`SELECT game_id, id, score, submitted_at, best = 1, latest <= ?2 FROM (
+			SELECT game_id, id, score, submitted_at,
+				ROW_NUMBER() OVER (PARTITION BY game_id ORDER BY score DESC, id) AS best,
+				ROW_NUMBER() OVER (PARTITION BY game_id ORDER BY id DESC) AS latest
+			FROM scores WHERE player_id = ?1)
+		WHERE best = 1 OR latest <= ?2
+		ORDER BY game_id, id`, playerID, historyLength)
+	if err != nil {
+		return models.PlayerStats{}, err
+	}
+	defer scores.Close()
+	for scores.Next() {
+		var gameID int
+		var point models.ScorePoint
+		var submittedAt int64
+		var isBest, isLatest bool
+		if err := scores.Scan(&gameID, &point.EntryID, &point.Score, &submittedAt, &isBest, &isLatest); err != nil {
+			return models.PlayerStats{}, err
+		}
+		point.SubmittedAt = time.Unix(submittedAt, 0).UTC()
+		i, ok := byGame[gameID]
+		if !ok {
+			continue
+		}
+		game := &stats.Games[i]
+		if isBest {
+			game.BestScore = point.Score
+			game.Rank, game.TotalScores = s.rankings.rank(gameID, ranking.Item{Score: point.Score, Seq: point.EntryID})
+		}
+		if isLatest {
+			game.History = append(game.History, point)
+		}
+	}
+	if err := scores.Err(); err != nil {
+		return models.PlayerStats{}, err
+	}
+	addTotals(&stats)
+
+	// Times are whole seconds. Within one, a session is likelier to have been
+	// started after a score than to have lasted under a second, so it is newer.
+	recent, err := tx.Query(`SELECT kind, game, score, at FROM (
+			SELECT 'score' AS kind, g.name AS game, s.score AS score, s.submitted_at AS at
+			FROM scores s JOIN games g ON g.id = s.game_id WHERE s.player_id = ?1
+			UNION ALL
+			SELECT 'session', g.name, NULL, gs.started_at
+			FROM game_sessions gs JOIN games g ON g.id = gs.game_id WHERE gs.player_id = ?1)
+		ORDER BY at DESC, kind = 'session' DESC
+		LIMIT ?2`, playerID, recentLength)
+	if err != nil {
+		return models.PlayerStats{}, err
+	}
+	defer recent.Close()
+	for recent.Next() {
+		var event models.ActivityEvent
+		var at int64
+		if err := recent.Scan(&event.Type, &event.Game, &event.Score, &at); err != nil {
+			return models.PlayerStats{}, err
+		}
+		event.At = time.Unix(at, 0).UTC()
+		stats.RecentActivity = append(stats.RecentActivity, event)
+	}
+	return stats, recent.Err()
+}
+
+func (s *SQLite) UnlockAchievements(playerID string, ids []string, unlockedAt time.Time) ([]string, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	unlocked := []string{}
+	for _, id := range ids {
+		result, err := tx.Exec(`INSERT INTO player_achievements (player_id, achievement_id, unlocked_at)
+			VALUES (?, ?, ?) ON CONFLICT DO NOTHING`, playerID, id, unlockedAt.Unix())
+		if err != nil {
+			return nil, err
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return nil, err
+		} else if n > 0 {
+			unlocked = append(unlocked, id)
+		}
+	}
+	return unlocked, tx.Commit()
+}
+
+func (s *SQLite) ListAchievements(playerID string) (map[string]time.Time, error) {
+	rows, err := s.db.Query(`SELECT achievement_id, unlocked_at FROM player_achievements WHERE player_id = ?`, playerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	unlocked := make(map[string]time.Time)
+	for rows.Next() {
+		var id string
+		var unlockedAt int64
+		if err := rows.Scan(&id, &unlockedAt); err != nil {
+			return nil, err
+		}
+		unlocked[id] = time.Unix(unlockedAt, 0).UTC()
+	}
+	return unlocked, rows.Err()
+}
+
+// scanPlayer reads a row of playerColumns, mapping no row to ErrNotFound
+func scanPlayer(row interface{ Scan(...interface{}) error }) (models.Player, error) {
+	var player models.Player
+	var createdAt int64
+	err := row.Scan(&player.ID, &player.Username, &player.DisplayName, &player.Guest, &createdAt, &player.PasswordHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Player{}, ErrNotFound
+	}
+	player.CreatedAt = time.Unix(createdAt, 0).UTC()
+	return player, err
+}
+
+// scanGame reads a row of gameColumns, mapping no row to ErrNotFound
+func scanGame(row interface{ Scan(...interface{}) error }) (models.Game, error) {
+	var game models.Game
+	var controls string
+	err := row.Scan(&game.ID, &game.Slug, &game.Name, &game.Description, &controls, &game.Category,
+		&game.Difficulty, &game.HighScore, &game.Rules.MaxPointsPerSecond, &game.Rules.MaxSessionSeconds,
+		&game.Enabled, &game.Thumbnail)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Game{}, ErrNotFound
+	}
+	if err != nil {
+		return models.Game{}, err
+	}
+	if err := json.Unmarshal([]byte(controls), &game.Controls); err != nil {
+		return models.Game{}, fmt.Errorf("game %d has invalid controls: %w", game.ID, err)
+	}
+	return game, nil
+}
+
+// encodeControls returns the JSON array controls are stored as
+func encodeControls(controls []string) string {
+	if controls == nil {
+		return "[]"
+	}
+	encoded, _ := json.Marshal(controls)
+	return string(encoded)
+}
@@ -1,26 +1,54 @@
 package routes
 
 import (
+	"arcade-api/config"
 	"arcade-api/handlers"
+	"arcade-api/middleware"
 	"arcade-api/server"
 	"log"
 )
 
 // RegisterRoutes registers all API routes
-func RegisterRoutes() {
+func RegisterRoutes(h *handlers.Handler) {
 	log.Println("Registering routes...")
+	authenticated := middleware.Authenticate([]byte(config.AppConfig.AuthSecret))
 	
 	// Service info and health routes
-	server.API.GET("/", handlers.GetServiceInfo)
-	server.API.GET("/health", handlers.HealthCheck)
+	server.API.GET("/", h.GetServiceInfo)
+	server.API.GET("/health", h.HealthCheck)
 	
 	// Game routes
-	server.API.GET("/games", handlers.GetGames)
-	server.API.GET("/games/:id", handlers.GetGameByID)
+	server.API.GET("/games", h.GetGames)
+	server.API.GET("/games/:id", h.GetGameByID)
+	server.API.POST("/games/:id/sessions", authenticated, h.StartSession)
 	
 	// Leaderboard and score routes
-	server.API.GET("/leaderboard", handlers.GetLeaderboard)
-	server.API.POST("/score", handlers.SubmitScore)
+	server.API.GET("/leaderboard", h.GetLeaderboard)
+	server.API.GET("/leaderboard/archive", h.GetLeaderboardArchive)
+	server.API.GET("/leaderboard/around/:player", h.GetLeaderboardAround)
+	server.API.GET("/leaderboard/stream", h.StreamLeaderboard)
+	server.API.GET("/leaderboard/ws", h.StreamLeaderboardWebSocket)
+	server.API.POST("/score", authenticated, h.SubmitScore)
+	
+	// Player account routes
+	server.API.POST("/auth/register", h.Register)
+	server.API.POST("/auth/login", h.Login)
+	server.API.POST("/auth/guest", h.PlayAsGuest)
+	server.API.POST("/auth/refresh", h.RefreshTokens)
+	server.API.POST("/auth/logout", h.Logout)
+	server.API.GET("/players/me", authenticated, h.GetCurrentPlayer)
+	server.API.PATCH("/players/me", authenticated, h.UpdateCurrentPlayer)
+	server.API.POST("/players/me/claim", authenticated, h.ClaimGuestScores)
+	server.API.GET("/players/:id", h.GetPlayerProfile)
+	server.API.GET("/players/:id/stats", h.GetPlayerStats)
+	server.API.GET("/players/:id/achievements", h.GetPlayerAchievements)
+	
+	// Game catalog administration routes
+	admin := server.API.Group("/admin", middleware.RequireAdmin(config.AppConfig.AdminToken))
+	admin.GET("/games", h.ListAllGames)
+	admin.POST("/games", h.CreateGame)
+	admin.PUT("/games/:id", h.UpdateGame)
+	admin.DELETE("/games/:id", h.DeleteGame)
 	
 	log.Println("Routes registered successfully")
 }
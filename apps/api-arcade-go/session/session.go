@@ -0,0 +1,78 @@
+package session
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalid is returned for a token that is malformed or was not signed
+	// with the server's secret
+	ErrInvalid = errors.New("invalid session token")
+	// ErrExpired is returned for a correctly signed token past its expiry
+	ErrExpired = errors.New("session has expired")
+)
+
+// Claims identify one play of a game by a player. The seed lets the client
+// initialise its random number generator the way the server expects.
+type Claims struct {
+	ID        string `json:"sid"`
+	GameID    int    `json:"gid"`
+	PlayerID  string `json:"pid"`
+	Seed      int64  `json:"seed"`
+	StartedAt int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Started returns when the session was issued
+func (c Claims) Started() time.Time {
+	return time.Unix(c.StartedAt, 0)
+}
+
+// Expires returns when the session stops accepting a score
+func (c Claims) Expires() time.Time {
+	return time.Unix(c.ExpiresAt, 0)
+}
+
+// Issue signs claims with secret into a token of the form payload.signature,
+// both base64url encoded
+func Issue(claims Claims, secret []byte) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + sign(encoded, secret), nil
+}
+
+// Verify checks a token's signature and expiry and returns its claims
+func Verify(token string, secret []byte, now time.Time) (Claims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(sign(encoded, secret))) {
+		return Claims{}, ErrInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Claims{}, ErrInvalid
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.ID == "" {
+		return Claims{}, ErrInvalid
+	}
+	if !now.Before(claims.Expires()) {
+		return claims, ErrExpired
+	}
+	return claims, nil
+}
+
+func sign(payload string, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
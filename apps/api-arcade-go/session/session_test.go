@@ -0,0 +1,132 @@
+package session
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var secret = []byte("test-session-secret")
+
+func testClaims() Claims {
+	started := time.Unix(1700000000, 0)
+	return Claims{
+		ID:        "session-1",
+		GameID:    3,
+		PlayerID:  "player-1",
+		Seed:      42,
+		StartedAt: started.Unix(),
+		ExpiresAt: started.Add(time.Hour).Unix(),
+	}
+}
+
+// swapPayload returns token with its payload replaced by claims, keeping the
+// signature made over the original payload
+func swapPayload(t *testing.T, token string, claims Claims) string {
+	t.Helper()
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, signature, _ := strings.Cut(token, ".")
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + signature
+}
+
+func TestVerifyBindsSessionToGameAndPlayer(t *testing.T) {
+	claims := testClaims()
+	token, err := Issue(claims, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Verify(token, secret, claims.Started())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != claims {
+		t.Fatalf("claims %+v, want %+v", got, claims)
+	}
+
+	// A score submitted with another game, player or seed must not verify, nor
+	// one whose session was stretched
+	cases := []struct {
+		field  string
+		change func(*Claims)
+	}{
+		{"game", func(c *Claims) { c.GameID = 4 }},
+		{"player", func(c *Claims) { c.PlayerID = "player-2" }},
+		{"seed", func(c *Claims) { c.Seed = 43 }},
+		{"session", func(c *Claims) { c.ID = "session-2" }},
+		{"expiry", func(c *Claims) { c.ExpiresAt += 3600 }},
+	}
+	for _, tc := range cases {
+		changed := claims
+		tc.change(&changed)
+		if _, err := Verify(swapPayload(t, token, changed), secret, claims.Started()); !errors.Is(err, ErrInvalid) {
+			t.Errorf("changed %s: error %v, want %v", tc.field, err, ErrInvalid)
+		}
+	}
+
+	if _, err := Verify(token, []byte("other-secret"), claims.Started()); !errors.Is(err, ErrInvalid) {
+		t.Errorf("other secret: error %v, want %v", err, ErrInvalid)
+	}
+}
+
+func TestVerifyReportsExpiryWithClaims(t *testing.T) {
+	claims := testClaims()
+	token, err := Issue(claims, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		now  time.Time
+		want error
+	}{
+		{claims.Started(), nil},
+		{claims.Expires().Add(-time.Second), nil},
+		{claims.Expires(), ErrExpired},
+		{claims.Expires().Add(24 * time.Hour), ErrExpired},
+	}
+	for _, tc := range cases {
+		got, err := Verify(token, secret, tc.now)
+		if !errors.Is(err, tc.want) {
+			t.Fatalf("at %v: error %v, want %v", tc.now, err, tc.want)
+		}
+		// The handler names the expiry time when it rejects a late score
+		if got != claims {
+			t.Fatalf("at %v: claims %+v, want %+v", tc.now, got, claims)
+		}
+	}
+}
+
+func TestVerifyRejectsMalformedTokens(t *testing.T) {
+	token, err := Issue(testClaims(), secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, _, _ := strings.Cut(token, ".")
+
+	signed := func(payload string) string {
+		encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+		return encoded + "." + sign(encoded, secret)
+	}
+
+	for name, token := range map[string]string{
+		"empty":             "",
+		"no signature":      encoded,
+		"empty signature":   encoded + ".",
+		"extra part":        token + ".x",
+		"not base64":        "!!!." + sign("!!!", secret),
+		"not json":          signed(`not json`),
+		"no session id":     signed(`{"gid":1,"pid":"player-1"}`),
+		"access token form": "eyJhbGciOiJIUzI1NiJ9." + token,
+	} {
+		if _, err := Verify(token, secret, time.Unix(1700000000, 0)); !errors.Is(err, ErrInvalid) {
+			t.Errorf("%s: error %v, want %v", name, err, ErrInvalid)
+		}
+	}
+}
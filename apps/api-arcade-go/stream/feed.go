@@ -0,0 +1,113 @@
+package stream
+
+import "arcade-api/models"
+
+// Feed turns the scores of a game into the leaderboard events one subscriber
+// is sent, tracking the top entries the subscriber knows about. Scores are
+// only ever added, so keeping the top N of those seen keeps it exact, whatever
+// order concurrent submissions are published in.
+type Feed struct {
+	game string
+	size int
+	top  []models.LeaderboardEntry
+}
+
+// NewFeed returns a feed for game starting from snapshot, the game's current
+// top entries, and tracking the top size entries
+func NewFeed(game string, snapshot []models.LeaderboardEntry, size int) *Feed {
+	top := make([]models.LeaderboardEntry, 0, size+1)
+	for _, entry := range snapshot {
+		if len(top) == size {
+			break
+		}
+		entry.Rank = len(top) + 1
+		top = append(top, entry)
+	}
+	return &Feed{game: game, size: size, top: top}
+}
+
+// Snapshot returns the event describing the top entries the feed starts from
+func (f *Feed) Snapshot() models.LeaderboardEvent {
+	return f.event("snapshot", models.LeaderboardResponse{
+		Leaderboard: append([]models.LeaderboardEntry{}, f.top...),
+		Total:       len(f.top),
+	})
+}
+
+// Lagged returns the event telling a subscriber it fell behind and must
+// subscribe again
+func (f *Feed) Lagged() models.LeaderboardEvent {
+	return f.event("lagged", nil)
+}
+
+// Apply returns the events for a newly recorded score: the entry itself, the
+// rank changes if it reached the top entries, and a new high score. A score
+// already in the snapshot produces no events.
+func (f *Feed) Apply(score Score) []models.LeaderboardEvent {
+	position := len(f.top)
+	for i, entry := range f.top {
+		if entry.ID == score.Entry.ID {
+			return nil
+		}
+		if ranksAhead(score.Entry, entry) && position == len(f.top) {
+			position = i
+		}
+	}
+
+	// Entries carry their game's current name, which events follow if the game
+	// is renamed while the subscriber listens
+	if score.Entry.Game != "" {
+		f.game = score.Entry.Game
+	}
+
+	events := []models.LeaderboardEvent{
+		f.event("entry", models.ScoreEvent{Entry: score.Entry, Rank: score.Rank}),
+	}
+
+	if position < f.size {
+		f.top = append(f.top, models.LeaderboardEntry{})
+		copy(f.top[position+1:], f.top[position:])
+		f.top[position] = score.Entry
+		// A Rank of 0 records that the new entry was not in the top before
+		f.top[position].Rank = 0
+
+		changes := make([]models.RankChange, 0, len(f.top)-position)
+		for i := position; i < len(f.top); i++ {
+			to := i + 1
+			if to > f.size {
+				to = 0
+			}
+			changes = append(changes, models.RankChange{
+				EntryID: f.top[i].ID,
+				Player:  f.top[i].Player,
+				Score:   f.top[i].Score,
+				From:    f.top[i].Rank,
+				To:      to,
+			})
+			f.top[i].Rank = to
+		}
+		f.top = f.top[:min(len(f.top), f.size)]
+		events = append(events, f.event("ranks", models.RankChangesEvent{Changes: changes}))
+	}
+
+	if score.IsNewHighScore {
+		events = append(events, f.event("highscore", models.HighScoreEvent{
+			Entry:             score.Entry,
+			PreviousHighScore: score.PreviousHighScore,
+		}))
+	}
+	return events
+}
+
+func (f *Feed) event(kind string, data interface{}) models.LeaderboardEvent {
+	return models.LeaderboardEvent{Type: kind, Game: f.game, Data: data}
+}
+
+// ranksAhead reports whether a ranks above b: a higher score, or an equal
+// score submitted earlier
+func ranksAhead(a, b models.LeaderboardEntry) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	return a.ID < b.ID
+}
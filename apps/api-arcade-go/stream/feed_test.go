@@ -0,0 +1,140 @@
+package stream
+
+import (
+	"reflect"
+	"testing"
+
+	"arcade-api/models"
+)
+
+func entry(id int64, player string, score int) models.LeaderboardEntry {
+	return models.LeaderboardEntry{ID: id, Player: player, Game: "Snake", Score: score}
+}
+
+// types returns the type of each event, in order
+func types(events []models.LeaderboardEvent) []string {
+	kinds := []string{}
+	for _, event := range events {
+		kinds = append(kinds, event.Type)
+	}
+	return kinds
+}
+
+func TestFeedApply(t *testing.T) {
+	snapshot := []models.LeaderboardEntry{entry(1, "ada", 300), entry(2, "bob", 200), entry(3, "cy", 100)}
+
+	cases := []struct {
+		name    string
+		score   Score
+		types   []string
+		changes []models.RankChange
+		top     []int64
+	}{
+		{
+			name:  "already in the snapshot",
+			score: Score{Entry: entry(2, "bob", 200), Rank: 2},
+			types: []string{},
+			top:   []int64{1, 2, 3},
+		},
+		{
+			name:  "below the top",
+			score: Score{Entry: entry(4, "dee", 50), Rank: 4},
+			types: []string{"entry"},
+			top:   []int64{1, 2, 3},
+		},
+		{
+			// Equal to the last entry but later, so it ranks below it
+			name:  "tie with the last entry",
+			score: Score{Entry: entry(4, "dee", 100), Rank: 4},
+			types: []string{"entry"},
+			top:   []int64{1, 2, 3},
+		},
+		{
+			name:  "into the middle",
+			score: Score{Entry: entry(4, "dee", 250), Rank: 2},
+			types: []string{"entry", "ranks"},
+			changes: []models.RankChange{
+				{EntryID: 4, Player: "dee", Score: 250, From: 0, To: 2},
+				{EntryID: 2, Player: "bob", Score: 200, From: 2, To: 3},
+				{EntryID: 3, Player: "cy", Score: 100, From: 3, To: 0},
+			},
+			top: []int64{1, 4, 2},
+		},
+		{
+			name:  "new high score",
+			score: Score{Entry: entry(4, "dee", 500), Rank: 1, IsNewHighScore: true, PreviousHighScore: 300},
+			types: []string{"entry", "ranks", "highscore"},
+			changes: []models.RankChange{
+				{EntryID: 4, Player: "dee", Score: 500, From: 0, To: 1},
+				{EntryID: 1, Player: "ada", Score: 300, From: 1, To: 2},
+				{EntryID: 2, Player: "bob", Score: 200, From: 2, To: 3},
+				{EntryID: 3, Player: "cy", Score: 100, From: 3, To: 0},
+			},
+			top: []int64{4, 1, 2},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			feed := NewFeed("Snake", snapshot, 3)
+			events := feed.Apply(tc.score)
+			if got := types(events); !reflect.DeepEqual(got, tc.types) {
+				t.Fatalf("events %v, want %v", got, tc.types)
+			}
+			for _, event := range events {
+				if event.Type == "ranks" {
+					if got := event.Data.(models.RankChangesEvent).Changes; !reflect.DeepEqual(got, tc.changes) {
+						t.Fatalf("rank changes %+v, want %+v", got, tc.changes)
+					}
+				}
+			}
+
+			var top []int64
+			for i, entry := range feed.Snapshot().Data.(models.LeaderboardResponse).Leaderboard {
+				if entry.Rank != i+1 {
+					t.Fatalf("entry %d has rank %d, want %d", entry.ID, entry.Rank, i+1)
+				}
+				top = append(top, entry.ID)
+			}
+			if !reflect.DeepEqual(top, tc.top) {
+				t.Fatalf("top %v, want %v", top, tc.top)
+			}
+		})
+	}
+}
+
+func TestFeedApplyFillsAShortLeaderboard(t *testing.T) {
+	feed := NewFeed("Snake", nil, 2)
+	for _, score := range []Score{
+		{Entry: entry(1, "ada", 100), Rank: 1},
+		{Entry: entry(2, "bob", 200), Rank: 1},
+		// Published after a later submission, as concurrent scores can be
+		{Entry: entry(3, "cy", 150), Rank: 2},
+		{Entry: entry(3, "cy", 150), Rank: 2},
+	} {
+		feed.Apply(score)
+	}
+
+	var top []int64
+	for _, entry := range feed.Snapshot().Data.(models.LeaderboardResponse).Leaderboard {
+		top = append(top, entry.ID)
+	}
+	if want := []int64{2, 3}; !reflect.DeepEqual(top, want) {
+		t.Fatalf("top %v, want %v", top, want)
+	}
+}
+
+func TestFeedEventsFollowARenamedGame(t *testing.T) {
+	feed := NewFeed("Snake", []models.LeaderboardEntry{entry(1, "ada", 300)}, 3)
+	renamed := entry(2, "bob", 100)
+	renamed.Game = "Snake II"
+
+	for _, event := range feed.Apply(Score{Entry: renamed, Rank: 2}) {
+		if event.Game != "Snake II" {
+			t.Fatalf("%s event for %q, want the game's new name", event.Type, event.Game)
+		}
+	}
+	if game := feed.Lagged().Game; game != "Snake II" {
+		t.Fatalf("lagged event for %q, want the game's new name", game)
+	}
+}
@@ -0,0 +1,102 @@
+package stream
+
+import (
+	"arcade-api/models"
+	"sync"
+)
+
+// Score is a newly recorded score, as published when one is submitted
+type Score struct {
+	Entry             models.LeaderboardEntry
+	Rank              int
+	IsNewHighScore    bool
+	PreviousHighScore int
+}
+
+// Hub fans scores out to the subscribers of each game. Publishing never waits
+// on a subscriber: one whose buffer is full is dropped and marked lagged, and
+// must subscribe again to catch up.
+type Hub struct {
+	mu sync.Mutex
+	// subscribers maps game IDs to their subscriptions, so renaming a game
+	// keeps its subscribers
+	subscribers map[int]map[*Subscription]struct{}
+}
+
+// NewHub returns a hub with no subscribers
+func NewHub() *Hub {
+	return &Hub{subscribers: make(map[int]map[*Subscription]struct{})}
+}
+
+// Subscription receives the scores of one game
+type Subscription struct {
+	hub    *Hub
+	gameID int
+	scores chan Score
+	lagged bool
+}
+
+// Subscribe starts receiving the scores of the game with ID gameID, buffering
+// up to buffer of them for a subscriber that falls behind
+func (h *Hub) Subscribe(gameID int, buffer int) *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sub := &Subscription{hub: h, gameID: gameID, scores: make(chan Score, buffer)}
+	if h.subscribers[gameID] == nil {
+		h.subscribers[gameID] = make(map[*Subscription]struct{})
+	}
+	h.subscribers[gameID][sub] = struct{}{}
+	return sub
+}
+
+// Publish sends score to every subscriber of the game with ID gameID without
+// blocking
+func (h *Hub) Publish(gameID int, score Score) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscribers[gameID] {
+		select {
+		case sub.scores <- score:
+		default:
+			sub.lagged = true
+			h.remove(sub)
+		}
+	}
+}
+
+// remove unsubscribes sub and closes its channel. The caller must hold h.mu.
+func (h *Hub) remove(sub *Subscription) {
+	subs := h.subscribers[sub.gameID]
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(h.subscribers, sub.gameID)
+	}
+	close(sub.scores)
+}
+
+// Scores returns the channel scores arrive on. It is closed when the
+// subscription is closed or dropped for lagging.
+func (s *Subscription) Scores() <-chan Score {
+	return s.scores
+}
+
+// Lagged reports whether the subscription was dropped for falling behind
+func (s *Subscription) Lagged() bool {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	return s.lagged
+}
+
+// Close unsubscribes. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	s.hub.remove(s)
+}
@@ -0,0 +1,51 @@
+package stream
+
+import "testing"
+
+func TestHubDeliversScoresByGameID(t *testing.T) {
+	hub := NewHub()
+	snake := hub.Subscribe(1, 4)
+	defer snake.Close()
+	pong := hub.Subscribe(2, 4)
+	defer pong.Close()
+
+	// The game's name in the entry does not decide who receives it, so a
+	// renamed game keeps its subscribers
+	hub.Publish(1, Score{Entry: entry(1, "ada", 100)})
+	hub.Publish(1, Score{Entry: entry(2, "bob", 200)})
+	hub.Publish(3, Score{Entry: entry(3, "cy", 300)})
+
+	if got := len(snake.Scores()); got != 2 {
+		t.Fatalf("game 1 subscriber has %d scores, want 2", got)
+	}
+	if got := len(pong.Scores()); got != 0 {
+		t.Fatalf("game 2 subscriber has %d scores, want 0", got)
+	}
+}
+
+func TestHubDropsLaggingSubscribers(t *testing.T) {
+	hub := NewHub()
+	slow := hub.Subscribe(1, 1)
+	fast := hub.Subscribe(1, 4)
+	defer fast.Close()
+
+	hub.Publish(1, Score{Entry: entry(1, "ada", 100)})
+	hub.Publish(1, Score{Entry: entry(2, "bob", 200)})
+
+	if !slow.Lagged() || fast.Lagged() {
+		t.Fatalf("lagged: slow %v, fast %v; want only slow", slow.Lagged(), fast.Lagged())
+	}
+	// The buffered score is still delivered before the channel closes
+	if _, ok := <-slow.Scores(); !ok {
+		t.Fatal("buffered score lost")
+	}
+	if _, ok := <-slow.Scores(); ok {
+		t.Fatal("lagging subscription still open")
+	}
+	slow.Close()
+
+	hub.Publish(1, Score{Entry: entry(3, "cy", 300)})
+	if got := len(fast.Scores()); got != 3 {
+		t.Fatalf("remaining subscriber has %d scores, want 3", got)
+	}
+}
@@ -0,0 +1,200 @@
+package calculators
+
+import (
+	"finclamp-api/explain"
+	"finclamp-api/marketdata"
+	"finclamp-api/models"
+	"finclamp-api/registry"
+	"finclamp-api/utils"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Backtest replays a SIP or lump-sum plan over every rolling period of an
+// index's historical monthly returns
+func Backtest(req models.BacktestRequest, t *explain.Trace) (models.BacktestResponse, error) {
+	// Set default mode if not provided
+	if req.Mode == "" {
+		req.Mode = "sip"
+	}
+	req.Mode = strings.ToLower(req.Mode)
+	if req.Mode != "sip" && req.Mode != "lumpsum" {
+		return models.BacktestResponse{}, &registry.InputError{Message: "Invalid mode", Detail: "Mode must be one of: sip, lumpsum"}
+	}
+
+	dataset := marketdata.Source()
+	returns, ok := marketdata.Series(req.Index)
+	if !ok {
+		return models.BacktestResponse{}, &registry.InputError{Message: "Invalid index", Detail: "Available indices: " + strings.Join(marketdata.Indices(), ", ")}
+	}
+
+	periodMonths := req.Term * 12
+	if periodMonths > len(returns) {
+		return models.BacktestResponse{}, &registry.InputError{Message: "Term too long",
+			Detail: fmt.Sprintf("%s has %d months of data, term requires %d", req.Index, len(returns), periodMonths)}
+	}
+
+	numPeriods := int(t.Step("Count the rolling start months", "periods = months of data − term × 12 + 1",
+		map[string]float64{"months": float64(len(returns)), "term": float64(req.Term)},
+		float64(len(returns)-periodMonths+1)))
+
+	periods := make([]models.BacktestPeriod, 0, numPeriods)
+	for start := 0; start < numPeriods; start++ {
+		periods = append(periods, replayPeriod(returns[start:start+periodMonths], req.Mode, req.Amount))
+	}
+
+	best, worst, deepest := periods[0], periods[0], periods[0]
+	annualized := make([]float64, len(periods))
+	losses := 0
+	for i, period := range periods {
+		annualized[i] = period.AnnualizedReturn
+		if period.FinalValue < period.TotalInvested {
+			losses++
+		}
+		if period.AnnualizedReturn > best.AnnualizedReturn {
+			best = period
+		}
+		if period.AnnualizedReturn < worst.AnnualizedReturn {
+			worst = period
+		}
+		if period.MaxDrawdown > deepest.MaxDrawdown {
+			deepest = period
+		}
+	}
+
+	if req.Mode == "sip" {
+		t.Step("Replay each period investing at the start of every month; annualize with the IRR",
+			"FV = Σ amount × Π(1 + rₘ); (1 + irr)^12 − 1", map[string]float64{"amount": req.Amount}, float64(len(periods)))
+	} else {
+		t.Step("Replay each period investing once at the start; annualize with the CAGR",
+			"FV = amount × Π(1 + rₘ); (FV / amount)^(12 / months) − 1", map[string]float64{"amount": req.Amount}, float64(len(periods)))
+	}
+	t.Step("Best annualized return, starting "+best.StartMonth, "max(annualized)", nil, best.AnnualizedReturn)
+	t.Step("Worst annualized return, starting "+worst.StartMonth, "min(annualized)", nil, worst.AnnualizedReturn)
+	t.Step("Deepest peak-to-trough index decline, starting "+deepest.StartMonth, "max(1 − nav / peak) × 100", nil, deepest.MaxDrawdown)
+
+	distribution := summarizeReturns(annualized, losses, t)
+
+	return models.BacktestResponse{
+		Index:           strings.ToLower(req.Index),
+		Mode:            req.Mode,
+		PeriodMonths:    periodMonths,
+		DataFrom:        returns[0].Month.Format("2006-01"),
+		DataTo:          returns[len(returns)-1].Month.Format("2006-01"),
+		DataSource:      dataset.Source,
+		Illustrative:    dataset.Illustrative,
+		PeriodsTested:   len(periods),
+		Distribution:    distribution,
+		Best:            best,
+		Worst:           worst,
+		DeepestDrawdown: deepest,
+		Explanation:     t.Steps(),
+	}, nil
+}
+
+// replayPeriod invests according to the plan over one window of monthly returns.
+// SIP instalments are made at the start of each month; lump sums at the start of
+// the window. Drawdown is measured on the index value so contributions don't mask it.
+func replayPeriod(window []marketdata.MonthlyReturn, mode string, amount float64) models.BacktestPeriod {
+	value, invested := 0.0, 0.0
+	nav, peak, maxDrawdown := 1.0, 1.0, 0.0
+
+	for i, month := range window {
+		if mode == "sip" || i == 0 {
+			value += amount
+			invested += amount
+		}
+		value *= 1 + month.Return
+
+		nav *= 1 + month.Return
+		peak = math.Max(peak, nav)
+		maxDrawdown = math.Max(maxDrawdown, 1-nav/peak)
+	}
+
+	var annualized float64
+	if mode == "sip" {
+		annualized = sipAnnualizedReturn(amount, len(window), value)
+	} else {
+		annualized = math.Pow(value/invested, 12/float64(len(window))) - 1
+	}
+
+	return models.BacktestPeriod{
+		StartMonth:       window[0].Month.Format("2006-01"),
+		EndMonth:         window[len(window)-1].Month.Format("2006-01"),
+		TotalInvested:    utils.RoundToTwoDecimals(invested),
+		FinalValue:       utils.RoundToTwoDecimals(value),
+		AnnualizedReturn: utils.RoundToTwoDecimals(annualized * 100),
+		MaxDrawdown:      utils.RoundToTwoDecimals(maxDrawdown * 100),
+	}
+}
+
+// sipAnnualizedReturn solves for the monthly rate (an IRR) that grows equal
+// start-of-month instalments into the final value, then annualizes it
+func sipAnnualizedReturn(instalment float64, months int, finalValue float64) float64 {
+	futureValue := func(rate float64) float64 {
+		total := 0.0
+		for i := 0; i < months; i++ {
+			total = (total + instalment) * (1 + rate)
+		}
+		return total
+	}
+
+	low, high := -0.99, 1.0
+	for i := 0; i < 200; i++ {
+		mid := (low + high) / 2
+		if futureValue(mid) < finalValue {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+
+	return math.Pow(1+(low+high)/2, 12) - 1
+}
+
+// summarizeReturns builds the distribution of annualized returns (in percent)
+func summarizeReturns(annualized []float64, losses int, t *explain.Trace) models.ReturnDistribution {
+	sorted := append([]float64(nil), annualized...)
+	sort.Float64s(sorted)
+
+	sum := 0.0
+	for _, r := range sorted {
+		sum += r
+	}
+
+	count := float64(len(sorted))
+	median := t.Step("Median annualized return", "linear interpolation at rank 0.5 × (periods − 1)",
+		map[string]float64{"periods": count}, percentile(sorted, 50))
+	mean := t.Step("Mean annualized return", "mean = Σ annualized / periods",
+		map[string]float64{"sum": sum, "periods": count}, sum/count)
+	lossProbability := t.Step("Share of periods ending below the amount invested", "loss% = losing periods / periods × 100",
+		map[string]float64{"losing": float64(losses), "periods": count}, float64(losses)/count*100)
+
+	return models.ReturnDistribution{
+		Min:             sorted[0],
+		P10:             utils.RoundToTwoDecimals(percentile(sorted, 10)),
+		P25:             utils.RoundToTwoDecimals(percentile(sorted, 25)),
+		Median:          utils.RoundToTwoDecimals(median),
+		P75:             utils.RoundToTwoDecimals(percentile(sorted, 75)),
+		P90:             utils.RoundToTwoDecimals(percentile(sorted, 90)),
+		Max:             sorted[len(sorted)-1],
+		Mean:            utils.RoundToTwoDecimals(mean),
+		LossProbability: utils.RoundToTwoDecimals(lossProbability),
+	}
+}
+
+// percentile returns the p-th percentile of sorted values using linear interpolation
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	weight := rank - float64(lower)
+
+	return sorted[lower]*(1-weight) + sorted[upper]*weight
+}
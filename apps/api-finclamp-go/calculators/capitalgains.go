@@ -0,0 +1,266 @@
+package calculators
+
+import (
+	"finclamp-api/explain"
+	"finclamp-api/indexation"
+	"finclamp-api/models"
+	"finclamp-api/registry"
+	"finclamp-api/utils"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// capitalGainsRules holds the tax rules applied to property sales by resident
+// individuals. Surcharge is not modelled.
+type capitalGainsRules struct {
+	LongTermMonths  int       // held for more than this many months is long-term
+	IndexedRate     float64   // long-term rate with indexation, percent
+	FlatRate        float64   // long-term rate without indexation, percent
+	FlatRateFrom    time.Time // sales on or after this date use FlatRate; earlier purchases may still choose IndexedRate
+	IndexationBase  time.Time // costs before this date are replaced by the fair market value on it
+	CessPercent     float64
+	Section54Cap    float64
+	Section54FCap   float64
+	Section54ECCap  float64
+	DefaultSlabRate float64
+}
+
+var propertyRules = capitalGainsRules{
+	LongTermMonths:  24,
+	IndexedRate:     20,
+	FlatRate:        12.5,
+	FlatRateFrom:    time.Date(2024, time.July, 23, 0, 0, 0, 0, time.UTC),
+	IndexationBase:  time.Date(2001, time.April, 1, 0, 0, 0, 0, time.UTC),
+	CessPercent:     4,
+	Section54Cap:    100000000,
+	Section54FCap:   100000000,
+	Section54ECCap:  5000000,
+	DefaultSlabRate: 30,
+}
+
+// exemptionOrder applies house reinvestments before capital gains bonds
+var exemptionOrder = map[string]int{"54": 0, "54F": 0, "54EC": 1}
+
+// PropertyCapitalGains computes the capital gain and tax on a property sale.
+// Long-term gains are computed with and without indexation where the rules
+// allow a choice, and the method with the lower tax is selected.
+func PropertyCapitalGains(req models.PropertyCapitalGainsRequest, t *explain.Trace) (models.PropertyCapitalGainsResponse, error) {
+	rules := propertyRules
+
+	purchase, err := time.Parse(dateLayout, req.PurchaseDate)
+	if err != nil {
+		return models.PropertyCapitalGainsResponse{}, &registry.InputError{Message: "Invalid purchase date", Detail: "purchaseDate must use the YYYY-MM-DD format"}
+	}
+	sale, err := time.Parse(dateLayout, req.SaleDate)
+	if err != nil {
+		return models.PropertyCapitalGainsResponse{}, &registry.InputError{Message: "Invalid sale date", Detail: "saleDate must use the YYYY-MM-DD format"}
+	}
+	if !sale.After(purchase) {
+		return models.PropertyCapitalGainsResponse{}, &registry.InputError{Message: "Invalid sale date", Detail: "saleDate must be after purchaseDate"}
+	}
+
+	holdingMonths := wholeMonthsBetween(purchase, sale)
+	longTerm := sale.After(purchase.AddDate(0, rules.LongTermMonths, 0))
+	t.Step(fmt.Sprintf("Holding period in whole months (long-term if more than %d)", rules.LongTermMonths),
+		"months from purchaseDate to saleDate", nil, float64(holdingMonths))
+
+	// Cost of acquisition, substituting the 1 April 2001 fair market value for older purchases
+	cost := req.PurchasePrice
+	if purchase.Before(rules.IndexationBase) && req.FairMarketValue2001 > cost {
+		cost = t.Step("Use the fair market value on 1 April 2001 as the cost", "cost = max(purchasePrice, FMV 2001)",
+			map[string]float64{"purchasePrice": req.PurchasePrice, "fmv2001": req.FairMarketValue2001}, req.FairMarketValue2001)
+	}
+	acquiredOn := purchase
+	if purchase.Before(rules.IndexationBase) {
+		acquiredOn = rules.IndexationBase
+	}
+
+	netConsideration := t.Step("Net sale consideration", "net = salePrice − transferExpenses",
+		map[string]float64{"salePrice": req.SalePrice, "transferExpenses": req.TransferExpenses}, req.SalePrice-req.TransferExpenses)
+
+	saleYear := indexation.FinancialYear(sale)
+	response := models.PropertyCapitalGainsResponse{
+		HoldingMonths:        holdingMonths,
+		Term:                 "short",
+		PurchaseYear:         indexation.FinancialYear(purchase),
+		SaleYear:             saleYear,
+		NetSaleConsideration: utils.RoundToTwoDecimals(netConsideration),
+		CostOfAcquisition:    utils.RoundToTwoDecimals(cost),
+	}
+	if longTerm {
+		response.Term = "long"
+	}
+
+	type improvement struct {
+		date time.Time
+		cost float64
+	}
+	var improvements []improvement
+	improvementCost := 0.0
+	for _, imp := range req.Improvements {
+		date, err := time.Parse(dateLayout, imp.Date)
+		if err != nil {
+			return models.PropertyCapitalGainsResponse{}, &registry.InputError{Message: "Invalid improvement date", Detail: "improvement dates must use the YYYY-MM-DD format"}
+		}
+		if date.Before(purchase) || date.After(sale) {
+			return models.PropertyCapitalGainsResponse{}, &registry.InputError{Message: "Invalid improvement date", Detail: fmt.Sprintf("improvement on %s is outside the holding period", imp.Date)}
+		}
+		// Improvements made before the indexation base date are not deductible
+		if date.Before(rules.IndexationBase) {
+			continue
+		}
+		improvements = append(improvements, improvement{date: date, cost: imp.Cost})
+		improvementCost += imp.Cost
+	}
+	response.CostOfImprovement = utils.RoundToTwoDecimals(improvementCost)
+
+	var options []models.CapitalGainsOption
+	if !longTerm {
+		slabRate := req.SlabRate
+		if slabRate == 0 {
+			slabRate = rules.DefaultSlabRate
+		}
+		deduction := cost + improvementCost
+		gain := t.Step("Short-term gain", "gain = net − cost − improvements",
+			map[string]float64{"net": netConsideration, "cost": cost, "improvements": improvementCost}, netConsideration-deduction)
+		options = append(options, gainsOption("slab", deduction, gain, 0, nil, slabRate, rules, t))
+	} else {
+		if sale.Before(rules.FlatRateFrom) || purchase.Before(rules.FlatRateFrom) {
+			saleIndex, ok := indexation.Index(saleYear)
+			if !ok {
+				first, last := indexation.Range()
+				return models.PropertyCapitalGainsResponse{}, &registry.InputError{Message: "Cost inflation index unavailable",
+					Detail: fmt.Sprintf("no index for %s; the table covers %s to %s", saleYear, first, last)}
+			}
+			purchaseIndex, _ := indexation.Index(indexation.FinancialYear(acquiredOn))
+
+			indexedCost := t.Step("Index the cost of acquisition", "indexedCost = cost × CII(sale year) / CII(purchase year)",
+				map[string]float64{"cost": cost, "saleCII": saleIndex, "purchaseCII": purchaseIndex}, cost*saleIndex/purchaseIndex)
+			indexedImprovements := 0.0
+			for _, imp := range improvements {
+				improvementIndex, _ := indexation.Index(indexation.FinancialYear(imp.date))
+				indexedImprovements += t.Step("Index improvement made in "+indexation.FinancialYear(imp.date),
+					"indexedImprovement = cost × CII(sale year) / CII(improvement year)",
+					map[string]float64{"cost": imp.cost, "saleCII": saleIndex, "improvementCII": improvementIndex}, imp.cost*saleIndex/improvementIndex)
+			}
+			response.IndexedCostOfAcquisition = utils.RoundToTwoDecimals(indexedCost)
+			response.IndexedCostOfImprovement = utils.RoundToTwoDecimals(indexedImprovements)
+
+			deduction := indexedCost + indexedImprovements
+			gain := t.Step("Long-term gain with indexation", "gain = net − indexedCost − indexedImprovements",
+				map[string]float64{"net": netConsideration, "indexedCost": indexedCost, "indexedImprovements": indexedImprovements}, netConsideration-deduction)
+			exemptions, exempt := applyExemptions(req, gain, netConsideration, rules, t)
+			options = append(options, gainsOption("indexed", deduction, gain, exempt, exemptions, rules.IndexedRate, rules, t))
+		}
+
+		if !sale.Before(rules.FlatRateFrom) {
+			deduction := cost + improvementCost
+			gain := t.Step("Long-term gain without indexation", "gain = net − cost − improvements",
+				map[string]float64{"net": netConsideration, "cost": cost, "improvements": improvementCost}, netConsideration-deduction)
+			exemptions, exempt := applyExemptions(req, gain, netConsideration, rules, t)
+			options = append(options, gainsOption("unindexed", deduction, gain, exempt, exemptions, rules.FlatRate, rules, t))
+		}
+	}
+
+	selected := options[0]
+	for _, option := range options[1:] {
+		if option.TotalTax < selected.TotalTax {
+			selected = option
+		}
+	}
+
+	response.Options = options
+	response.SelectedMethod = selected.Method
+	response.TaxableGain = selected.TaxableGain
+	response.TotalTax = selected.TotalTax
+	response.Explanation = t.Steps()
+
+	return response, nil
+}
+
+// gainsOption taxes a gain net of exemptions at rate plus cess. Losses are not taxed.
+func gainsOption(method string, deduction, gain, exempt float64, exemptions []models.ExemptionApplied, rate float64, rules capitalGainsRules, t *explain.Trace) models.CapitalGainsOption {
+	if exemptions == nil {
+		exemptions = []models.ExemptionApplied{}
+	}
+
+	taxable := math.Max(gain-exempt, 0)
+	tax := t.Step("Tax on the "+method+" gain", "tax = taxableGain × rate / 100",
+		map[string]float64{"taxableGain": taxable, "rate": rate}, taxable*rate/100)
+	cess := t.Step("Health and education cess", "cess = tax × cessPercent / 100",
+		map[string]float64{"tax": tax, "cessPercent": rules.CessPercent}, tax*rules.CessPercent/100)
+
+	return models.CapitalGainsOption{
+		Method:        method,
+		CostDeduction: utils.RoundToTwoDecimals(deduction),
+		Gain:          utils.RoundToTwoDecimals(gain),
+		Exemptions:    exemptions,
+		TaxableGain:   utils.RoundToTwoDecimals(taxable),
+		TaxRate:       rate,
+		Tax:           utils.RoundToTwoDecimals(tax),
+		Cess:          utils.RoundToTwoDecimals(cess),
+		TotalTax:      utils.RoundToTwoDecimals(tax + cess),
+	}
+}
+
+// applyExemptions applies reinvestment exemptions to a long-term gain and
+// returns the exemptions with the total exempt amount. Section 54 requires the
+// property sold to be a residential house and section 54F requires it not to
+// be; 54F exempts the gain in proportion to the consideration reinvested.
+func applyExemptions(req models.PropertyCapitalGainsRequest, gain, netConsideration float64, rules capitalGainsRules, t *explain.Trace) ([]models.ExemptionApplied, float64) {
+	reinvestments := append([]models.GainsReinvestment(nil), req.Reinvestments...)
+	sort.SliceStable(reinvestments, func(i, j int) bool {
+		return exemptionOrder[reinvestments[i].Section] < exemptionOrder[reinvestments[j].Section]
+	})
+
+	remaining := math.Max(gain, 0)
+	used := map[string]float64{}
+	caps := map[string]float64{"54": rules.Section54Cap, "54F": rules.Section54FCap, "54EC": rules.Section54ECCap}
+
+	applied := make([]models.ExemptionApplied, 0, len(reinvestments))
+	total := 0.0
+	for _, r := range reinvestments {
+		exemption := models.ExemptionApplied{Section: r.Section, Invested: r.Amount}
+
+		var eligible float64
+		switch {
+		case r.Section == "54" && !req.ResidentialProperty:
+			exemption.Note = "Section 54 applies only when a residential house is sold"
+		case r.Section == "54F" && req.ResidentialProperty:
+			exemption.Note = "Section 54F applies only when the asset sold is not a residential house"
+		case r.Section == "54F":
+			eligible = gain * math.Min(r.Amount/netConsideration, 1)
+		default:
+			eligible = r.Amount
+		}
+
+		capLeft := caps[r.Section] - used[r.Section]
+		exempt := math.Max(math.Min(math.Min(eligible, remaining), capLeft), 0)
+		if exempt < eligible && exemption.Note == "" && exempt == capLeft {
+			exemption.Note = fmt.Sprintf("Limited to the section %s cap", r.Section)
+		}
+		exempt = t.Step("Exemption under section "+r.Section, "exempt = min(eligible, remaining gain, cap left)",
+			map[string]float64{"eligible": eligible, "remaining": remaining, "capLeft": capLeft}, exempt)
+
+		used[r.Section] += exempt
+		remaining -= exempt
+		total += exempt
+		exemption.Exempt = utils.RoundToTwoDecimals(exempt)
+		applied = append(applied, exemption)
+	}
+
+	return applied, total
+}
+
+// wholeMonthsBetween returns the number of complete months from start to end
+func wholeMonthsBetween(start, end time.Time) int {
+	months := (end.Year()-start.Year())*12 + int(end.Month()) - int(start.Month())
+	if end.Day() < start.Day() {
+		months--
+	}
+	return months
+}
@@ -0,0 +1,144 @@
+package calculators
+
+import "finclamp-api/registry"
+
+func init() {
+	registry.Register(
+		registry.Define(registry.Meta{
+			Name:        "loan",
+			Title:       "Loan calculation",
+			Description: "Loan calculations",
+			Version:     "1.0.0",
+		}, registry.Infallible(Loan)),
+		registry.Define(registry.Meta{
+			Name:        "savings",
+			Title:       "Savings calculation",
+			Description: "Savings calculations",
+			Version:     "1.0.0",
+		}, registry.Infallible(Savings)),
+		registry.Define(registry.Meta{
+			Name:        "investment",
+			Title:       "Investment calculation",
+			Description: "Investment calculations",
+			Version:     "1.0.0",
+		}, registry.Infallible(Investment)),
+		registry.Define(registry.Meta{
+			Name:        "refinance",
+			Title:       "Refinance comparison",
+			Description: "Loan refinance comparison",
+			Version:     "1.0.0",
+		}, registry.Infallible(Refinance)),
+		registry.Define(registry.Meta{
+			Name:        "backtest",
+			Title:       "Backtest",
+			Description: "SIP/lump-sum backtest over the loaded index returns (illustrative unless marked otherwise)",
+			Version:     "1.0.0",
+		}, Backtest),
+		registry.Define(registry.Meta{
+			Name:        "goals",
+			Title:       "Goal plan",
+			Description: "Multi-goal savings allocation plan",
+			Version:     "1.0.0",
+		}, GoalPlan),
+		registry.Define(registry.Meta{
+			Name:        "rebalance",
+			Title:       "Portfolio rebalance",
+			Description: "Portfolio rebalancing trades",
+			Version:     "1.0.0",
+		}, Rebalance),
+		registry.Define(registry.Meta{
+			Name:        "convert",
+			Title:       "Currency conversion",
+			Description: "Currency conversion with offline rates",
+			Version:     "1.0.0",
+		}, Convert),
+		registry.Define(registry.Meta{
+			Name:        "property-capital-gains",
+			Title:       "Property capital gains calculation",
+			Description: "Capital gains tax on property with cost inflation indexation",
+			Version:     "1.0.0",
+		}, PropertyCapitalGains),
+		registry.Define(registry.Meta{
+			Name:        "life-insurance",
+			Title:       "Life insurance needs analysis",
+			Description: "Term cover needed by human life value and expense replacement",
+			Version:     "1.0.0",
+		}, LifeInsurance),
+		registry.Define(registry.Meta{
+			Name:        "emergency-fund",
+			Title:       "Emergency fund calculation",
+			Description: "Emergency fund target from expenses and job stability",
+			Version:     "1.0.0",
+		}, EmergencyFund),
+		registry.Define(registry.Meta{
+			Name:        "gratuity",
+			Title:       "Gratuity calculation",
+			Description: "Gratuity under the Payment of Gratuity Act with tax exemption",
+			Version:     "1.0.0",
+			Group:       "salary",
+		}, Gratuity),
+		registry.Define(registry.Meta{
+			Name:        "hra-exemption",
+			Title:       "HRA exemption calculation",
+			Description: "House rent allowance exemption for metro and non-metro cities",
+			Version:     "1.0.0",
+			Group:       "salary",
+		}, HRAExemption),
+		registry.Define(registry.Meta{
+			Name:        "salary-structure",
+			Title:       "Salary structure calculation",
+			Description: "CTC to in-hand salary with income tax under the old or new regime",
+			Version:     "1.0.0",
+			Group:       "salary",
+		}, SalaryStructure),
+		registry.Define(registry.Meta{
+			Name:        "tip",
+			Title:       "Tip calculation",
+			Description: "Tip and bill split",
+			Version:     "1.0.0",
+			Group:       "everyday",
+		}, Tip),
+		registry.Define(registry.Meta{
+			Name:        "discount",
+			Title:       "Discount calculation",
+			Description: "Stacked discounts with tax",
+			Version:     "1.0.0",
+			Group:       "everyday",
+		}, Discount),
+		registry.Define(registry.Meta{
+			Name:        "fuel-cost",
+			Title:       "Fuel cost calculation",
+			Description: "Fuel cost for a distance driven per period",
+			Version:     "1.0.0",
+			Group:       "everyday",
+		}, FuelCost),
+		registry.Define(registry.Meta{
+			Name:        "commute",
+			Title:       "Commute comparison",
+			Description: "Commute cost comparison across modes",
+			Version:     "1.0.0",
+			Group:       "everyday",
+		}, Commute),
+		registry.Define(registry.Meta{
+			Name:        "subscriptions",
+			Title:       "Subscription cost calculation",
+			Description: "Recurring subscription costs",
+			Version:     "1.0.0",
+			Group:       "everyday",
+		}, Subscriptions),
+		registry.Define(registry.Meta{
+			Name:        "habits",
+			Title:       "Habit cost calculation",
+			Description: "Cost of recurring habits",
+			Version:     "1.0.0",
+			Group:       "everyday",
+		}, HabitCost),
+		registry.Define(registry.Meta{
+			Name:        "wfh-savings",
+			Title:       "Work-from-home savings calculation",
+			Description: "Work-from-home savings",
+			Version:     "1.0.0",
+			Group:       "everyday",
+		}, WFHSavings),
+	)
+}
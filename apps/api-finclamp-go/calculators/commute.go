@@ -0,0 +1,86 @@
+package calculators
+
+import (
+	"finclamp-api/explain"
+	"finclamp-api/models"
+	"finclamp-api/registry"
+	"finclamp-api/units"
+	"finclamp-api/utils"
+	"fmt"
+	"sort"
+)
+
+const defaultCommuteDays = 5
+
+// Commute compares the yearly cost of commuting modes over the same round trip
+func Commute(req models.CommuteRequest, t *explain.Trace) (models.CommuteResponse, error) {
+	distanceUnit := req.DistanceUnit
+	if distanceUnit == "" {
+		distanceUnit = units.DefaultDistance
+	}
+	daysPerWeek := req.DaysPerWeek
+	if daysPerWeek == 0 {
+		daysPerWeek = defaultCommuteDays
+	}
+
+	kmPerUnit, err := units.ToKilometres(1, distanceUnit)
+	if err != nil {
+		return models.CommuteResponse{}, unitError(err)
+	}
+	roundTripKm := t.Step("Round trip in km", "oneWayDistance × 2 × kmPerUnit",
+		map[string]float64{"oneWayDistance": req.OneWayDistance, "kmPerUnit": kmPerUnit}, req.OneWayDistance*2*kmPerUnit)
+	days := t.Step("Commuting days per year", "daysPerWeek × 52",
+		map[string]float64{"daysPerWeek": daysPerWeek}, daysPerWeek*units.Periods["week"])
+
+	names := make(map[string]bool, len(req.Modes))
+	modes := make([]models.CommuteModeCost, 0, len(req.Modes))
+	yearlyCosts := make(map[string]float64, len(req.Modes))
+	for _, mode := range req.Modes {
+		if names[mode.Name] {
+			return models.CommuteResponse{}, &registry.InputError{Message: "Duplicate commute mode", Detail: fmt.Sprintf("mode names must be unique, %q appears twice", mode.Name)}
+		}
+		names[mode.Name] = true
+
+		perKm := mode.FarePerDistance / kmPerUnit
+		if mode.Vehicle != nil {
+			fuelPerKm, err := fuelCostPerKm(*mode.Vehicle, t)
+			if err != nil {
+				return models.CommuteResponse{}, err
+			}
+			perKm += fuelPerKm
+		}
+		fixed, err := yearlyRecurring(mode.FixedCosts, daysPerWeek)
+		if err != nil {
+			return models.CommuteResponse{}, err
+		}
+
+		yearly := t.Step("Yearly cost of "+mode.Name, "(roundTripKm × costPerKm + dailyCosts) × days + fixedCosts",
+			map[string]float64{"roundTripKm": roundTripKm, "costPerKm": perKm, "dailyCosts": mode.DailyCosts, "days": days, "fixedCosts": fixed},
+			(roundTripKm*perKm+mode.DailyCosts)*days+fixed)
+		yearlyCosts[mode.Name] = yearly
+
+		modes = append(modes, models.CommuteModeCost{
+			Name:            mode.Name,
+			DailyCost:       utils.RoundToTwoDecimals(yearly / days),
+			CostPerDistance: utils.RoundToTwoDecimals(yearly / days / roundTripKm * kmPerUnit),
+			Projection:      projectCost(mode.Name, yearly, req.ProjectionOptions, t),
+		})
+	}
+
+	sort.SliceStable(modes, func(i, j int) bool {
+		return yearlyCosts[modes[i].Name] < yearlyCosts[modes[j].Name]
+	})
+	cheapest := yearlyCosts[modes[0].Name]
+	for i := range modes {
+		modes[i].ExtraPerYear = utils.RoundToTwoDecimals(yearlyCosts[modes[i].Name] - cheapest)
+	}
+
+	return models.CommuteResponse{
+		DistanceUnit:   distanceUnit,
+		YearlyDistance: utils.RoundToTwoDecimals(roundTripKm * days / kmPerUnit),
+		CommutingDays:  days,
+		Modes:          modes,
+		Cheapest:       modes[0].Name,
+		Explanation:    t.Steps(),
+	}, nil
+}
@@ -0,0 +1,44 @@
+package calculators
+
+import (
+	"finclamp-api/currency"
+	"finclamp-api/explain"
+	"finclamp-api/models"
+	"finclamp-api/registry"
+	"strings"
+)
+
+// Convert converts an amount between currencies using the offline rate table.
+// The as-of date reported is the older of the two currencies' rate dates.
+func Convert(req models.CurrencyConversionRequest, t *explain.Trace) (models.CurrencyConversionResponse, error) {
+	from, ok := currency.Lookup(req.From)
+	if !ok {
+		return models.CurrencyConversionResponse{}, &registry.InputError{Message: "Invalid currency", Detail: "Available currencies: " + strings.Join(currency.Codes(), ", ")}
+	}
+	to, ok := currency.Lookup(req.To)
+	if !ok {
+		return models.CurrencyConversionResponse{}, &registry.InputError{Message: "Invalid currency", Detail: "Available currencies: " + strings.Join(currency.Codes(), ", ")}
+	}
+
+	rate := t.Step("Cross rate through USD", "rate = toPerUSD / fromPerUSD",
+		map[string]float64{"fromPerUSD": from.PerUSD, "toPerUSD": to.PerUSD}, currency.Rate(from, to))
+	converted := t.Step("Convert the amount", "converted = amount × rate",
+		map[string]float64{"amount": req.Amount, "rate": rate}, req.Amount*rate)
+
+	asOf := from.AsOf
+	if to.AsOf.Before(asOf) {
+		asOf = to.AsOf
+	}
+
+	return models.CurrencyConversionResponse{
+		Amount:             currency.Round(req.Amount, from),
+		From:               from.Code,
+		To:                 to.Code,
+		Rate:               rate,
+		Converted:          currency.Round(converted, to),
+		AsOf:               asOf.Format("2006-01-02"),
+		AmountFormatted:    currency.Format(req.Amount, from, req.Locale),
+		ConvertedFormatted: currency.Format(converted, to, req.Locale),
+		Explanation:        t.Steps(),
+	}, nil
+}
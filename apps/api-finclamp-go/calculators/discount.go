@@ -0,0 +1,52 @@
+package calculators
+
+import (
+	"finclamp-api/explain"
+	"finclamp-api/models"
+	"finclamp-api/utils"
+)
+
+// Discount applies a discount, then an additional discount on the reduced
+// price, then tax on what remains
+func Discount(req models.DiscountRequest, t *explain.Trace) (models.DiscountResponse, error) {
+	discount := t.Step("Primary discount", "originalPrice × discountPercent / 100",
+		map[string]float64{"originalPrice": req.OriginalPrice, "discountPercent": req.DiscountPercent},
+		req.OriginalPrice*req.DiscountPercent/100)
+	afterFirst := req.OriginalPrice - discount
+
+	additional := t.Step("Additional discount on the reduced price", "(originalPrice − discount) × additionalDiscount / 100",
+		map[string]float64{"reducedPrice": afterFirst, "additionalDiscount": req.AdditionalDiscount},
+		afterFirst*req.AdditionalDiscount/100)
+	afterDiscounts := afterFirst - additional
+
+	tax := t.Step("Tax on the discounted price", "priceAfterDiscount × taxPercent / 100",
+		map[string]float64{"priceAfterDiscount": afterDiscounts, "taxPercent": req.TaxPercent},
+		afterDiscounts*req.TaxPercent/100)
+	finalPrice := afterDiscounts + tax
+	savings := req.OriginalPrice - afterDiscounts
+
+	response := models.DiscountResponse{
+		DiscountAmount:           utils.RoundToTwoDecimals(discount),
+		AdditionalDiscountAmount: utils.RoundToTwoDecimals(additional),
+		PriceAfterDiscount:       utils.RoundToTwoDecimals(afterDiscounts),
+		TaxAmount:                utils.RoundToTwoDecimals(tax),
+		FinalPrice:               utils.RoundToTwoDecimals(finalPrice),
+		TotalSavings:             utils.RoundToTwoDecimals(savings),
+		EffectiveDiscount: utils.RoundToTwoDecimals(t.Step("Effective discount", "savings / originalPrice × 100",
+			map[string]float64{"savings": savings, "originalPrice": req.OriginalPrice}, savings/req.OriginalPrice*100)),
+	}
+
+	if req.Frequency != nil {
+		perYear, err := occurrencesPerYear(*req.Frequency)
+		if err != nil {
+			return models.DiscountResponse{}, err
+		}
+		yearly := t.Step("Yearly spend on this purchase", "finalPrice × occurrencesPerYear",
+			map[string]float64{"finalPrice": finalPrice, "occurrencesPerYear": perYear}, finalPrice*perYear)
+		projection := projectCost("this purchase", yearly, req.ProjectionOptions, t)
+		response.Projection = &projection
+	}
+
+	response.Explanation = t.Steps()
+	return response, nil
+}
@@ -0,0 +1,84 @@
+package calculators
+
+import (
+	"finclamp-api/explain"
+	"finclamp-api/models"
+	"finclamp-api/utils"
+	"fmt"
+	"math"
+)
+
+// stabilityMonths is the base number of months of expenses to hold, by how
+// secure the household's income is
+var stabilityMonths = map[string]int{
+	"stable":   3,
+	"moderate": 6,
+	"unstable": 9,
+}
+
+// Adjustments that raise the emergency fund target
+const (
+	singleIncomeMonths  = 2
+	monthsPerDependant  = 1
+	maxDependantMonths  = 3
+	defaultJobStability = "moderate"
+)
+
+// EmergencyFund sizes an emergency fund as a number of months of essential
+// outgoings, raised for single-income households and dependants, and reports
+// how far current savings are from it
+func EmergencyFund(req models.EmergencyFundRequest, t *explain.Trace) (models.EmergencyFundResponse, error) {
+	stability := req.JobStability
+	if stability == "" {
+		stability = defaultJobStability
+	}
+	earners := req.Earners
+	if earners == 0 {
+		earners = 1
+	}
+
+	monthlyNeed := t.Step("Essential outgoings per month", "monthlyExpenses + monthlyEmis",
+		map[string]float64{"monthlyExpenses": req.MonthlyExpenses, "monthlyEmis": req.MonthlyEMIs}, req.MonthlyExpenses+req.MonthlyEMIs)
+
+	baseMonths := stabilityMonths[stability]
+	months := baseMonths
+	adjustments := []models.FundAdjustment{}
+	if earners == 1 {
+		adjustments = append(adjustments, models.FundAdjustment{Reason: "single income household", Months: singleIncomeMonths})
+		months += singleIncomeMonths
+	}
+	if req.Dependants > 0 {
+		extra := min(req.Dependants*monthsPerDependant, maxDependantMonths)
+		adjustments = append(adjustments, models.FundAdjustment{Reason: fmt.Sprintf("dependants (%d)", req.Dependants), Months: extra})
+		months += extra
+	}
+	t.Step("Months of outgoings to hold ("+stability+" income)", "baseMonths + adjustments",
+		map[string]float64{"baseMonths": float64(baseMonths)}, float64(months))
+
+	target := t.Step("Emergency fund target", "monthlyNeed × months + medicalBuffer",
+		map[string]float64{"monthlyNeed": monthlyNeed, "months": float64(months), "medicalBuffer": req.MedicalBuffer},
+		monthlyNeed*float64(months)+req.MedicalBuffer)
+	gap := t.Step("Gap to the target", "max(target − currentSavings, 0)",
+		map[string]float64{"target": target, "currentSavings": req.CurrentSavings}, math.Max(target-req.CurrentSavings, 0))
+
+	response := models.EmergencyFundResponse{
+		MonthlyNeed:    utils.RoundToTwoDecimals(monthlyNeed),
+		BaseMonths:     baseMonths,
+		Adjustments:    adjustments,
+		TargetMonths:   months,
+		TargetAmount:   utils.RoundToTwoDecimals(target),
+		CurrentSavings: req.CurrentSavings,
+		Gap:            utils.RoundToTwoDecimals(gap),
+	}
+	// Without a monthly saving the gap is never closed, so monthsToTarget stays null
+	if gap == 0 {
+		response.MonthsToTarget = new(int)
+	} else if req.MonthlySaving > 0 {
+		monthsToTarget := int(math.Ceil(t.Step("Months to close the gap", "gap / monthlySaving",
+			map[string]float64{"gap": gap, "monthlySaving": req.MonthlySaving}, gap/req.MonthlySaving)))
+		response.MonthsToTarget = &monthsToTarget
+	}
+
+	response.Explanation = t.Steps()
+	return response, nil
+}
@@ -0,0 +1,100 @@
+package calculators
+
+import (
+	"finclamp-api/explain"
+	"finclamp-api/models"
+	"finclamp-api/registry"
+	"finclamp-api/units"
+	"finclamp-api/utils"
+	"math"
+)
+
+// Defaults for the cost projection shared by the everyday calculators
+const (
+	defaultProjectionYears = 10
+	defaultExpectedReturn  = 12
+)
+
+// projectCost annualizes a yearly cost and projects it over the requested
+// horizon. The opportunity cost is the growth forgone by spending the money
+// each month rather than investing it at the expected return.
+func projectCost(label string, yearly float64, opts models.ProjectionOptions, t *explain.Trace) models.CostProjection {
+	years := opts.ProjectionYears
+	if years == 0 {
+		years = defaultProjectionYears
+	}
+	expectedReturn := float64(defaultExpectedReturn)
+	if opts.ExpectedReturn != nil {
+		expectedReturn = *opts.ExpectedReturn
+	}
+
+	monthly := yearly / 12
+	months := math.Round(years * 12)
+	monthlyRate := expectedReturn / 100 / 12
+
+	lifetime := t.Step("Total "+label+" over the projection", "lifetime = yearly × years",
+		map[string]float64{"yearly": yearly, "years": years}, yearly*years)
+
+	annuityFactor := months
+	if monthlyRate > 0 {
+		annuityFactor = (math.Pow(1+monthlyRate, months) - 1) / monthlyRate
+	}
+	invested := t.Step("Value of "+label+" if invested monthly instead", "PMT × ((1 + r)^n − 1) / r",
+		map[string]float64{"PMT": monthly, "r": monthlyRate, "n": months}, monthly*annuityFactor)
+	opportunityCost := t.Step("Opportunity cost of "+label, "investedValue − lifetime",
+		map[string]float64{"investedValue": invested, "lifetime": lifetime}, invested-lifetime)
+
+	return models.CostProjection{
+		Daily:           utils.RoundToTwoDecimals(yearly / units.Periods["day"]),
+		Weekly:          utils.RoundToTwoDecimals(yearly / units.Periods["week"]),
+		Monthly:         utils.RoundToTwoDecimals(monthly),
+		Yearly:          utils.RoundToTwoDecimals(yearly),
+		Years:           years,
+		Lifetime:        utils.RoundToTwoDecimals(lifetime),
+		ExpectedReturn:  expectedReturn,
+		InvestedValue:   utils.RoundToTwoDecimals(invested),
+		OpportunityCost: utils.RoundToTwoDecimals(opportunityCost),
+	}
+}
+
+// occurrencesPerYear converts a frequency to occurrences per year
+func occurrencesPerYear(f models.Frequency) (float64, error) {
+	perYear, err := units.PerYear(f.Times, f.Per, f.DaysPerWeek)
+	if err != nil {
+		return 0, unitError(err)
+	}
+	return perYear, nil
+}
+
+// yearlyRecurring sums recurring costs per year. Per-day costs apply on
+// daysPerWeek days (0 means every day).
+func yearlyRecurring(costs []models.RecurringCost, daysPerWeek float64) (float64, error) {
+	total := 0.0
+	for _, cost := range costs {
+		perYear, err := units.PerYear(cost.Amount, cost.Per, daysPerWeek)
+		if err != nil {
+			return 0, unitError(err)
+		}
+		total += perYear
+	}
+	return total, nil
+}
+
+// fuelCostPerKm returns the fuel cost of driving one kilometre
+func fuelCostPerKm(v models.VehicleFuel, t *explain.Trace) (float64, error) {
+	kmPerLitre, err := units.KilometresPerLitre(v.Efficiency, v.EfficiencyUnit)
+	if err != nil {
+		return 0, unitError(err)
+	}
+	litresPerUnit, err := units.ToLitres(1, v.VolumeUnit)
+	if err != nil {
+		return 0, unitError(err)
+	}
+	return t.Step("Fuel cost per km", "fuelPrice / litresPerUnit / kmPerLitre",
+		map[string]float64{"fuelPrice": v.FuelPrice, "litresPerUnit": litresPerUnit, "kmPerLitre": kmPerLitre},
+		v.FuelPrice/litresPerUnit/kmPerLitre), nil
+}
+
+func unitError(err error) error {
+	return &registry.InputError{Message: "Invalid unit", Detail: err.Error()}
+}
@@ -0,0 +1,58 @@
+package calculators
+
+import (
+	"finclamp-api/explain"
+	"finclamp-api/models"
+	"finclamp-api/units"
+	"finclamp-api/utils"
+)
+
+// FuelCost computes the fuel cost of driving a distance per period
+func FuelCost(req models.FuelCostRequest, t *explain.Trace) (models.FuelCostResponse, error) {
+	distanceUnit := req.DistanceUnit
+	if distanceUnit == "" {
+		distanceUnit = units.DefaultDistance
+	}
+	volumeUnit := req.VolumeUnit
+	if volumeUnit == "" {
+		volumeUnit = units.DefaultVolume
+	}
+
+	costPerKm, err := fuelCostPerKm(req.VehicleFuel, t)
+	if err != nil {
+		return models.FuelCostResponse{}, err
+	}
+	kmPerLitre, err := units.KilometresPerLitre(req.Efficiency, req.EfficiencyUnit)
+	if err != nil {
+		return models.FuelCostResponse{}, unitError(err)
+	}
+	kmPerUnit, err := units.ToKilometres(1, distanceUnit)
+	if err != nil {
+		return models.FuelCostResponse{}, unitError(err)
+	}
+	litresPerUnit, err := units.ToLitres(1, volumeUnit)
+	if err != nil {
+		return models.FuelCostResponse{}, unitError(err)
+	}
+
+	perYear, err := units.PerYear(req.Distance, req.Per, req.DaysPerWeek)
+	if err != nil {
+		return models.FuelCostResponse{}, unitError(err)
+	}
+	yearlyKm := t.Step("Distance driven per year in km", "distance × kmPerUnit × periodsPerYear",
+		map[string]float64{"distance": req.Distance, "kmPerUnit": kmPerUnit, "periodsPerYear": perYear / req.Distance},
+		perYear*kmPerUnit)
+	yearly := t.Step("Yearly fuel cost", "yearlyKm × costPerKm",
+		map[string]float64{"yearlyKm": yearlyKm, "costPerKm": costPerKm}, yearlyKm*costPerKm)
+
+	return models.FuelCostResponse{
+		CostPerDistance: utils.RoundToTwoDecimals(costPerKm * kmPerUnit),
+		DistanceUnit:    distanceUnit,
+		KmPerLitre:      utils.RoundToTwoDecimals(kmPerLitre),
+		YearlyDistance:  utils.RoundToTwoDecimals(yearlyKm / kmPerUnit),
+		YearlyFuel:      utils.RoundToTwoDecimals(yearlyKm / kmPerLitre / litresPerUnit),
+		VolumeUnit:      volumeUnit,
+		Projection:      projectCost("fuel", yearly, req.ProjectionOptions, t),
+		Explanation:     t.Steps(),
+	}, nil
+}
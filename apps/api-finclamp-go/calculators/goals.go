@@ -0,0 +1,245 @@
+package calculators
+
+import (
+	"finclamp-api/explain"
+	"finclamp-api/models"
+	"finclamp-api/registry"
+	"finclamp-api/utils"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+const monthLayout = "2006-01"
+
+// maxGoalMonths is the furthest a goal's target month may be from the start,
+// which bounds the months planned for each goal
+const maxGoalMonths = 600
+
+// goalState tracks a goal while the plan is being built
+type goalState struct {
+	goal        models.FinancialGoal
+	months      int
+	monthlyRate float64
+	allocation  []float64
+}
+
+// GoalPlan splits a monthly savings capacity across several goals. Goals are
+// funded in priority order (then earliest target first); each goal takes a
+// level monthly amount from the capacity still free before its deadline, and
+// may take more in months freed up once an earlier goal is complete.
+func GoalPlan(req models.GoalPlanRequest, t *explain.Trace) (models.GoalPlanResponse, error) {
+	start := time.Date(time.Now().Year(), time.Now().Month(), 1, 0, 0, 0, 0, time.UTC)
+	if req.StartMonth != "" {
+		parsed, err := time.Parse(monthLayout, req.StartMonth)
+		if err != nil {
+			return models.GoalPlanResponse{}, &registry.InputError{Message: "Invalid start month", Detail: "startMonth must use the YYYY-MM format"}
+		}
+		start = parsed
+	}
+
+	goals := make([]*goalState, 0, len(req.Goals))
+	names := make(map[string]bool, len(req.Goals))
+	horizon := 0
+	for _, goal := range req.Goals {
+		if names[goal.Name] {
+			return models.GoalPlanResponse{}, &registry.InputError{Message: "Duplicate goal", Detail: fmt.Sprintf("goal names must be unique, %q appears twice", goal.Name)}
+		}
+		names[goal.Name] = true
+
+		target, err := time.Parse(monthLayout, goal.TargetMonth)
+		if err != nil {
+			return models.GoalPlanResponse{}, &registry.InputError{Message: "Invalid target month", Detail: fmt.Sprintf("%s: targetMonth must use the YYYY-MM format", goal.Name)}
+		}
+		months := monthsBetween(start, target)
+		if months <= 0 {
+			return models.GoalPlanResponse{}, &registry.InputError{Message: "Invalid target month", Detail: fmt.Sprintf("%s: targetMonth must be after %s", goal.Name, start.Format(monthLayout))}
+		}
+		if months > maxGoalMonths {
+			return models.GoalPlanResponse{}, &registry.InputError{Message: "Invalid target month", Detail: fmt.Sprintf("%s: targetMonth must be within %d months of %s", goal.Name, maxGoalMonths, start.Format(monthLayout))}
+		}
+
+		goals = append(goals, &goalState{goal: goal, months: months, monthlyRate: goal.ExpectedReturn / 100 / 12})
+		if months > horizon {
+			horizon = months
+		}
+	}
+
+	sort.SliceStable(goals, func(i, j int) bool {
+		if goals[i].goal.Priority != goals[j].goal.Priority {
+			return goals[i].goal.Priority < goals[j].goal.Priority
+		}
+		return goals[i].months < goals[j].months
+	})
+
+	free := make([]float64, horizon)
+	for m := range free {
+		free[m] = req.MonthlyCapacity
+	}
+
+	response := models.GoalPlanResponse{
+		StartMonth:      start.Format(monthLayout),
+		MonthlyCapacity: req.MonthlyCapacity,
+		Goals:           make([]models.GoalAllocation, 0, len(goals)),
+	}
+
+	totalRequired, totalShortfall := 0.0, 0.0
+	for _, g := range goals {
+		growth := math.Pow(1+g.monthlyRate, float64(g.months))
+		savingsValue := t.Step("Grow current savings for "+g.goal.Name+" to the target month", "S × (1 + r)^n",
+			map[string]float64{"S": g.goal.CurrentSavings, "r": g.monthlyRate, "n": float64(g.months)},
+			g.goal.CurrentSavings*growth)
+		needed := math.Max(g.goal.TargetAmount-savingsValue, 0)
+
+		annuityFactor := float64(g.months)
+		if g.monthlyRate > 0 {
+			annuityFactor = (growth - 1) / g.monthlyRate
+		}
+		required := t.Step("Level monthly amount "+g.goal.Name+" needs on its own", "(target − S × (1 + r)^n) / (((1 + r)^n − 1) / r)",
+			map[string]float64{"target": g.goal.TargetAmount, "savingsValue": savingsValue, "r": g.monthlyRate, "n": float64(g.months)},
+			needed/annuityFactor)
+
+		level := fillLevel(free[:g.months], g.monthlyRate, needed)
+		g.allocation = make([]float64, g.months)
+		for m := range g.allocation {
+			g.allocation[m] = math.Min(free[m], level)
+			free[m] -= g.allocation[m]
+		}
+
+		projected := t.Step("Project "+g.goal.Name+" with the capacity it was allocated", "S × (1 + r)^n + Σ xₘ × (1 + r)^(n − m − 1)",
+			map[string]float64{"level": level, "savingsValue": savingsValue},
+			savingsValue+allocationValue(g.allocation, g.monthlyRate))
+		shortfall := math.Max(g.goal.TargetAmount-projected, 0)
+		// Treat sub-paisa residue from the level search as fully funded
+		if shortfall < 0.01 {
+			shortfall = 0
+		}
+
+		totalRequired += required
+		totalShortfall += shortfall
+		if shortfall == 0 {
+			response.AchievableGoals++
+		}
+
+		response.Goals = append(response.Goals, models.GoalAllocation{
+			Name:            g.goal.Name,
+			Priority:        g.goal.Priority,
+			TargetAmount:    g.goal.TargetAmount,
+			TargetMonth:     g.goal.TargetMonth,
+			Months:          g.months,
+			RequiredMonthly: utils.RoundToTwoDecimals(required),
+			Allocation:      allocationPhases(start, g.allocation),
+			ProjectedAmount: utils.RoundToTwoDecimals(projected),
+			Shortfall:       utils.RoundToTwoDecimals(shortfall),
+			Achievable:      shortfall == 0,
+		})
+	}
+
+	response.TotalRequiredMonthly = utils.RoundToTwoDecimals(totalRequired)
+	response.TotalShortfall = utils.RoundToTwoDecimals(totalShortfall)
+	response.Plan = planPhases(start, goals, free)
+	response.Explanation = t.Steps()
+
+	return response, nil
+}
+
+// fillLevel finds the smallest level L such that contributing min(free[m], L)
+// each month grows to needed. If even all free capacity falls short, it returns
+// the largest free amount so the goal takes everything available.
+func fillLevel(free []float64, monthlyRate, needed float64) float64 {
+	ceiling := 0.0
+	for _, amount := range free {
+		ceiling = math.Max(ceiling, amount)
+	}
+	if needed <= 0 {
+		return 0
+	}
+
+	// Every pass of the search fills the same contributions buffer
+	contributions := make([]float64, len(free))
+	valueAt := func(level float64) float64 {
+		for m, amount := range free {
+			contributions[m] = math.Min(amount, level)
+		}
+		return allocationValue(contributions, monthlyRate)
+	}
+
+	if valueAt(ceiling) <= needed {
+		return ceiling
+	}
+
+	low, high := 0.0, ceiling
+	for i := 0; i < 100; i++ {
+		mid := (low + high) / 2
+		if valueAt(mid) < needed {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return high
+}
+
+// allocationValue returns the value at the deadline of end-of-month contributions
+func allocationValue(contributions []float64, monthlyRate float64) float64 {
+	value := 0.0
+	for _, amount := range contributions {
+		value = value*(1+monthlyRate) + amount
+	}
+	return value
+}
+
+// allocationPhases compresses a per-month contribution list into runs of equal amounts
+func allocationPhases(start time.Time, contributions []float64) []models.AllocationPhase {
+	phases := []models.AllocationPhase{}
+	for m, amount := range contributions {
+		rounded := utils.RoundToTwoDecimals(amount)
+		month := start.AddDate(0, m, 0).Format(monthLayout)
+		if n := len(phases); n > 0 && phases[n-1].MonthlyAmount == rounded {
+			phases[n-1].ToMonth = month
+			continue
+		}
+		phases = append(phases, models.AllocationPhase{FromMonth: month, ToMonth: month, MonthlyAmount: rounded})
+	}
+	return phases
+}
+
+// planPhases groups months in which every goal receives the same amount
+func planPhases(start time.Time, goals []*goalState, free []float64) []models.PlanPhase {
+	phases := []models.PlanPhase{}
+	for m := range free {
+		allocations := make(map[string]float64)
+		for _, g := range goals {
+			if m < len(g.allocation) && g.allocation[m] > 0 {
+				allocations[g.goal.Name] = utils.RoundToTwoDecimals(g.allocation[m])
+			}
+		}
+		unallocated := utils.RoundToTwoDecimals(free[m])
+		month := start.AddDate(0, m, 0).Format(monthLayout)
+
+		if n := len(phases); n > 0 && phases[n-1].Unallocated == unallocated && sameAllocations(phases[n-1].Allocations, allocations) {
+			phases[n-1].ToMonth = month
+			continue
+		}
+		phases = append(phases, models.PlanPhase{FromMonth: month, ToMonth: month, Allocations: allocations, Unallocated: unallocated})
+	}
+	return phases
+}
+
+func sameAllocations(a, b map[string]float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, amount := range a {
+		if b[name] != amount {
+			return false
+		}
+	}
+	return true
+}
+
+// monthsBetween returns the number of whole months from start to end
+func monthsBetween(start, end time.Time) int {
+	return (end.Year()-start.Year())*12 + int(end.Month()) - int(start.Month())
+}
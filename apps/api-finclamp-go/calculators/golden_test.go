@@ -0,0 +1,252 @@
+package calculators
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"finclamp-api/currency"
+	"finclamp-api/indexation"
+	"finclamp-api/marketdata"
+	"finclamp-api/registry"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Golden files live in testdata/golden, one per calculator, each holding cases
+// of a request and the response (or input error) it must produce. Cases cite
+// their source where the numbers come from a published table or example.
+// Run `go test ./calculators -run Golden -update` to rewrite the expected
+// results after an intended change, then review the diff.
+var update = flag.Bool("update", false, "rewrite golden files with the current results")
+
+const goldenDir = "testdata/golden"
+
+// goldenFile is the on-disk layout of a golden file
+type goldenFile struct {
+	Calculator string       `json:"calculator"`
+	Cases      []goldenCase `json:"cases"`
+}
+
+type goldenCase struct {
+	Name     string          `json:"name"`
+	Source   string          `json:"source,omitempty"`
+	Request  json.RawMessage `json:"request"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	log.SetOutput(io.Discard)
+	// Calculators that read offline datasets use the copies embedded in the binary
+	for name, load := range map[string]func(string) error{
+		"market data":          marketdata.Load,
+		"exchange rates":       currency.Load,
+		"cost inflation index": indexation.Load,
+	} {
+		if err := load(""); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to load %s: %v\n", name, err)
+			os.Exit(1)
+		}
+	}
+	os.Exit(m.Run())
+}
+
+func TestGolden(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join(goldenDir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range paths {
+		path := path
+		t.Run(strings.TrimSuffix(filepath.Base(path), ".json"), func(t *testing.T) {
+			file := readGolden(t, path)
+			calc, ok := registry.Lookup(file.Calculator)
+			if !ok {
+				t.Fatalf("%s: no calculator named %q", path, file.Calculator)
+			}
+
+			for i := range file.Cases {
+				golden := &file.Cases[i]
+				t.Run(golden.Name, func(t *testing.T) {
+					response, errMessage := runGolden(t, calc, golden.Request)
+					if *update {
+						golden.Response, golden.Error = response, errMessage
+						return
+					}
+					if golden.Source != "" {
+						t.Logf("source: %s", golden.Source)
+					}
+					if errMessage != golden.Error {
+						t.Fatalf("error: got %q, want %q", errMessage, golden.Error)
+					}
+					if diffs := diffJSON(t, golden.Response, response); len(diffs) > 0 {
+						t.Errorf("response differs from %s (-update rewrites it):\n\t%s", path, strings.Join(diffs, "\n\t"))
+					}
+				})
+			}
+
+			if *update {
+				writeGolden(t, path, file)
+			}
+		})
+	}
+}
+
+// TestGoldenCoversEveryCalculator fails when a registered calculator has no
+// golden file, so new calculators cannot ship without known-good results
+func TestGoldenCoversEveryCalculator(t *testing.T) {
+	for _, calc := range registry.All() {
+		name := calc.Meta().Name
+		path := filepath.Join(goldenDir, name+".json")
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("calculator %q has no golden file %s", name, path)
+			continue
+		}
+		if file := readGolden(t, path); file.Calculator != name || len(file.Cases) == 0 {
+			t.Errorf("%s: want cases for calculator %q", path, name)
+		}
+	}
+}
+
+// runGolden runs a calculator without an explanation and returns its response
+// as JSON, or the message of the input error it rejected the request with
+func runGolden(t *testing.T, calc registry.Calculator, request json.RawMessage) (json.RawMessage, string) {
+	t.Helper()
+	result, err := calc.Run(request, false)
+	var inputErr *registry.InputError
+	if errors.As(err, &inputErr) {
+		return nil, inputErr.Message
+	}
+	if err != nil {
+		t.Fatalf("calculation failed: %v", err)
+	}
+	response, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("failed to encode response: %v", err)
+	}
+	return response, ""
+}
+
+func readGolden(t *testing.T, path string) goldenFile {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var file goldenFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+	return file
+}
+
+func writeGolden(t *testing.T, path string, file goldenFile) {
+	t.Helper()
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(file); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// diffJSON compares two JSON documents leaf by leaf and describes each
+// difference as "path: got X, want Y". Numbers within floatTolerance are equal
+// so that floating-point noise does not fail a case.
+func diffJSON(t *testing.T, want, got json.RawMessage) []string {
+	t.Helper()
+	wantLeaves, gotLeaves := map[string]interface{}{}, map[string]interface{}{}
+	for _, doc := range []struct {
+		raw    json.RawMessage
+		leaves map[string]interface{}
+	}{{want, wantLeaves}, {got, gotLeaves}} {
+		if len(doc.raw) == 0 {
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal(doc.raw, &value); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		leaves(value, "", doc.leaves)
+	}
+
+	paths := make([]string, 0, len(wantLeaves))
+	for path := range wantLeaves {
+		paths = append(paths, path)
+	}
+	for path := range gotLeaves {
+		if _, ok := wantLeaves[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	diffs := []string{}
+	for _, path := range paths {
+		wantValue, inWant := wantLeaves[path]
+		gotValue, inGot := gotLeaves[path]
+		switch {
+		case !inGot:
+			diffs = append(diffs, fmt.Sprintf("%s: missing, want %v", path, wantValue))
+		case !inWant:
+			diffs = append(diffs, fmt.Sprintf("%s: got %v, not in golden file", path, gotValue))
+		case !sameLeaf(wantValue, gotValue):
+			diffs = append(diffs, fmt.Sprintf("%s: got %v, want %v", path, gotValue, wantValue))
+		}
+	}
+	return diffs
+}
+
+const floatTolerance = 1e-6
+
+func sameLeaf(want, got interface{}) bool {
+	wantNumber, wantOK := want.(float64)
+	gotNumber, gotOK := got.(float64)
+	if wantOK && gotOK {
+		return math.Abs(wantNumber-gotNumber) <= floatTolerance
+	}
+	return reflect.DeepEqual(want, got)
+}
+
+// leaves records every leaf of decoded JSON under its path, e.g.
+// "options[0].totalTax". Empty objects and arrays are leaves too.
+func leaves(value interface{}, path string, into map[string]interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			into[path] = v
+		}
+		for key, child := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			leaves(child, childPath, into)
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			into[path] = v
+		}
+		for i, child := range v {
+			leaves(child, path+"["+strconv.Itoa(i)+"]", into)
+		}
+	default:
+		into[path] = v
+	}
+}
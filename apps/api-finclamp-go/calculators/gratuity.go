@@ -0,0 +1,81 @@
+package calculators
+
+import (
+	"finclamp-api/explain"
+	"finclamp-api/models"
+	"finclamp-api/utils"
+	"math"
+)
+
+// Gratuity rules under the Payment of Gratuity Act, 1972 and section 10(10)
+const (
+	gratuityDaysPerYear   = 15
+	coveredWorkingDays    = 26
+	notCoveredWorkingDays = 30
+	gratuityMinimumYears  = 5
+	gratuityExemptLimit   = 2000000
+	gratuityRoundUpAfter  = 6 // months of a final part year that count as a full year
+	defaultGratuityReason = "resignation"
+)
+
+// Gratuity computes the gratuity due on leaving an employer. Employees covered
+// by the Act get 15 days' salary per year of service on a 26-day month, with a
+// final part year of more than six months counted in full; others get 15 days'
+// average salary per completed year on a 30-day month. Up to 20 lakh is exempt
+// from tax.
+func Gratuity(req models.GratuityRequest, t *explain.Trace) (models.GratuityResponse, error) {
+	covered := true
+	if req.CoveredByAct != nil {
+		covered = *req.CoveredByAct
+	}
+	reason := req.Reason
+	if reason == "" {
+		reason = defaultGratuityReason
+	}
+
+	response := models.GratuityResponse{
+		Eligible:     true,
+		CoveredByAct: covered,
+		ExemptLimit:  gratuityExemptLimit,
+	}
+	if req.ServiceYears < gratuityMinimumYears {
+		if reason != "death" && reason != "disability" {
+			response.Eligible = false
+			response.Note = "gratuity is payable after five years of continuous service"
+			response.Explanation = t.Steps()
+			return response, nil
+		}
+		response.Note = "the five-year minimum does not apply on " + reason
+	}
+
+	salary := req.MonthlySalary
+	years := req.ServiceYears
+	divisor := coveredWorkingDays
+	if covered {
+		if req.ServiceMonths > gratuityRoundUpAfter {
+			years++
+		}
+	} else {
+		divisor = notCoveredWorkingDays
+		if req.AverageMonthlySalary > 0 {
+			salary = req.AverageMonthlySalary
+		}
+	}
+	t.Step("Qualifying years of service", "serviceYears, +1 if covered and serviceMonths > 6",
+		map[string]float64{"serviceYears": float64(req.ServiceYears), "serviceMonths": float64(req.ServiceMonths)}, float64(years))
+
+	gratuity := t.Step("Gratuity", "salary × 15 × years / divisor",
+		map[string]float64{"salary": salary, "years": float64(years), "divisor": float64(divisor)},
+		salary*gratuityDaysPerYear*float64(years)/float64(divisor))
+	exempt := t.Step("Tax-exempt gratuity", "min(gratuity, exemptLimit)",
+		map[string]float64{"gratuity": gratuity, "exemptLimit": gratuityExemptLimit}, math.Min(gratuity, gratuityExemptLimit))
+
+	response.Salary = salary
+	response.QualifyingYears = years
+	response.Divisor = divisor
+	response.Gratuity = utils.RoundToTwoDecimals(gratuity)
+	response.Exempt = utils.RoundToTwoDecimals(exempt)
+	response.Taxable = utils.RoundToTwoDecimals(gratuity - exempt)
+	response.Explanation = t.Steps()
+	return response, nil
+}
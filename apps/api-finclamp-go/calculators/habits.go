@@ -0,0 +1,32 @@
+package calculators
+
+import (
+	"finclamp-api/explain"
+	"finclamp-api/models"
+)
+
+// HabitCost projects the cost of recurring purchases, each on its own and
+// combined
+func HabitCost(req models.HabitCostRequest, t *explain.Trace) (models.HabitCostResponse, error) {
+	response := models.HabitCostResponse{Habits: make([]models.HabitCost, 0, len(req.Habits))}
+
+	total := 0.0
+	for _, habit := range req.Habits {
+		perYear, err := occurrencesPerYear(habit.Frequency)
+		if err != nil {
+			return models.HabitCostResponse{}, err
+		}
+		yearly := t.Step("Yearly cost of "+habit.Name, "unitCost × timesPerYear",
+			map[string]float64{"unitCost": habit.UnitCost, "timesPerYear": perYear}, habit.UnitCost*perYear)
+		total += yearly
+
+		response.Habits = append(response.Habits, models.HabitCost{
+			Name:       habit.Name,
+			Projection: projectCost(habit.Name, yearly, req.ProjectionOptions, t),
+		})
+	}
+
+	response.Projection = projectCost("all habits", total, req.ProjectionOptions, t)
+	response.Explanation = t.Steps()
+	return response, nil
+}
@@ -0,0 +1,65 @@
+package calculators
+
+import (
+	"finclamp-api/explain"
+	"finclamp-api/models"
+	"finclamp-api/utils"
+	"fmt"
+	"math"
+)
+
+// HRA exemption limits under section 10(13A), as percentages of salary
+const (
+	metroHRAPercent    = 50
+	nonMetroHRAPercent = 40
+	rentExcessPercent  = 10
+)
+
+// HRAExemption computes the exempt part of house rent allowance: the least of
+// the HRA received, rent paid above 10% of salary, and 50% of salary in a metro
+// (40% elsewhere)
+func HRAExemption(req models.HRAExemptionRequest, t *explain.Trace) (models.HRAExemptionResponse, error) {
+	months := req.Months
+	if months == 0 {
+		months = 12
+	}
+	limits, exempt := hraExemption(req.BasicSalary*float64(months), req.HRAReceived*float64(months), req.RentPaid*float64(months), req.Metro, t)
+
+	response := models.HRAExemptionResponse{
+		Limits:      limits,
+		HRAReceived: utils.RoundToTwoDecimals(req.HRAReceived * float64(months)),
+		Exempt:      utils.RoundToTwoDecimals(exempt),
+		Taxable:     utils.RoundToTwoDecimals(req.HRAReceived*float64(months) - exempt),
+		Explanation: t.Steps(),
+	}
+	for _, limit := range limits {
+		if limit.Amount == response.Exempt {
+			response.LimitingRule = limit.Rule
+			break
+		}
+	}
+	return response, nil
+}
+
+// hraExemption returns the three limits and the exemption for amounts over the
+// same period
+func hraExemption(salary, hra, rent float64, metro bool, t *explain.Trace) ([]models.HRALimit, float64) {
+	percent, area := float64(nonMetroHRAPercent), "non-metro"
+	if metro {
+		percent, area = metroHRAPercent, "metro"
+	}
+
+	excessRent := t.Step("Rent paid above 10% of salary", "max(rent − salary × 10%, 0)",
+		map[string]float64{"rent": rent, "salary": salary}, math.Max(rent-salary*rentExcessPercent/100, 0))
+	salaryShare := t.Step("Share of salary for a "+area+" city", "salary × percent",
+		map[string]float64{"salary": salary, "percent": percent / 100}, salary*percent/100)
+	exempt := t.Step("HRA exemption", "min(hraReceived, excessRent, salaryShare)",
+		map[string]float64{"hraReceived": hra, "excessRent": excessRent, "salaryShare": salaryShare},
+		math.Min(hra, math.Min(excessRent, salaryShare)))
+
+	return []models.HRALimit{
+		{Rule: "actual HRA received", Amount: utils.RoundToTwoDecimals(hra)},
+		{Rule: "rent paid above 10% of salary", Amount: utils.RoundToTwoDecimals(excessRent)},
+		{Rule: fmt.Sprintf("%g%% of salary (%s)", percent, area), Amount: utils.RoundToTwoDecimals(salaryShare)},
+	}, exempt
+}
@@ -0,0 +1,127 @@
+package calculators
+
+import (
+	"finclamp-api/explain"
+	"finclamp-api/models"
+	"finclamp-api/registry"
+	"finclamp-api/utils"
+	"fmt"
+	"math"
+)
+
+// Defaults for the life insurance needs analysis
+const (
+	defaultRetirementAge = 60
+	defaultInflation     = 6
+	defaultDiscountRate  = 7
+)
+
+// LifeInsurance estimates the term cover needed with two methods: human life
+// value (the present value of the income the family would lose) and expense
+// replacement (the present value of the family's expenses while they depend on
+// the insured). Both add liabilities and future goals and subtract assets.
+func LifeInsurance(req models.LifeInsuranceRequest, t *explain.Trace) (models.LifeInsuranceResponse, error) {
+	retirementAge := req.RetirementAge
+	if retirementAge == 0 {
+		retirementAge = defaultRetirementAge
+	}
+	if retirementAge <= req.Age {
+		return models.LifeInsuranceResponse{}, &registry.InputError{Message: "Invalid retirement age",
+			Detail: fmt.Sprintf("retirementAge (%d) must be greater than age (%d)", retirementAge, req.Age)}
+	}
+	inflation := float64(defaultInflation)
+	if req.Inflation != nil {
+		inflation = *req.Inflation
+	}
+	discountRate := float64(defaultDiscountRate)
+	if req.DiscountRate != nil {
+		discountRate = *req.DiscountRate
+	}
+
+	workingYears := retirementAge - req.Age
+	supportYears := req.YearsToSupport
+	if supportYears == 0 {
+		for _, dependant := range req.Dependants {
+			supportYears = max(supportYears, dependant.SupportUntilAge-dependant.Age)
+		}
+		if supportYears == 0 {
+			supportYears = workingYears
+		}
+	}
+
+	goals := 0.0
+	for _, goal := range req.Goals {
+		future := goal.CostToday * math.Pow(1+inflation/100, float64(goal.YearsAway))
+		goals += t.Step("Present value of "+goal.Name, "costToday × (1 + inflation)^years / (1 + discountRate)^years",
+			map[string]float64{"costToday": goal.CostToday, "inflation": inflation / 100, "discountRate": discountRate / 100, "years": float64(goal.YearsAway)},
+			future/math.Pow(1+discountRate/100, float64(goal.YearsAway)))
+	}
+
+	// Human life value: the income the family would lose, net of the insured's own spending
+	netIncome := t.Step("Income the family would lose each year", "annualIncome − annualPersonalExpenses",
+		map[string]float64{"annualIncome": req.AnnualIncome, "annualPersonalExpenses": req.AnnualPersonalExpenses},
+		math.Max(req.AnnualIncome-req.AnnualPersonalExpenses, 0))
+	hlv := t.Step("Present value of lost income until retirement", "Σ netIncome × (1 + growth)^k / (1 + discountRate)^k, k = 0…years−1",
+		map[string]float64{"netIncome": netIncome, "growth": req.IncomeGrowth / 100, "discountRate": discountRate / 100, "years": float64(workingYears)},
+		growingAnnuityDue(netIncome, req.IncomeGrowth, discountRate, workingYears))
+
+	// Expense replacement: the family's expenses for as long as they need support
+	expenses := t.Step("Present value of family expenses while supported", "Σ annualFamilyExpenses × (1 + inflation)^k / (1 + discountRate)^k, k = 0…years−1",
+		map[string]float64{"annualFamilyExpenses": req.AnnualFamilyExpenses, "inflation": inflation / 100, "discountRate": discountRate / 100, "years": float64(supportYears)},
+		growingAnnuityDue(req.AnnualFamilyExpenses, inflation, discountRate, supportYears))
+
+	estimates := []models.CoverEstimate{
+		coverEstimate("human-life-value", workingYears, hlv, goals, req, t),
+	}
+	if req.AnnualFamilyExpenses > 0 {
+		estimates = append(estimates, coverEstimate("expense-replacement", supportYears, expenses, goals, req, t))
+	}
+
+	recommended := estimates[0]
+	for _, estimate := range estimates[1:] {
+		if estimate.RecommendedCover > recommended.RecommendedCover {
+			recommended = estimate
+		}
+	}
+
+	return models.LifeInsuranceResponse{
+		Estimates:         estimates,
+		RecommendedMethod: recommended.Method,
+		RecommendedCover:  recommended.RecommendedCover,
+		ExistingCover:     req.ExistingCover,
+		Gap:               recommended.Gap,
+		IncomeMultiple:    utils.RoundToTwoDecimals(recommended.RecommendedCover / req.AnnualIncome),
+		Explanation:       t.Steps(),
+	}, nil
+}
+
+// coverEstimate adds liabilities and goals to a method's present value, nets off
+// existing assets and compares the result with existing cover
+func coverEstimate(method string, years int, presentValue, goals float64, req models.LifeInsuranceRequest, t *explain.Trace) models.CoverEstimate {
+	cover := t.Step("Cover needed by "+method, "max(presentValue + liabilities + goals − existingAssets, 0)",
+		map[string]float64{"presentValue": presentValue, "liabilities": req.Liabilities, "goals": goals, "existingAssets": req.ExistingAssets},
+		math.Max(presentValue+req.Liabilities+goals-req.ExistingAssets, 0))
+	gap := t.Step("Gap against existing cover for "+method, "max(cover − existingCover, 0)",
+		map[string]float64{"cover": cover, "existingCover": req.ExistingCover}, math.Max(cover-req.ExistingCover, 0))
+
+	return models.CoverEstimate{
+		Method:           method,
+		Years:            years,
+		PresentValue:     utils.RoundToTwoDecimals(presentValue),
+		Liabilities:      req.Liabilities,
+		Goals:            utils.RoundToTwoDecimals(goals),
+		ExistingAssets:   req.ExistingAssets,
+		RecommendedCover: utils.RoundToTwoDecimals(cover),
+		Gap:              utils.RoundToTwoDecimals(gap),
+	}
+}
+
+// growingAnnuityDue returns the present value of years annual payments, the
+// first made now and each growing at growth percent, discounted at rate percent
+func growingAnnuityDue(payment, growth, rate float64, years int) float64 {
+	ratio := (1 + growth/100) / (1 + rate/100)
+	if ratio == 1 {
+		return payment * float64(years)
+	}
+	return payment * (1 - math.Pow(ratio, float64(years))) / (1 - ratio)
+}
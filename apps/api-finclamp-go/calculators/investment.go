@@ -0,0 +1,64 @@
+package calculators
+
+import (
+	"finclamp-api/explain"
+	"finclamp-api/models"
+	"finclamp-api/utils"
+	"math"
+)
+
+// riskFactors scale projected gains by the investor's risk level
+var riskFactors = map[string]float64{
+	"low":    0.8,
+	"medium": 1.0,
+	"high":   1.2,
+}
+
+// Investment projects a lump-sum investment at a fixed annual return with a risk adjustment
+func Investment(req models.InvestmentCalculationRequest, t *explain.Trace) models.InvestmentCalculationResponse {
+	// Set default risk level if not provided
+	if req.RiskLevel == "" {
+		req.RiskLevel = "medium"
+	}
+
+	annualReturn := t.Step("Convert the expected return to a fraction", "R = expectedReturn / 100",
+		map[string]float64{"expectedReturn": req.ExpectedReturn}, req.ExpectedReturn/100)
+	futureValue := t.Step("Compound the amount annually", "FV = A × (1 + R)^term",
+		map[string]float64{"A": req.Amount, "R": annualReturn, "term": float64(req.Term)},
+		req.Amount*math.Pow(1+annualReturn, float64(req.Term)))
+	totalGain := t.Step("Calculate the projected gain", "gain = FV − A",
+		map[string]float64{"FV": futureValue, "A": req.Amount}, futureValue-req.Amount)
+	annualizedReturn := t.Step("Calculate the annualized return", "CAGR = (FV / A)^(1 / term) − 1",
+		map[string]float64{"FV": futureValue, "A": req.Amount, "term": float64(req.Term)},
+		math.Pow(futureValue/req.Amount, 1/float64(req.Term))-1)
+
+	riskFactor, exists := riskFactors[req.RiskLevel]
+	if !exists {
+		riskFactor = 1.0
+	}
+	riskFactor = t.Step("Look up the factor for risk level "+req.RiskLevel, "k = riskFactor(riskLevel)",
+		nil, riskFactor)
+
+	adjustedGain := t.Step("Apply the risk factor to the gain", "adjustedGain = gain × k",
+		map[string]float64{"gain": totalGain, "k": riskFactor}, totalGain*riskFactor)
+	adjustedFutureValue := t.Step("Calculate the risk-adjusted value", "adjustedValue = A + adjustedGain",
+		map[string]float64{"A": req.Amount, "adjustedGain": adjustedGain}, req.Amount+adjustedGain)
+
+	balanceAt := func(month int) float64 {
+		return req.Amount * math.Pow(1+annualReturn, float64(month)/12)
+	}
+	contributionsAt := func(int) float64 {
+		return req.Amount
+	}
+	series := growthSeries(req.SeriesOptions, req.Term*12, balanceAt, contributionsAt)
+
+	return models.InvestmentCalculationResponse{
+		ProjectedValue:   utils.RoundToTwoDecimals(futureValue),
+		AdjustedValue:    utils.RoundToTwoDecimals(adjustedFutureValue),
+		TotalGain:        utils.RoundToTwoDecimals(totalGain),
+		AdjustedGain:     utils.RoundToTwoDecimals(adjustedGain),
+		AnnualizedReturn: utils.RoundToTwoDecimals(annualizedReturn * 100), // Convert to percentage
+		Series:           series,
+		Explanation:      t.Steps(),
+	}
+}
@@ -0,0 +1,75 @@
+package calculators
+
+import (
+	"finclamp-api/explain"
+	"finclamp-api/models"
+	"finclamp-api/utils"
+	"math"
+)
+
+// Loan calculates the EMI, total repayment and total interest of a fixed-rate loan
+func Loan(req models.LoanCalculationRequest, t *explain.Trace) models.LoanCalculationResponse {
+	// Set default type if not provided
+	if req.Type == "" {
+		req.Type = "monthly"
+	}
+
+	monthlyRate := t.Step("Convert the annual rate to a monthly rate", "r = rate / 100 / 12",
+		map[string]float64{"rate": req.Rate}, req.Rate/100/12)
+	numPayments := t.Step("Count the monthly payments", "n = term × 12",
+		map[string]float64{"term": float64(req.Term)}, float64(req.Term*12))
+
+	growth := math.Pow(1+monthlyRate, numPayments)
+	monthlyPayment := t.Step("Calculate the monthly payment (EMI)", "EMI = P × r × (1 + r)^n / ((1 + r)^n − 1)",
+		map[string]float64{"P": req.Principal, "r": monthlyRate, "n": numPayments},
+		req.Principal*(monthlyRate*growth)/(growth-1))
+
+	totalAmount := t.Step("Calculate the total amount paid", "total = EMI × n",
+		map[string]float64{"EMI": monthlyPayment, "n": numPayments}, monthlyPayment*numPayments)
+	totalInterest := t.Step("Calculate the total interest", "interest = total − P",
+		map[string]float64{"total": totalAmount, "P": req.Principal}, totalAmount-req.Principal)
+
+	return models.LoanCalculationResponse{
+		MonthlyPayment: utils.RoundToTwoDecimals(monthlyPayment),
+		TotalAmount:    utils.RoundToTwoDecimals(totalAmount),
+		TotalInterest:  utils.RoundToTwoDecimals(totalInterest),
+		NumPayments:    int(numPayments),
+		Explanation:    t.Steps(),
+	}
+}
+
+// Amortize builds a monthly repayment schedule for a fixed-rate loan and returns
+// the EMI, the schedule and the unrounded cumulative interest at the end of each
+// month. The final payment absorbs any rounding residue so the balance always
+// reaches zero.
+func Amortize(principal, rate float64, months int) (float64, []models.AmortizationEntry, []float64) {
+	monthlyRate := rate / 100 / 12
+	growth := math.Pow(1+monthlyRate, float64(months))
+	payment := principal * (monthlyRate * growth) / (growth - 1)
+
+	schedule := make([]models.AmortizationEntry, 0, months)
+	cumulativeInterest := make([]float64, 0, months)
+
+	balance := principal
+	paidInterest := 0.0
+	for month := 1; month <= months; month++ {
+		interest := balance * monthlyRate
+		principalPart := payment - interest
+		if month == months {
+			principalPart = balance
+		}
+		balance -= principalPart
+		paidInterest += interest
+
+		schedule = append(schedule, models.AmortizationEntry{
+			Month:     month,
+			Payment:   utils.RoundToTwoDecimals(principalPart + interest),
+			Principal: utils.RoundToTwoDecimals(principalPart),
+			Interest:  utils.RoundToTwoDecimals(interest),
+			Balance:   utils.RoundToTwoDecimals(math.Max(balance, 0)),
+		})
+		cumulativeInterest = append(cumulativeInterest, paidInterest)
+	}
+
+	return payment, schedule, cumulativeInterest
+}
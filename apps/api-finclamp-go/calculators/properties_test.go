@@ -0,0 +1,216 @@
+package calculators
+
+import (
+	"finclamp-api/models"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// Property tests check invariants over many generated inputs. The generator is
+// seeded so a failure reproduces; each failure reports the inputs that broke it.
+const propertyCases = 500
+
+func newRand() *rand.Rand {
+	return rand.New(rand.NewSource(20240401))
+}
+
+// randomLoan returns a principal of 10 thousand to 10 crore, a rate of 0.5% to
+// 30% and a term of 1 to 30 years
+func randomLoan(r *rand.Rand) models.LoanCalculationRequest {
+	return models.LoanCalculationRequest{
+		Principal: math.Round(1e4 + r.Float64()*1e8),
+		Rate:      math.Round((0.5+r.Float64()*29.5)*100) / 100,
+		Term:      1 + r.Intn(30),
+	}
+}
+
+// Rounding each figure to paise allows an error of half a paisa per rounded amount
+func roundingSlack(amounts int) float64 {
+	return 0.005*float64(amounts) + 1e-6
+}
+
+func TestLoanTotalPaidIsPrincipalPlusInterest(t *testing.T) {
+	r := newRand()
+	for i := 0; i < propertyCases; i++ {
+		req := randomLoan(r)
+		resp := Loan(req, nil)
+
+		if diff := math.Abs(resp.TotalAmount - (req.Principal + resp.TotalInterest)); diff > roundingSlack(2) {
+			t.Fatalf("%+v: totalAmount %.2f ≠ principal %.2f + totalInterest %.2f", req, resp.TotalAmount, req.Principal, resp.TotalInterest)
+		}
+		if diff := math.Abs(resp.TotalAmount - resp.MonthlyPayment*float64(resp.NumPayments)); diff > roundingSlack(resp.NumPayments+1) {
+			t.Fatalf("%+v: totalAmount %.2f ≠ monthlyPayment %.2f × %d", req, resp.TotalAmount, resp.MonthlyPayment, resp.NumPayments)
+		}
+		if resp.TotalInterest <= 0 {
+			t.Fatalf("%+v: totalInterest %.2f is not positive", req, resp.TotalInterest)
+		}
+	}
+}
+
+func TestAmortizationScheduleReachesZero(t *testing.T) {
+	r := newRand()
+	for i := 0; i < propertyCases; i++ {
+		req := randomLoan(r)
+		months := req.Term * 12
+		payment, schedule, cumulativeInterest := Amortize(req.Principal, req.Rate, months)
+
+		if len(schedule) != months {
+			t.Fatalf("%+v: schedule has %d months, want %d", req, len(schedule), months)
+		}
+		if last := schedule[months-1]; last.Balance != 0 {
+			t.Fatalf("%+v: balance after month %d is %.2f, want 0", req, last.Month, last.Balance)
+		}
+
+		principalPaid, interestPaid, previousBalance := 0.0, 0.0, req.Principal
+		for _, entry := range schedule {
+			if entry.Balance > previousBalance {
+				t.Fatalf("%+v: balance rose from %.2f to %.2f in month %d", req, previousBalance, entry.Balance, entry.Month)
+			}
+			if math.Abs(entry.Payment-payment) > roundingSlack(2) && entry.Month != months {
+				t.Fatalf("%+v: month %d payment %.2f ≠ EMI %.2f", req, entry.Month, entry.Payment, payment)
+			}
+			previousBalance = entry.Balance
+			principalPaid += entry.Principal
+			interestPaid += entry.Interest
+		}
+		if diff := math.Abs(principalPaid - req.Principal); diff > roundingSlack(months) {
+			t.Fatalf("%+v: principal repaid %.2f ≠ principal %.2f", req, principalPaid, req.Principal)
+		}
+		if diff := math.Abs(interestPaid - cumulativeInterest[months-1]); diff > roundingSlack(months) {
+			t.Fatalf("%+v: interest in schedule %.2f ≠ cumulative interest %.2f", req, interestPaid, cumulativeInterest[months-1])
+		}
+	}
+}
+
+func TestLoanPaymentRisesWithRate(t *testing.T) {
+	r := newRand()
+	for i := 0; i < propertyCases; i++ {
+		low := randomLoan(r)
+		high := low
+		high.Rate += 0.25 + r.Float64()*5
+
+		lowResp, highResp := Loan(low, nil), Loan(high, nil)
+		if highResp.MonthlyPayment <= lowResp.MonthlyPayment {
+			t.Fatalf("%+v → rate %.2f: EMI %.2f did not rise from %.2f", low, high.Rate, highResp.MonthlyPayment, lowResp.MonthlyPayment)
+		}
+		if highResp.TotalInterest <= lowResp.TotalInterest {
+			t.Fatalf("%+v → rate %.2f: interest %.2f did not rise from %.2f", low, high.Rate, highResp.TotalInterest, lowResp.TotalInterest)
+		}
+	}
+}
+
+func TestLongerTenureLowersPaymentAndRaisesInterest(t *testing.T) {
+	r := newRand()
+	for i := 0; i < propertyCases; i++ {
+		short := randomLoan(r)
+		long := short
+		long.Term += 1 + r.Intn(10)
+
+		shortResp, longResp := Loan(short, nil), Loan(long, nil)
+		if longResp.MonthlyPayment >= shortResp.MonthlyPayment {
+			t.Fatalf("%+v → term %d: EMI %.2f did not fall from %.2f", short, long.Term, longResp.MonthlyPayment, shortResp.MonthlyPayment)
+		}
+		if longResp.TotalInterest <= shortResp.TotalInterest {
+			t.Fatalf("%+v → term %d: interest %.2f did not rise from %.2f", short, long.Term, longResp.TotalInterest, shortResp.TotalInterest)
+		}
+	}
+}
+
+func TestSavingsGrowWithRateAndTerm(t *testing.T) {
+	r := newRand()
+	for i := 0; i < propertyCases; i++ {
+		req := models.SavingsCalculationRequest{
+			Principal:           math.Round(r.Float64() * 1e7),
+			MonthlyContribution: math.Round(r.Float64() * 1e5),
+			Rate:                math.Round((0.5+r.Float64()*20)*100) / 100,
+			Term:                1 + r.Intn(40),
+		}
+		if req.Principal == 0 && req.MonthlyContribution == 0 {
+			continue
+		}
+		resp := Savings(req, nil)
+
+		if diff := math.Abs(resp.FinalAmount - (resp.TotalContributions + resp.TotalInterest)); diff > roundingSlack(3) {
+			t.Fatalf("%+v: finalAmount %.2f ≠ contributions %.2f + interest %.2f", req, resp.FinalAmount, resp.TotalContributions, resp.TotalInterest)
+		}
+
+		higherRate := req
+		higherRate.Rate += 0.25 + r.Float64()*5
+		if got := Savings(higherRate, nil).FinalAmount; got <= resp.FinalAmount {
+			t.Fatalf("%+v → rate %.2f: final amount %.2f did not rise from %.2f", req, higherRate.Rate, got, resp.FinalAmount)
+		}
+		longer := req
+		longer.Term += 1 + r.Intn(10)
+		if got := Savings(longer, nil).FinalAmount; got <= resp.FinalAmount {
+			t.Fatalf("%+v → term %d: final amount %.2f did not rise from %.2f", req, longer.Term, got, resp.FinalAmount)
+		}
+	}
+}
+
+func TestGrowthSeriesKeepsEndpointsWithinMaxPoints(t *testing.T) {
+	r := newRand()
+	for i := 0; i < propertyCases; i++ {
+		req := models.SavingsCalculationRequest{
+			Principal: math.Round(1e4 + r.Float64()*1e7),
+			Rate:      math.Round((0.5+r.Float64()*20)*100) / 100,
+			Term:      1 + r.Intn(100),
+			SeriesOptions: models.SeriesOptions{
+				Series:    []string{"monthly", "yearly"}[r.Intn(2)],
+				MaxPoints: r.Intn(50),
+			},
+		}
+		resp := Savings(req, nil)
+
+		maxPoints := req.MaxPoints
+		if maxPoints == 0 {
+			maxPoints = defaultSeriesPoints
+		}
+		if n := len(resp.Series); n == 0 || n > maxPoints {
+			t.Fatalf("%+v: series has %d points, want 1 to %d", req, n, maxPoints)
+		}
+		if last := resp.Series[len(resp.Series)-1]; last.Month != resp.NumMonths || last.Balance != resp.FinalAmount {
+			t.Fatalf("%+v: series ends at month %d with %.2f, want month %d with %.2f", req, last.Month, last.Balance, resp.NumMonths, resp.FinalAmount)
+		}
+		if maxPoints > 1 && resp.Series[0].Month != 0 {
+			t.Fatalf("%+v: series starts at month %d, want 0", req, resp.Series[0].Month)
+		}
+		for j := 1; j < len(resp.Series); j++ {
+			if resp.Series[j].Month <= resp.Series[j-1].Month {
+				t.Fatalf("%+v: months %d and %d are out of order", req, resp.Series[j-1].Month, resp.Series[j].Month)
+			}
+		}
+	}
+}
+
+func TestGratuityNeverFallsWithService(t *testing.T) {
+	r := newRand()
+	for i := 0; i < propertyCases; i++ {
+		covered := r.Intn(2) == 0
+		req := models.GratuityRequest{
+			MonthlySalary: math.Round(1e4 + r.Float64()*5e5),
+			ServiceYears:  r.Intn(40),
+			ServiceMonths: r.Intn(12),
+			CoveredByAct:  &covered,
+		}
+		resp, err := Gratuity(req, nil)
+		if err != nil {
+			t.Fatalf("%+v: %v", req, err)
+		}
+
+		longer := req
+		if longer.ServiceMonths++; longer.ServiceMonths == 12 {
+			longer.ServiceYears, longer.ServiceMonths = longer.ServiceYears+1, 0
+		}
+		longerResp, err := Gratuity(longer, nil)
+		if err != nil {
+			t.Fatalf("%+v: %v", longer, err)
+		}
+		if longerResp.Gratuity < resp.Gratuity {
+			t.Fatalf("covered=%t %+v: gratuity fell from %.2f to %.2f after another month", covered, req, resp.Gratuity, longerResp.Gratuity)
+		}
+		if math.Abs(resp.Exempt+resp.Taxable-resp.Gratuity) > roundingSlack(3) || resp.Exempt > gratuityExemptLimit {
+			t.Fatalf("%+v: exempt %.2f + taxable %.2f does not split gratuity %.2f within the limit", req, resp.Exempt, resp.Taxable, resp.Gratuity)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package calculators
+
+import (
+	"finclamp-api/explain"
+	"finclamp-api/models"
+	"finclamp-api/registry"
+	"finclamp-api/utils"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// riskAllocations maps the risk levels used by the investment calculator onto
+// target asset allocations in percent
+var riskAllocations = map[string]map[string]float64{
+	"low":    {"equity": 30, "debt": 60, "gold": 10},
+	"medium": {"equity": 60, "debt": 30, "gold": 10},
+	"high":   {"equity": 80, "debt": 15, "gold": 5},
+}
+
+// defaultToleranceBand is the drift, in percentage points, tolerated before an
+// asset class is rebalanced
+const defaultToleranceBand = 5.0
+
+// Rebalance returns the trades that bring a portfolio back to its target
+// allocation. Only asset classes outside their tolerance band are traded back
+// to target; in-band classes absorb the balancing amount, starting with the
+// most under- or overweight. Fresh cash is always deployed into underweight
+// classes, and in buy-only mode nothing is sold.
+func Rebalance(req models.RebalanceRequest, t *explain.Trace) (models.RebalanceResponse, error) {
+	holdings := normalizeAssets(req.Holdings)
+
+	target := normalizeAssets(req.TargetAllocation)
+	if len(target) == 0 {
+		// Set default risk profile if not provided
+		if req.RiskProfile == "" {
+			req.RiskProfile = "medium"
+		}
+		target = riskAllocations[req.RiskProfile]
+	}
+
+	targetSum := 0.0
+	for _, percent := range target {
+		targetSum += percent
+	}
+	if math.Abs(targetSum-100) > 0.01 {
+		return models.RebalanceResponse{}, &registry.InputError{Message: "Invalid target allocation", Detail: fmt.Sprintf("target percentages must add up to 100, got %.2f", targetSum)}
+	}
+
+	if req.ToleranceBand == 0 {
+		req.ToleranceBand = defaultToleranceBand
+	}
+	bands := normalizeAssets(req.ToleranceBands)
+
+	classes := assetClasses(holdings, target)
+	invested := 0.0
+	for _, value := range holdings {
+		invested += value
+	}
+	total := t.Step("Total value including fresh cash", "total = Σ holdings + cash",
+		map[string]float64{"holdings": invested, "cash": req.FreshCash}, invested+req.FreshCash)
+	if total == 0 {
+		return models.RebalanceResponse{}, &registry.InputError{Message: "Empty portfolio", Detail: "holdings or freshCash must be greater than zero"}
+	}
+
+	positions := make([]models.AssetPosition, len(classes))
+	targetValues := make([]float64, len(classes))
+	outOfBand := []int{}
+	inBand := []int{}
+	for i, class := range classes {
+		currentPercent := 0.0
+		if invested > 0 {
+			currentPercent = holdings[class] / invested * 100
+		}
+		band, ok := bands[class]
+		if !ok {
+			band = req.ToleranceBand
+		}
+		drift := t.Step("Drift of "+class+" from target", "drift = current% − target%",
+			map[string]float64{"current": currentPercent, "target": target[class]}, currentPercent-target[class])
+		targetValues[i] = t.Step("Target value of "+class, "target% × total / 100",
+			map[string]float64{"target": target[class], "total": total}, target[class]*total/100)
+
+		positions[i] = models.AssetPosition{
+			AssetClass:     class,
+			CurrentValue:   utils.RoundToTwoDecimals(holdings[class]),
+			CurrentPercent: utils.RoundToTwoDecimals(currentPercent),
+			TargetPercent:  target[class],
+			Drift:          utils.RoundToTwoDecimals(drift),
+			Band:           band,
+			OutOfBand:      math.Abs(drift) > band,
+		}
+		if positions[i].OutOfBand {
+			outOfBand = append(outOfBand, i)
+		} else {
+			inBand = append(inBand, i)
+		}
+	}
+
+	// Positive amounts are buys, negative amounts are sells
+	amounts := make([]float64, len(classes))
+	if req.BuyOnly || len(outOfBand) == 0 {
+		deficits := make([]float64, len(classes))
+		for i := range classes {
+			deficits[i] = math.Max(targetValues[i]-holdings[classes[i]], 0)
+		}
+		buys := waterfill(deficits, req.FreshCash)
+		for i := range amounts {
+			amounts[i] = buys[i]
+		}
+	} else {
+		residual := req.FreshCash
+		for _, i := range outOfBand {
+			amounts[i] = targetValues[i] - holdings[classes[i]]
+			residual -= amounts[i]
+		}
+		residual = t.Step("Cash left after trading out-of-band classes to target", "residual = cash − Σ (target − current) for out-of-band classes",
+			map[string]float64{"cash": req.FreshCash}, residual)
+
+		// The in-band classes' combined gap to target always equals the residual,
+		// so the water-fill below fully absorbs it
+		gaps := make([]float64, len(inBand))
+		for k, i := range inBand {
+			gap := targetValues[i] - holdings[classes[i]]
+			if residual < 0 {
+				gap = -gap
+			}
+			gaps[k] = math.Max(gap, 0)
+		}
+		fills := waterfill(gaps, math.Abs(residual))
+		for k, i := range inBand {
+			if residual < 0 {
+				amounts[i] = -fills[k]
+			} else {
+				amounts[i] = fills[k]
+			}
+		}
+	}
+
+	response := models.RebalanceResponse{
+		TotalValue:       utils.RoundToTwoDecimals(total),
+		TargetAllocation: target,
+		RebalanceNeeded:  len(outOfBand) > 0 || req.FreshCash > 0,
+		Trades:           []models.RebalanceTrade{},
+		Positions:        positions,
+	}
+
+	traded := 0.0
+	for i, class := range classes {
+		amount := utils.RoundToTwoDecimals(amounts[i])
+		if amount >= 0.01 {
+			response.Trades = append(response.Trades, models.RebalanceTrade{AssetClass: class, Action: "buy", Amount: amount})
+		} else if amount <= -0.01 {
+			response.Trades = append(response.Trades, models.RebalanceTrade{AssetClass: class, Action: "sell", Amount: -amount})
+		}
+		traded += math.Abs(amounts[i])
+
+		after := holdings[class] + amounts[i]
+		positions[i].AfterValue = utils.RoundToTwoDecimals(after)
+		positions[i].AfterPercent = utils.RoundToTwoDecimals(after / total * 100)
+	}
+
+	response.TotalTraded = utils.RoundToTwoDecimals(t.Step("Total amount traded", "Σ |trade|", nil, traded))
+	response.Explanation = t.Steps()
+
+	return response, nil
+}
+
+// waterfill spreads amount across gaps so the largest gaps shrink first, leaving
+// every touched gap at the same level. It returns how much each gap receives.
+func waterfill(gaps []float64, amount float64) []float64 {
+	fills := make([]float64, len(gaps))
+
+	capacity, highest := 0.0, 0.0
+	for _, gap := range gaps {
+		capacity += gap
+		highest = math.Max(highest, gap)
+	}
+	if amount <= 0 || capacity <= 0 {
+		return fills
+	}
+	if amount >= capacity {
+		copy(fills, gaps)
+		return fills
+	}
+
+	filled := func(level float64) float64 {
+		sum := 0.0
+		for _, gap := range gaps {
+			sum += math.Max(gap-level, 0)
+		}
+		return sum
+	}
+
+	low, high := 0.0, highest
+	for i := 0; i < 100; i++ {
+		mid := (low + high) / 2
+		if filled(mid) > amount {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+
+	for i, gap := range gaps {
+		fills[i] = math.Max(gap-high, 0)
+	}
+	return fills
+}
+
+// normalizeAssets lower-cases asset class names so "Equity" and "equity" match
+func normalizeAssets(values map[string]float64) map[string]float64 {
+	normalized := make(map[string]float64, len(values))
+	for class, value := range values {
+		normalized[strings.ToLower(strings.TrimSpace(class))] += value
+	}
+	return normalized
+}
+
+// assetClasses returns every asset class that is held or targeted, sorted
+func assetClasses(holdings, target map[string]float64) []string {
+	seen := make(map[string]bool)
+	var classes []string
+	for _, values := range []map[string]float64{holdings, target} {
+		for class := range values {
+			if !seen[class] {
+				seen[class] = true
+				classes = append(classes, class)
+			}
+		}
+	}
+	sort.Strings(classes)
+	return classes
+}
@@ -0,0 +1,111 @@
+package calculators
+
+import (
+	"finclamp-api/explain"
+	"finclamp-api/models"
+	"finclamp-api/utils"
+	"fmt"
+)
+
+// Refinance compares an existing loan with one or more refinance offers
+func Refinance(req models.RefinanceRequest, t *explain.Trace) models.RefinanceResponse {
+	current, currentInterest := buildRefinanceOption("Current loan", req.OutstandingBalance, req.CurrentRate, req.RemainingMonths, 0, t)
+
+	response := models.RefinanceResponse{
+		Current: current,
+		Offers:  make([]models.RefinanceOption, 0, len(req.Offers)),
+	}
+
+	bestSavings := 0.0
+	for i, offer := range req.Offers {
+		name := offer.Name
+		if name == "" {
+			name = fmt.Sprintf("Offer %d", i+1)
+		}
+
+		fees := t.Step("Calculate the switching fees for "+name, "fees = flatFee + balance × feePercent / 100",
+			map[string]float64{"flatFee": offer.ProcessingFee, "balance": req.OutstandingBalance, "feePercent": offer.ProcessingFeePercent},
+			offer.ProcessingFee+req.OutstandingBalance*offer.ProcessingFeePercent/100)
+		option, offerInterest := buildRefinanceOption(name, req.OutstandingBalance, offer.Rate, offer.TenureMonths, fees, t)
+
+		option.NetSavings = utils.RoundToTwoDecimals(t.Step("Calculate the net savings of "+name, "savings = currentCost − offerCost",
+			map[string]float64{"currentCost": current.TotalCost, "offerCost": option.TotalCost}, current.TotalCost-option.TotalCost))
+		option.BreakEvenMonth = breakEvenMonth(currentInterest, offerInterest, fees)
+
+		breakEven := 0.0
+		if option.BreakEvenMonth != nil {
+			breakEven = float64(*option.BreakEvenMonth)
+		}
+		t.Step("Find the first month where interest saved covers the fees for "+name+" (0 if never)",
+			"min m such that Σinterest_current(m) − Σinterest_offer(m) ≥ fees",
+			map[string]float64{"fees": fees}, breakEven)
+
+		if option.NetSavings > bestSavings {
+			bestSavings = option.NetSavings
+			response.BestOffer = name
+		}
+
+		response.Offers = append(response.Offers, option)
+	}
+
+	response.Explanation = t.Steps()
+	return response
+}
+
+// buildRefinanceOption amortizes a loan and returns the option summary along with
+// the unrounded cumulative interest paid at the end of each month
+func buildRefinanceOption(name string, principal, rate float64, months int, fees float64, t *explain.Trace) (models.RefinanceOption, []float64) {
+	payment, schedule, cumulativeInterest := Amortize(principal, rate, months)
+
+	payment = t.Step("Calculate the EMI for "+name, "EMI = P × r × (1 + r)^n / ((1 + r)^n − 1), r = rate / 100 / 12",
+		map[string]float64{"P": principal, "rate": rate, "n": float64(months)}, payment)
+	totalInterest := t.Step("Sum the interest over the "+name+" schedule", "interest = Σ balance × r",
+		map[string]float64{"n": float64(months)}, cumulativeInterest[len(cumulativeInterest)-1])
+	totalPayment := principal + totalInterest
+	totalCost := t.Step("Calculate the total cost of "+name, "cost = P + interest + fees",
+		map[string]float64{"P": principal, "interest": totalInterest, "fees": fees}, totalPayment+fees)
+
+	option := models.RefinanceOption{
+		Name:           name,
+		Rate:           rate,
+		TenureMonths:   months,
+		MonthlyPayment: utils.RoundToTwoDecimals(payment),
+		TotalPayment:   utils.RoundToTwoDecimals(totalPayment),
+		TotalInterest:  utils.RoundToTwoDecimals(totalInterest),
+		Fees:           utils.RoundToTwoDecimals(fees),
+		TotalCost:      utils.RoundToTwoDecimals(totalCost),
+		Schedule:       schedule,
+	}
+
+	return option, cumulativeInterest
+}
+
+// breakEvenMonth returns the first month in which the interest saved by switching
+// has covered the upfront fees, or nil if the offer never pays for itself.
+// Principal repaid is the same on both sides, so comparing cumulative interest
+// is equivalent to comparing amount paid plus amount still owed.
+func breakEvenMonth(currentInterest, offerInterest []float64, fees float64) *int {
+	horizon := len(currentInterest)
+	if len(offerInterest) > horizon {
+		horizon = len(offerInterest)
+	}
+
+	for month := 1; month <= horizon; month++ {
+		saved := interestAt(currentInterest, month) - interestAt(offerInterest, month)
+		if saved >= fees {
+			m := month
+			return &m
+		}
+	}
+
+	return nil
+}
+
+// interestAt returns cumulative interest paid by the end of a month, holding the
+// final value once the loan has been repaid
+func interestAt(cumulative []float64, month int) float64 {
+	if month > len(cumulative) {
+		return cumulative[len(cumulative)-1]
+	}
+	return cumulative[month-1]
+}
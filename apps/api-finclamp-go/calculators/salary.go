@@ -0,0 +1,155 @@
+package calculators
+
+import (
+	"finclamp-api/explain"
+	"finclamp-api/models"
+	"finclamp-api/registry"
+	"finclamp-api/tax"
+	"finclamp-api/utils"
+	"fmt"
+	"math"
+)
+
+// Defaults for the salary structure rules
+const (
+	defaultBasicPercent    = 40
+	defaultPFRate          = 12
+	defaultPFWageCeiling   = 15000
+	defaultGratuityPercent = 4.81
+	defaultProfessionalTax = 2400
+	section80CLimit        = 150000
+)
+
+// SalaryStructure splits an annual CTC into earnings, employer contributions
+// and deductions, works out income tax under the chosen regime and returns the
+// annual and monthly take-home pay
+func SalaryStructure(req models.SalaryStructureRequest, t *explain.Trace) (models.SalaryStructureResponse, error) {
+	rules := req.Rules
+	regimeName := req.Regime
+	if regimeName == "" {
+		regimeName = tax.DefaultRegime
+	}
+	regime := tax.Regimes[regimeName]
+
+	basicPercent := optional(rules.BasicPercent, defaultBasicPercent)
+	hraPercent := float64(nonMetroHRAPercent)
+	if req.Metro {
+		hraPercent = metroHRAPercent
+	}
+	hraPercent = optional(rules.HRAPercent, hraPercent)
+	pfRate := optional(rules.PFRate, defaultPFRate)
+	pfWageCeiling := optional(rules.PFWageCeiling, defaultPFWageCeiling)
+	professionalTax := optional(rules.ProfessionalTax, defaultProfessionalTax)
+	gratuityPercent := optional(rules.GratuityPercent, defaultGratuityPercent)
+
+	basic := t.Step("Basic salary", "ctc × basicPercent",
+		map[string]float64{"ctc": req.CTC, "basicPercent": basicPercent / 100}, req.CTC*basicPercent/100)
+	pfWages := basic
+	if pfWageCeiling > 0 {
+		pfWages = math.Min(basic, pfWageCeiling*12)
+	}
+	pf := t.Step("Provident fund contribution", "pfWages × pfRate",
+		map[string]float64{"pfWages": pfWages, "pfRate": pfRate / 100}, pfWages*pfRate/100)
+	gratuity := t.Step("Gratuity provision", "basic × gratuityPercent",
+		map[string]float64{"basic": basic, "gratuityPercent": gratuityPercent / 100}, basic*gratuityPercent/100)
+
+	employerContributions := []models.SalaryComponent{}
+	carvedOut := 0.0
+	if rules.EmployerPFInCTC == nil || *rules.EmployerPFInCTC {
+		employerContributions = append(employerContributions, salaryComponent("Employer PF", pf))
+		carvedOut += pf
+	}
+	if rules.GratuityInCTC == nil || *rules.GratuityInCTC {
+		employerContributions = append(employerContributions, salaryComponent("Gratuity", gratuity))
+		carvedOut += gratuity
+	}
+
+	gross := t.Step("Gross salary", "ctc − employerContributions",
+		map[string]float64{"ctc": req.CTC, "employerContributions": carvedOut}, req.CTC-carvedOut)
+	variable := req.CTC * rules.VariablePercent / 100
+	hra := basic * hraPercent / 100
+	special := t.Step("Special allowance", "gross − basic − hra − variablePay",
+		map[string]float64{"gross": gross, "basic": basic, "hra": hra, "variablePay": variable}, gross-basic-hra-variable)
+	if special < 0 {
+		return models.SalaryStructureResponse{}, &registry.InputError{Message: "Invalid salary rules",
+			Detail: fmt.Sprintf("basic, HRA, variable pay and employer contributions (%.2f) exceed the CTC (%.2f)", basic+hra+variable+carvedOut, req.CTC)}
+	}
+
+	earnings := []models.SalaryComponent{
+		salaryComponent("Basic", basic),
+		salaryComponent("HRA", hra),
+		salaryComponent("Special allowance", special),
+	}
+	if variable > 0 {
+		earnings = append(earnings, salaryComponent("Variable pay", variable))
+	}
+
+	exemptions := []models.SalaryComponent{salaryComponent("Standard deduction", regime.StandardDeduction)}
+	if regime.AllowsDeductions {
+		if req.MonthlyRent > 0 {
+			_, hraExempt := hraExemption(basic, hra, req.MonthlyRent*12, req.Metro, t)
+			exemptions = append(exemptions, salaryComponent("HRA exemption", hraExempt))
+		}
+		if professionalTax > 0 {
+			exemptions = append(exemptions, salaryComponent("Professional tax", professionalTax))
+		}
+		section80C := t.Step("Section 80C deduction", "min(employeePF + section80c, 150000)",
+			map[string]float64{"employeePF": pf, "section80c": req.Section80C}, math.Min(pf+req.Section80C, section80CLimit))
+		exemptions = append(exemptions, salaryComponent("Section 80C", section80C))
+		if req.OtherDeductions > 0 {
+			exemptions = append(exemptions, salaryComponent("Other deductions", req.OtherDeductions))
+		}
+	}
+	totalExemptions := 0.0
+	for _, exemption := range exemptions {
+		totalExemptions += exemption.Annual
+	}
+
+	taxable := t.Step("Taxable income", "max(gross − exemptions, 0)",
+		map[string]float64{"gross": gross, "exemptions": totalExemptions}, math.Max(gross-totalExemptions, 0))
+	incomeTax := regime.Compute(taxable)
+	t.Step("Income tax under the "+regime.Name+" regime", "slabTax − rebate + surcharge + cess",
+		map[string]float64{"slabTax": incomeTax.SlabTax, "rebate": incomeTax.Rebate, "surcharge": incomeTax.Surcharge, "cess": incomeTax.Cess},
+		incomeTax.TotalTax)
+
+	deductions := []models.SalaryComponent{
+		salaryComponent("Employee PF", pf),
+		salaryComponent("Professional tax", professionalTax),
+		salaryComponent("Income tax", incomeTax.TotalTax),
+	}
+	annualInHand := t.Step("Annual take-home", "gross − employeePF − professionalTax − incomeTax",
+		map[string]float64{"gross": gross, "employeePF": pf, "professionalTax": professionalTax, "incomeTax": incomeTax.TotalTax},
+		gross-pf-professionalTax-incomeTax.TotalTax)
+	monthlyInHand := t.Step("Monthly take-home", "(annualInHand − variablePay) / 12",
+		map[string]float64{"annualInHand": annualInHand, "variablePay": variable}, (annualInHand-variable)/12)
+
+	return models.SalaryStructureResponse{
+		CTC:                   req.CTC,
+		Earnings:              earnings,
+		EmployerContributions: employerContributions,
+		GrossSalary:           utils.RoundToTwoDecimals(gross),
+		Deductions:            deductions,
+		TaxExemptions:         exemptions,
+		IncomeTax:             incomeTax,
+		AnnualInHand:          utils.RoundToTwoDecimals(annualInHand),
+		MonthlyInHand:         utils.RoundToTwoDecimals(monthlyInHand),
+		VariablePay:           utils.RoundToTwoDecimals(variable),
+		Explanation:           t.Steps(),
+	}, nil
+}
+
+func salaryComponent(name string, annual float64) models.SalaryComponent {
+	return models.SalaryComponent{
+		Name:    name,
+		Annual:  utils.RoundToTwoDecimals(annual),
+		Monthly: utils.RoundToTwoDecimals(annual / 12),
+	}
+}
+
+// optional returns the value of an optional rule, or fallback when unset
+func optional(value *float64, fallback float64) float64 {
+	if value == nil {
+		return fallback
+	}
+	return *value
+}
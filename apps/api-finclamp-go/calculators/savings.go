@@ -0,0 +1,59 @@
+package calculators
+
+import (
+	"finclamp-api/explain"
+	"finclamp-api/models"
+	"finclamp-api/utils"
+	"math"
+)
+
+// Savings calculates the future value of a lump sum plus optional monthly contributions
+func Savings(req models.SavingsCalculationRequest, t *explain.Trace) models.SavingsCalculationResponse {
+	monthlyRate := t.Step("Convert the annual rate to a monthly rate", "r = rate / 100 / 12",
+		map[string]float64{"rate": req.Rate}, req.Rate/100/12)
+	numMonths := t.Step("Count the months", "n = term × 12",
+		map[string]float64{"term": float64(req.Term)}, float64(req.Term*12))
+
+	// Future value with compound interest
+	futureValue := t.Step("Grow the principal with monthly compounding", "FV = P × (1 + r)^n",
+		map[string]float64{"P": req.Principal, "r": monthlyRate, "n": numMonths},
+		req.Principal*math.Pow(1+monthlyRate, numMonths))
+
+	// Future value of monthly contributions (annuity)
+	var contributionValue float64
+	if req.MonthlyContribution > 0 && monthlyRate > 0 {
+		contributionValue = t.Step("Grow the monthly contributions as an annuity", "FVc = C × ((1 + r)^n − 1) / r",
+			map[string]float64{"C": req.MonthlyContribution, "r": monthlyRate, "n": numMonths},
+			req.MonthlyContribution*((math.Pow(1+monthlyRate, numMonths)-1)/monthlyRate))
+	}
+
+	totalValue := t.Step("Add both future values", "total = FV + FVc",
+		map[string]float64{"FV": futureValue, "FVc": contributionValue}, futureValue+contributionValue)
+	totalContributions := t.Step("Sum everything deposited", "deposits = P + C × n",
+		map[string]float64{"P": req.Principal, "C": req.MonthlyContribution, "n": numMonths},
+		req.Principal+(req.MonthlyContribution*numMonths))
+	totalInterest := t.Step("Calculate the interest earned", "interest = total − deposits",
+		map[string]float64{"total": totalValue, "deposits": totalContributions}, totalValue-totalContributions)
+
+	balanceAt := func(month int) float64 {
+		growth := math.Pow(1+monthlyRate, float64(month))
+		balance := req.Principal * growth
+		if req.MonthlyContribution > 0 && monthlyRate > 0 {
+			balance += req.MonthlyContribution * ((growth - 1) / monthlyRate)
+		}
+		return balance
+	}
+	contributionsAt := func(month int) float64 {
+		return req.Principal + req.MonthlyContribution*float64(month)
+	}
+	series := growthSeries(req.SeriesOptions, int(numMonths), balanceAt, contributionsAt)
+
+	return models.SavingsCalculationResponse{
+		FinalAmount:        utils.RoundToTwoDecimals(totalValue),
+		TotalContributions: utils.RoundToTwoDecimals(totalContributions),
+		TotalInterest:      utils.RoundToTwoDecimals(totalInterest),
+		NumMonths:          int(numMonths),
+		Series:             series,
+		Explanation:        t.Steps(),
+	}
+}
@@ -0,0 +1,83 @@
+package calculators
+
+import (
+	"finclamp-api/models"
+	"finclamp-api/utils"
+	"math"
+)
+
+// growthSeries samples a balance curve at monthly or yearly intervals from month
+// 0 to months, keeping at most opts.MaxPoints of them (defaultSeriesPoints when
+// unset). balanceAt and contributionsAt return the cumulative values at the end
+// of a month; they are only called for the months kept.
+func growthSeries(opts models.SeriesOptions, months int, balanceAt, contributionsAt func(month int) float64) []models.GrowthPoint {
+	if opts.Series == "" {
+		return nil
+	}
+
+	step := 1
+	if opts.Series == "yearly" {
+		step = 12
+	}
+	maxPoints := opts.MaxPoints
+	if maxPoints == 0 {
+		maxPoints = defaultSeriesPoints
+	}
+
+	sampled := sampleMonths(months, step, maxPoints)
+	points := make([]models.GrowthPoint, len(sampled))
+	for i, month := range sampled {
+		points[i] = growthPoint(month, opts.InflationRate, balanceAt, contributionsAt)
+	}
+	return points
+}
+
+func growthPoint(month int, inflationRate float64, balanceAt, contributionsAt func(month int) float64) models.GrowthPoint {
+	balance := balanceAt(month)
+	contributions := contributionsAt(month)
+	deflator := math.Pow(1+inflationRate/100, float64(month)/12)
+
+	return models.GrowthPoint{
+		Month:         month,
+		Balance:       utils.RoundToTwoDecimals(balance),
+		Contributions: utils.RoundToTwoDecimals(contributions),
+		Interest:      utils.RoundToTwoDecimals(balance - contributions),
+		RealValue:     utils.RoundToTwoDecimals(balance / deflator),
+	}
+}
+
+// defaultSeriesPoints is the number of points a series is downsampled to when
+// the request does not set maxPoints
+const defaultSeriesPoints = 120
+
+// sampleMonths returns the months of a series taken every step months from 0 to
+// months, plus months itself, downsampled to at most maxPoints evenly spaced
+// months. The first and last are always kept so the curve's endpoints match the
+// response totals.
+func sampleMonths(months, step, maxPoints int) []int {
+	count := months/step + 1
+	if months%step != 0 {
+		count++
+	}
+	monthAt := func(i int) int {
+		return min(i*step, months)
+	}
+
+	if count <= maxPoints {
+		sampled := make([]int, count)
+		for i := range sampled {
+			sampled[i] = monthAt(i)
+		}
+		return sampled
+	}
+	if maxPoints == 1 {
+		return []int{months}
+	}
+
+	sampled := make([]int, maxPoints)
+	stride := float64(count-1) / float64(maxPoints-1)
+	for i := range sampled {
+		sampled[i] = monthAt(int(math.Round(float64(i) * stride)))
+	}
+	return sampled
+}
@@ -0,0 +1,67 @@
+package calculators
+
+import (
+	"finclamp-api/explain"
+	"finclamp-api/models"
+	"finclamp-api/units"
+	"finclamp-api/utils"
+	"sort"
+)
+
+const defaultSubscriptionCategory = "other"
+
+// Subscriptions totals recurring subscriptions on a common yearly basis and
+// breaks them down by category and billing cycle, most expensive first
+func Subscriptions(req models.SubscriptionRequest, t *explain.Trace) (models.SubscriptionResponse, error) {
+	response := models.SubscriptionResponse{
+		Subscriptions: make([]models.SubscriptionCost, 0, len(req.Subscriptions)),
+		ByCategory:    make(map[string]float64),
+		ByBilling:     make(map[string]float64),
+	}
+
+	total := 0.0
+	for _, sub := range req.Subscriptions {
+		category := sub.Category
+		if category == "" {
+			category = defaultSubscriptionCategory
+		}
+		perYear, err := units.PerYear(sub.Cost, sub.Billing, 0)
+		if err != nil {
+			return models.SubscriptionResponse{}, unitError(err)
+		}
+		yearly := t.Step("Yearly cost of "+sub.Name, "cost × billingsPerYear",
+			map[string]float64{"cost": sub.Cost, "billingsPerYear": units.Periods[sub.Billing]}, perYear)
+
+		total += yearly
+		response.ByCategory[category] += yearly
+		response.ByBilling[sub.Billing] += yearly
+		response.Subscriptions = append(response.Subscriptions, models.SubscriptionCost{
+			Name:     sub.Name,
+			Category: category,
+			Monthly:  yearly / 12,
+			Yearly:   yearly,
+		})
+	}
+
+	// Shares and rounding need the unrounded total, so they are applied last
+	for i := range response.Subscriptions {
+		sub := &response.Subscriptions[i]
+		sub.Share = utils.RoundToTwoDecimals(sub.Yearly / total * 100)
+		sub.Monthly = utils.RoundToTwoDecimals(sub.Monthly)
+		sub.Yearly = utils.RoundToTwoDecimals(sub.Yearly)
+	}
+	sort.SliceStable(response.Subscriptions, func(i, j int) bool {
+		return response.Subscriptions[i].Yearly > response.Subscriptions[j].Yearly
+	})
+	for name, amount := range response.ByCategory {
+		response.ByCategory[name] = utils.RoundToTwoDecimals(amount)
+	}
+	for name, amount := range response.ByBilling {
+		response.ByBilling[name] = utils.RoundToTwoDecimals(amount)
+	}
+
+	response.AverageMonthly = utils.RoundToTwoDecimals(total / 12 / float64(len(req.Subscriptions)))
+	response.Projection = projectCost("subscriptions", total, req.ProjectionOptions, t)
+	response.Explanation = t.Steps()
+	return response, nil
+}
@@ -0,0 +1,70 @@
+package calculators
+
+import (
+	"finclamp-api/explain"
+	"finclamp-api/models"
+	"finclamp-api/utils"
+	"math"
+)
+
+// serviceTips maps service quality to a customary tip percentage
+var serviceTips = map[string]float64{
+	"poor":        10,
+	"average":     15,
+	"good":        18,
+	"excellent":   20,
+	"outstanding": 25,
+}
+
+// Tip splits a bill and tip between people. With RoundUp each person's share
+// is rounded up to a whole amount and the difference goes to the tip.
+func Tip(req models.TipRequest, t *explain.Trace) (models.TipResponse, error) {
+	tipPercent := serviceTips["good"]
+	if quality, ok := serviceTips[req.ServiceQuality]; ok {
+		tipPercent = quality
+	}
+	if req.TipPercent != nil {
+		tipPercent = *req.TipPercent
+	}
+	people := req.People
+	if people == 0 {
+		people = 1
+	}
+
+	tip := t.Step("Tip amount", "bill × tipPercent / 100",
+		map[string]float64{"bill": req.BillAmount, "tipPercent": tipPercent}, req.BillAmount*tipPercent/100)
+	perPersonTotal := t.Step("Total per person", "(bill + tip) / people",
+		map[string]float64{"bill": req.BillAmount, "tip": tip, "people": float64(people)}, (req.BillAmount+tip)/float64(people))
+
+	if req.RoundUp {
+		perPersonTotal = t.Step("Round each share up", "ceil(perPersonTotal)",
+			map[string]float64{"perPersonTotal": perPersonTotal}, math.Ceil(perPersonTotal))
+		tip = perPersonTotal*float64(people) - req.BillAmount
+		tipPercent = tip / req.BillAmount * 100
+	}
+	total := req.BillAmount + tip
+
+	response := models.TipResponse{
+		TipPercent:     utils.RoundToTwoDecimals(tipPercent),
+		TipAmount:      utils.RoundToTwoDecimals(tip),
+		TotalAmount:    utils.RoundToTwoDecimals(total),
+		People:         people,
+		PerPersonBill:  utils.RoundToTwoDecimals(req.BillAmount / float64(people)),
+		PerPersonTip:   utils.RoundToTwoDecimals(tip / float64(people)),
+		PerPersonTotal: utils.RoundToTwoDecimals(perPersonTotal),
+	}
+
+	if req.Frequency != nil {
+		perYear, err := occurrencesPerYear(*req.Frequency)
+		if err != nil {
+			return models.TipResponse{}, err
+		}
+		yearly := t.Step("Yearly spend on this bill", "total × occurrencesPerYear",
+			map[string]float64{"total": total, "occurrencesPerYear": perYear}, total*perYear)
+		projection := projectCost("this bill", yearly, req.ProjectionOptions, t)
+		response.Projection = &projection
+	}
+
+	response.Explanation = t.Steps()
+	return response, nil
+}
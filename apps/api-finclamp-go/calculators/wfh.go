@@ -0,0 +1,85 @@
+package calculators
+
+import (
+	"finclamp-api/explain"
+	"finclamp-api/models"
+	"finclamp-api/registry"
+	"finclamp-api/units"
+	"finclamp-api/utils"
+	"fmt"
+)
+
+const defaultWorkDays = 5
+
+// WFHSavings estimates what working from home saves in commuting, food and
+// office costs, net of the extra costs of working at home
+func WFHSavings(req models.WFHSavingsRequest, t *explain.Trace) (models.WFHSavingsResponse, error) {
+	workDays := req.WorkDaysPerWeek
+	if workDays == 0 {
+		workDays = defaultWorkDays
+	}
+	if req.WFHDaysPerWeek > workDays {
+		return models.WFHSavingsResponse{}, &registry.InputError{Message: "Invalid work-from-home days",
+			Detail: fmt.Sprintf("wfhDaysPerWeek (%g) cannot exceed workDaysPerWeek (%g)", req.WFHDaysPerWeek, workDays)}
+	}
+
+	kmPerUnit, err := units.ToKilometres(1, req.DistanceUnit)
+	if err != nil {
+		return models.WFHSavingsResponse{}, unitError(err)
+	}
+	commutePerDay := req.DailyCommuteCosts
+	if req.Vehicle != nil && req.OneWayDistance > 0 {
+		perKm, err := fuelCostPerKm(*req.Vehicle, t)
+		if err != nil {
+			return models.WFHSavingsResponse{}, err
+		}
+		commutePerDay = t.Step("Commute cost per office day", "oneWayDistance × 2 × kmPerUnit × costPerKm + dailyCommuteCosts",
+			map[string]float64{"oneWayDistance": req.OneWayDistance, "kmPerUnit": kmPerUnit, "costPerKm": perKm, "dailyCommuteCosts": req.DailyCommuteCosts},
+			req.OneWayDistance*2*kmPerUnit*perKm+req.DailyCommuteCosts)
+	}
+
+	wfhDaysPerYear := req.WFHDaysPerWeek * units.Periods["week"]
+	commute := t.Step("Commute saved per year", "commutePerDay × wfhDaysPerYear",
+		map[string]float64{"commutePerDay": commutePerDay, "wfhDaysPerYear": wfhDaysPerYear}, commutePerDay*wfhDaysPerYear)
+	food := t.Step("Food saved per year", "dailyFoodCosts × wfhDaysPerYear",
+		map[string]float64{"dailyFoodCosts": req.DailyFoodCosts, "wfhDaysPerYear": wfhDaysPerYear}, req.DailyFoodCosts*wfhDaysPerYear)
+
+	officeYearly, err := yearlyRecurring(req.OfficeCosts, workDays)
+	if err != nil {
+		return models.WFHSavingsResponse{}, err
+	}
+	office := t.Step("Office costs saved per year", "officeCosts × wfhDays / workDays",
+		map[string]float64{"officeCosts": officeYearly, "wfhDays": req.WFHDaysPerWeek, "workDays": workDays},
+		officeYearly*req.WFHDaysPerWeek/workDays)
+
+	homeYearly, err := yearlyRecurring(req.HomeCosts, req.WFHDaysPerWeek)
+	if err != nil {
+		return models.WFHSavingsResponse{}, err
+	}
+	net := t.Step("Net savings per year", "commute + food + office − homeCosts",
+		map[string]float64{"commute": commute, "food": food, "office": office, "homeCosts": homeYearly},
+		commute+food+office-homeYearly)
+
+	response := models.WFHSavingsResponse{
+		SavedPerWFHDay:   utils.RoundToTwoDecimals(net / wfhDaysPerYear),
+		CommuteSavings:   utils.RoundToTwoDecimals(commute),
+		FoodSavings:      utils.RoundToTwoDecimals(food),
+		OfficeSavings:    utils.RoundToTwoDecimals(office),
+		HomeCosts:        utils.RoundToTwoDecimals(homeYearly),
+		NetYearlySavings: utils.RoundToTwoDecimals(net),
+	}
+	// Without net savings the setup cost is never paid back
+	if req.HomeOfficeSetup > 0 && net > 0 {
+		payback := utils.RoundToTwoDecimals(t.Step("Months to pay back the home office setup", "homeOfficeSetup / (net / 12)",
+			map[string]float64{"homeOfficeSetup": req.HomeOfficeSetup, "net": net}, req.HomeOfficeSetup/(net/12)))
+		response.PaybackMonths = &payback
+	} else if req.HomeOfficeSetup == 0 {
+		zero := 0.0
+		response.PaybackMonths = &zero
+	}
+
+	// The projection treats the savings as money that can be invested
+	response.Projection = projectCost("savings", net, req.ProjectionOptions, t)
+	response.Explanation = t.Steps()
+	return response, nil
+}
@@ -10,6 +10,26 @@ type Config struct {
 	Environment string
 	AppName     string
 	Version     string
+
+	// MarketDataFile overrides the embedded index return dataset when set
+	MarketDataFile string
+
+	// ExchangeRatesFile overrides the embedded exchange-rate table when set
+	ExchangeRatesFile string
+
+	// CostInflationIndexFile overrides the embedded Cost Inflation Index table when set
+	CostInflationIndexFile string
+
+	// DefaultCurrency labels amounts when a request does not name a currency
+	DefaultCurrency string
+
+	// AuthSecret verifies the HS256 bearer tokens that identify signed-in users;
+	// without it no user can sign in
+	AuthSecret string
+
+	// HistoryFile persists calculation history when set; otherwise history is
+	// kept in memory only
+	HistoryFile string
 }
 
 var AppConfig Config
@@ -22,9 +42,20 @@ func LoadConfig() {
 		Environment: getEnv("ENVIRONMENT", "development"),
 		AppName:     "FinClamp API",
 		Version:     "1.0.0",
+
+		MarketDataFile:         getEnv("MARKET_DATA_FILE", ""),
+		ExchangeRatesFile:      getEnv("EXCHANGE_RATES_FILE", ""),
+		CostInflationIndexFile: getEnv("COST_INFLATION_INDEX_FILE", ""),
+		DefaultCurrency:        getEnv("DEFAULT_CURRENCY", "INR"),
+		AuthSecret:             getEnv("AUTH_SECRET", ""),
+		HistoryFile:            getEnv("HISTORY_FILE", ""),
 	}
 	
-	log.Printf("Configuration loaded: %+v", AppConfig)
+	logged := AppConfig
+	if logged.AuthSecret != "" {
+		logged.AuthSecret = "[redacted]"
+	}
+	log.Printf("Configuration loaded: %+v", logged)
 }
 
 func getEnv(key, defaultValue string) string {
@@ -0,0 +1,177 @@
+package currency
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed exchange_rates.csv
+var embeddedRates []byte
+
+// Currency describes a currency and its exchange rate against USD
+type Currency struct {
+	Code     string
+	Name     string
+	Symbol   string
+	Decimals int
+	Locale   string
+	PerUSD   float64
+	AsOf     time.Time
+}
+
+var (
+	mu         sync.RWMutex
+	currencies map[string]Currency
+)
+
+// Load parses the exchange-rate table. When path is empty the table embedded in
+// the binary is used; otherwise the file at path replaces it.
+func Load(path string) error {
+	var reader io.Reader
+	source := "embedded rates"
+
+	if path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("open exchange rates file: %w", err)
+		}
+		defer file.Close()
+		reader = file
+		source = path
+	} else {
+		reader = bytes.NewReader(embeddedRates)
+	}
+
+	parsed, err := parse(reader)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", source, err)
+	}
+
+	mu.Lock()
+	currencies = parsed
+	mu.Unlock()
+
+	log.Printf("Exchange rates loaded from %s: %d currencies", source, len(parsed))
+	return nil
+}
+
+// Lookup returns the currency for an ISO 4217 code
+func Lookup(code string) (Currency, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	cur, ok := currencies[strings.ToUpper(code)]
+	return cur, ok
+}
+
+// All returns every loaded currency sorted by code
+func All() []Currency {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	all := make([]Currency, 0, len(currencies))
+	for _, cur := range currencies {
+		all = append(all, cur)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Code < all[j].Code })
+	return all
+}
+
+// Codes returns every loaded currency code sorted alphabetically
+func Codes() []string {
+	all := All()
+	codes := make([]string, len(all))
+	for i, cur := range all {
+		codes[i] = cur.Code
+	}
+	return codes
+}
+
+// Rate returns how many units of to one unit of from buys
+func Rate(from, to Currency) float64 {
+	return to.PerUSD / from.PerUSD
+}
+
+// Round rounds an amount to the currency's minor unit
+func Round(amount float64, cur Currency) float64 {
+	scale := math.Pow(10, float64(cur.Decimals))
+	return math.Round(amount*scale) / scale
+}
+
+func parse(r io.Reader) (map[string]Currency, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"code", "name", "symbol", "decimals", "locale", "per_usd", "as_of"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+
+	parsed := make(map[string]Currency)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		code := strings.ToUpper(strings.TrimSpace(record[columns["code"]]))
+		if len(code) != 3 {
+			return nil, fmt.Errorf("invalid currency code %q", code)
+		}
+		decimals, err := strconv.Atoi(record[columns["decimals"]])
+		if err != nil || decimals < 0 {
+			return nil, fmt.Errorf("%s: invalid decimals %q", code, record[columns["decimals"]])
+		}
+		perUSD, err := strconv.ParseFloat(record[columns["per_usd"]], 64)
+		if err != nil || perUSD <= 0 {
+			return nil, fmt.Errorf("%s: invalid rate %q", code, record[columns["per_usd"]])
+		}
+		asOf, err := time.Parse("2006-01-02", record[columns["as_of"]])
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid as-of date %q", code, record[columns["as_of"]])
+		}
+		locale := record[columns["locale"]]
+		if _, ok := locales[locale]; !ok {
+			return nil, fmt.Errorf("%s: unsupported locale %q", code, locale)
+		}
+
+		parsed[code] = Currency{
+			Code:     code,
+			Name:     record[columns["name"]],
+			Symbol:   record[columns["symbol"]],
+			Decimals: decimals,
+			Locale:   locale,
+			PerUSD:   perUSD,
+			AsOf:     asOf,
+		}
+	}
+
+	if _, ok := parsed["USD"]; !ok {
+		return nil, fmt.Errorf("USD must be present as the base currency")
+	}
+	return parsed, nil
+}
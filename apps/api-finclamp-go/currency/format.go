@@ -0,0 +1,113 @@
+package currency
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// locale holds the number formatting rules for a locale. Groups lists digit
+// group sizes from the right; the last size repeats, so Indian grouping is
+// {3, 2} (12,34,567) and Western grouping is {3} (1,234,567).
+type locale struct {
+	groupSeparator   string
+	decimalSeparator string
+	groups           []int
+	symbolAfter      bool
+}
+
+var locales = map[string]locale{
+	"en-US": {groupSeparator: ",", decimalSeparator: ".", groups: []int{3}},
+	"en-GB": {groupSeparator: ",", decimalSeparator: ".", groups: []int{3}},
+	"en-IN": {groupSeparator: ",", decimalSeparator: ".", groups: []int{3, 2}},
+	"en-AE": {groupSeparator: ",", decimalSeparator: ".", groups: []int{3}},
+	"en-SG": {groupSeparator: ",", decimalSeparator: ".", groups: []int{3}},
+	"en-AU": {groupSeparator: ",", decimalSeparator: ".", groups: []int{3}},
+	"en-CA": {groupSeparator: ",", decimalSeparator: ".", groups: []int{3}},
+	"ja-JP": {groupSeparator: ",", decimalSeparator: ".", groups: []int{3}},
+	"zh-CN": {groupSeparator: ",", decimalSeparator: ".", groups: []int{3}},
+	"ko-KR": {groupSeparator: ",", decimalSeparator: ".", groups: []int{3}},
+	"de-DE": {groupSeparator: ".", decimalSeparator: ",", groups: []int{3}, symbolAfter: true},
+	"de-CH": {groupSeparator: "'", decimalSeparator: ".", groups: []int{3}},
+	"fr-FR": {groupSeparator: " ", decimalSeparator: ",", groups: []int{3}, symbolAfter: true},
+}
+
+// Locales returns the supported locale names sorted alphabetically
+func Locales() []string {
+	names := make([]string, 0, len(locales))
+	for name := range locales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SupportsLocale reports whether a locale name has formatting rules
+func SupportsLocale(name string) bool {
+	_, ok := locales[name]
+	return ok
+}
+
+// Format renders an amount with the currency's symbol and minor units using a
+// locale's grouping rules, e.g. "₹12,34,567.00" for en-IN. An empty or unknown
+// locale falls back to the currency's default locale.
+func Format(amount float64, cur Currency, localeName string) string {
+	rules, ok := locales[localeName]
+	if !ok {
+		rules = locales[cur.Locale]
+	}
+
+	rounded := Round(math.Abs(amount), cur)
+	digits := strconv.FormatFloat(rounded, 'f', cur.Decimals, 64)
+	integer, fraction, _ := strings.Cut(digits, ".")
+
+	number := group(integer, rules)
+	if fraction != "" {
+		number += rules.decimalSeparator + fraction
+	}
+
+	var formatted string
+	if rules.symbolAfter {
+		formatted = number + " " + cur.Symbol
+	} else if symbolNeedsSpace(cur.Symbol) {
+		formatted = cur.Symbol + " " + number
+	} else {
+		formatted = cur.Symbol + number
+	}
+
+	if amount < 0 && rounded != 0 {
+		return "-" + formatted
+	}
+	return formatted
+}
+
+// group inserts group separators into a string of integer digits
+func group(integer string, rules locale) string {
+	var parts []string
+	for i := 0; len(integer) > 0; i++ {
+		size := rules.groups[len(rules.groups)-1]
+		if i < len(rules.groups) {
+			size = rules.groups[i]
+		}
+		if size >= len(integer) {
+			parts = append(parts, integer)
+			break
+		}
+		parts = append(parts, integer[len(integer)-size:])
+		integer = integer[:len(integer)-size]
+	}
+
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return strings.Join(parts, rules.groupSeparator)
+}
+
+// symbolNeedsSpace reports whether a prefix symbol ends in a letter, like "CHF"
+// or "AED", and so needs a space before the number
+func symbolNeedsSpace(symbol string) bool {
+	runes := []rune(symbol)
+	return len(runes) > 0 && unicode.IsLetter(runes[len(runes)-1])
+}
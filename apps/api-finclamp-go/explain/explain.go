@@ -0,0 +1,43 @@
+package explain
+
+import "finclamp-api/models"
+
+// Trace records the derivation steps of a calculation. A nil *Trace is valid and
+// records nothing, so calculators can always call Step without checking whether
+// an explanation was requested.
+type Trace struct {
+	steps []models.ExplanationStep
+}
+
+// New returns a Trace when enabled is true and nil otherwise
+func New(enabled bool) *Trace {
+	if !enabled {
+		return nil
+	}
+	return &Trace{}
+}
+
+// Step records a derivation step and returns result unchanged. Calculators
+// assign the return value, so the explained number is the computed number.
+func (t *Trace) Step(description, formula string, values map[string]float64, result float64) float64 {
+	if t == nil {
+		return result
+	}
+
+	t.steps = append(t.steps, models.ExplanationStep{
+		Step:        len(t.steps) + 1,
+		Description: description,
+		Formula:     formula,
+		Values:      values,
+		Result:      result,
+	})
+	return result
+}
+
+// Steps returns the recorded steps, or nil when tracing is disabled
+func (t *Trace) Steps() []models.ExplanationStep {
+	if t == nil {
+		return nil
+	}
+	return t.steps
+}
@@ -1,11 +1,18 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
 	"finclamp-api/config"
+	"finclamp-api/currency"
+	"finclamp-api/marketdata"
 	"finclamp-api/models"
+	"finclamp-api/phrase"
+	"finclamp-api/registry"
 	"finclamp-api/utils"
-	"math"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -22,11 +29,23 @@ func GetServiceInfo(c *gin.Context) {
 		Endpoints: []string{
 			"GET /api/v1/ - Service info",
 			"GET /api/v1/health - Health check",
-			"POST /api/v1/calculate/loan - Loan calculations",
-			"POST /api/v1/calculate/savings - Savings calculations",
-			"POST /api/v1/calculate/investment - Investment calculations",
+			"GET /api/v1/calculators - Calculator discovery with JSON Schemas (?group= to filter)",
+			"GET /api/v1/calculators/:name - Single calculator with JSON Schemas",
+			"GET /api/v1/currency/rates - Offline exchange rates",
+			"POST /api/v1/calculate/parse - Natural-language calculation query",
+			"GET /api/v1/history - Signed-in user's calculation history (?calculator=, ?from=, ?to=)",
+			"GET /api/v1/history/:id - One history entry",
+			"DELETE /api/v1/history/:id - Delete a history entry",
+			"POST /api/v1/history/compare - Compare history entries field by field",
 		},
 	}
+	dataset := marketdata.Source()
+	info.MarketData = models.DataSource{Source: dataset.Source, Illustrative: dataset.Illustrative}
+
+	for _, calc := range registry.All() {
+		meta := calc.Meta()
+		info.Endpoints = append(info.Endpoints, "POST "+registry.Path(meta.Name)+" - "+meta.Description)
+	}
 
 	utils.SendSuccessResponse(c, info, "Service information retrieved successfully")
 }
@@ -46,119 +65,118 @@ func HealthCheck(c *gin.Context) {
 	utils.SendSuccessResponse(c, health, "Service is healthy")
 }
 
-// CalculateLoan handles loan calculation requests
-func CalculateLoan(c *gin.Context) {
-	var req models.LoanCalculationRequest
-	
-	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
-		return
-	}
+// GetExchangeRates returns the offline exchange-rate table and supported locales
+func GetExchangeRates(c *gin.Context) {
+	currencies := currency.All()
 
-	// Set default type if not provided
-	if req.Type == "" {
-		req.Type = "monthly"
+	response := models.ExchangeRatesResponse{
+		Base:    "USD",
+		Rates:   make([]models.ExchangeRate, 0, len(currencies)),
+		Locales: currency.Locales(),
 	}
-
-	// Calculate loan
-	monthlyRate := req.Rate / 100 / 12
-	numPayments := float64(req.Term * 12)
-	
-	monthlyPayment := req.Principal * (monthlyRate * math.Pow(1+monthlyRate, numPayments)) / 
-		(math.Pow(1+monthlyRate, numPayments) - 1)
-	
-	totalAmount := monthlyPayment * numPayments
-	totalInterest := totalAmount - req.Principal
-
-	response := models.LoanCalculationResponse{
-		MonthlyPayment: utils.RoundToTwoDecimals(monthlyPayment),
-		TotalAmount:    utils.RoundToTwoDecimals(totalAmount),
-		TotalInterest:  utils.RoundToTwoDecimals(totalInterest),
-		NumPayments:    int(numPayments),
+	for _, cur := range currencies {
+		response.Rates = append(response.Rates, models.ExchangeRate{
+			Code:     cur.Code,
+			Name:     cur.Name,
+			Symbol:   cur.Symbol,
+			Decimals: cur.Decimals,
+			Locale:   cur.Locale,
+			PerUSD:   cur.PerUSD,
+			AsOf:     cur.AsOf.Format("2006-01-02"),
+		})
 	}
 
-	utils.SendSuccessResponse(c, response, "Loan calculation completed successfully")
+	utils.SendSuccessResponse(c, response, "Exchange rates retrieved successfully")
 }
 
-// CalculateSavings handles savings calculation requests
-func CalculateSavings(c *gin.Context) {
-	var req models.SavingsCalculationRequest
-	
-	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
-		return
-	}
+// ListCalculators returns every registered calculator with its JSON Schemas,
+// optionally limited to one group with ?group=
+func ListCalculators(c *gin.Context) {
+	calculators := registry.All()
+	group := c.Query("group")
 
-	// Calculate savings
-	monthlyRate := req.Rate / 100 / 12
-	numMonths := float64(req.Term * 12)
-	
-	// Future value with compound interest
-	futureValue := req.Principal * math.Pow(1+monthlyRate, numMonths)
-	
-	// Future value of monthly contributions (annuity)
-	var contributionValue float64
-	if req.MonthlyContribution > 0 && monthlyRate > 0 {
-		contributionValue = req.MonthlyContribution * 
-			((math.Pow(1+monthlyRate, numMonths) - 1) / monthlyRate)
+	response := models.CalculatorsResponse{
+		Calculators: make([]models.CalculatorInfo, 0, len(calculators)),
+	}
+	for _, calc := range calculators {
+		if group != "" && calc.Meta().Group != group {
+			continue
+		}
+		response.Calculators = append(response.Calculators, calc.Info())
 	}
+	response.Total = len(response.Calculators)
 
-	totalValue := futureValue + contributionValue
-	totalContributions := req.Principal + (req.MonthlyContribution * numMonths)
-	totalInterest := totalValue - totalContributions
+	utils.SendSuccessResponse(c, response, "Calculators retrieved successfully")
+}
 
-	response := models.SavingsCalculationResponse{
-		FinalAmount:        utils.RoundToTwoDecimals(totalValue),
-		TotalContributions: utils.RoundToTwoDecimals(totalContributions),
-		TotalInterest:      utils.RoundToTwoDecimals(totalInterest),
-		NumMonths:          int(numMonths),
+// GetCalculator returns a single registered calculator with its JSON Schemas
+func GetCalculator(c *gin.Context) {
+	calc, ok := registry.Lookup(c.Param("name"))
+	if !ok {
+		var names []string
+		for _, registered := range registry.All() {
+			names = append(names, registered.Meta().Name)
+		}
+		utils.SendErrorResponse(c, http.StatusNotFound, "Calculator not found", "Available calculators: "+strings.Join(names, ", "))
+		return
 	}
 
-	utils.SendSuccessResponse(c, response, "Savings calculation completed successfully")
+	utils.SendSuccessResponse(c, calc.Info(), "Calculator retrieved successfully")
 }
 
-// CalculateInvestment handles investment calculation requests
-func CalculateInvestment(c *gin.Context) {
-	var req models.InvestmentCalculationRequest
-	
+// ParseQuery turns a natural-language query into a request for one of the
+// calculators and runs it, or asks for whatever the query left out
+func ParseQuery(c *gin.Context) {
+	var req models.ParseQueryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
 		return
 	}
 
-	// Set default risk level if not provided
-	if req.RiskLevel == "" {
-		req.RiskLevel = "medium"
+	query, err := phrase.Parse(req.Query)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Could not understand query", "Try phrasing it like: "+strings.Join(phrase.Examples, "; "))
+		return
 	}
 
-	// Calculate investment
-	annualReturn := req.ExpectedReturn / 100
-	futureValue := req.Amount * math.Pow(1+annualReturn, float64(req.Term))
-	totalGain := futureValue - req.Amount
-	annualizedReturn := math.Pow(futureValue/req.Amount, 1/float64(req.Term)) - 1
-
-	// Risk adjustment factors
-	riskFactors := map[string]float64{
-		"low":    0.8,
-		"medium": 1.0,
-		"high":   1.2,
+	response := models.ParseQueryResponse{
+		Query:         req.Query,
+		Calculator:    query.Calculator,
+		Request:       query.Request,
+		Assumptions:   query.Assumptions,
+		Missing:       query.Missing,
+		Unreadable:    query.Unreadable,
+		Clarification: query.Clarification,
 	}
-
-	riskFactor, exists := riskFactors[req.RiskLevel]
-	if !exists {
-		riskFactor = 1.0
+	if !query.Complete() {
+		utils.SendSuccessResponse(c, response, "More information needed")
+		return
 	}
 
-	adjustedGain := totalGain * riskFactor
-	adjustedFutureValue := req.Amount + adjustedGain
+	calc, ok := registry.Lookup(query.Calculator)
+	if !ok {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Calculation failed", "calculator "+query.Calculator+" is not registered")
+		return
+	}
+	body, err := json.Marshal(query.Request)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Calculation failed", err.Error())
+		return
+	}
 
-	response := models.InvestmentCalculationResponse{
-		ProjectedValue:   utils.RoundToTwoDecimals(futureValue),
-		AdjustedValue:    utils.RoundToTwoDecimals(adjustedFutureValue),
-		TotalGain:        utils.RoundToTwoDecimals(totalGain),
-		AdjustedGain:     utils.RoundToTwoDecimals(adjustedGain),
-		AnnualizedReturn: utils.RoundToTwoDecimals(annualizedReturn * 100), // Convert to percentage
+	enabled, _ := strconv.ParseBool(c.Query("explain"))
+	result, err := calc.Run(body, enabled)
+	var inputErr *registry.InputError
+	if errors.As(err, &inputErr) {
+		utils.SendErrorResponse(c, http.StatusBadRequest, inputErr.Message, inputErr.Detail)
+		return
+	}
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Calculation failed", err.Error())
+		return
 	}
 
-	utils.SendSuccessResponse(c, response, "Investment calculation completed successfully")
+	registry.RecordHistory(c, calc.Meta(), query.Request, result)
+	response.Result = result
+	utils.SendSuccessResponse(c, response, calc.Meta().Title+" completed successfully")
 }
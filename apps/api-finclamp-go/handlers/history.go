@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"finclamp-api/history"
+	"finclamp-api/models"
+	"finclamp-api/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 200
+)
+
+// ListHistory returns the signed-in user's calculation history, newest first.
+// It can be filtered with ?calculator=, ?from= and ?to= (dates as YYYY-MM-DD or
+// RFC 3339; to is exclusive) and paged with ?limit= and ?offset=.
+func ListHistory(c *gin.Context) {
+	userID := c.GetString("UserID")
+
+	filter := history.Filter{Calculator: c.Query("calculator")}
+	var err error
+	if filter.From, err = parseHistoryTime(c.Query("from")); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid from date", err.Error())
+		return
+	}
+	if filter.To, err = parseHistoryTime(c.Query("to")); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid to date", err.Error())
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultHistoryLimit)))
+	if err != nil || limit < 1 || limit > maxHistoryLimit {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid limit", "limit must be between 1 and "+strconv.Itoa(maxHistoryLimit))
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid offset", "offset must be zero or more")
+		return
+	}
+
+	entries := history.List(userID, filter)
+	response := models.HistoryListResponse{
+		Entries:     []models.HistoryEntry{},
+		Total:       len(entries),
+		Limit:       limit,
+		Offset:      offset,
+		Calculators: history.Calculators(userID),
+	}
+	if offset < len(entries) {
+		response.Entries = entries[offset:min(offset+limit, len(entries))]
+	}
+
+	utils.SendSuccessResponse(c, response, "History retrieved successfully")
+}
+
+// GetHistoryEntry returns one entry from the signed-in user's history
+func GetHistoryEntry(c *gin.Context) {
+	entry, err := history.Get(c.GetString("UserID"), c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusNotFound, "History entry not found", err.Error())
+		return
+	}
+
+	utils.SendSuccessResponse(c, entry, "History entry retrieved successfully")
+}
+
+// DeleteHistoryEntry removes one entry from the signed-in user's history
+func DeleteHistoryEntry(c *gin.Context) {
+	err := history.Delete(c.GetString("UserID"), c.Param("id"))
+	if errors.Is(err, history.ErrNotFound) {
+		utils.SendErrorResponse(c, http.StatusNotFound, "History entry not found", err.Error())
+		return
+	}
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to delete history entry", err.Error())
+		return
+	}
+
+	utils.SendSuccessResponse(c, nil, "History entry deleted successfully")
+}
+
+// CompareHistory compares two or more of the signed-in user's history entries
+// field by field
+func CompareHistory(c *gin.Context) {
+	var req models.HistoryCompareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	userID := c.GetString("UserID")
+	entries := make([]models.HistoryEntry, 0, len(req.IDs))
+	for _, id := range req.IDs {
+		entry, err := history.Get(userID, id)
+		if err != nil {
+			utils.SendErrorResponse(c, http.StatusNotFound, "History entry not found", "no entry with id "+id)
+			return
+		}
+		entries = append(entries, entry)
+	}
+
+	utils.SendSuccessResponse(c, history.Compare(entries), "History compared successfully")
+}
+
+func parseHistoryTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, errors.New("use YYYY-MM-DD or RFC 3339, e.g. 2025-01-31 or 2025-01-31T18:30:00Z")
+	}
+	return t, nil
+}
@@ -0,0 +1,93 @@
+package history
+
+import (
+	"finclamp-api/models"
+	"finclamp-api/utils"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// skippedFields are derived detail that would swamp a field-level comparison
+var skippedFields = map[string]bool{"explanation": true, "series": true}
+
+// Compare lines entries up side by side and reports every request and response
+// field whose value differs between them. Fields are addressed by path, e.g.
+// "request.rate" or "response.options[0].totalTax".
+func Compare(entries []models.HistoryEntry) models.HistoryComparison {
+	fields := make(map[string][]interface{})
+	for i, entry := range entries {
+		visit := func(path string, value interface{}) {
+			if _, ok := fields[path]; !ok {
+				fields[path] = make([]interface{}, len(entries))
+			}
+			fields[path][i] = value
+		}
+		flatten(entry.Request, "request", visit)
+		flatten(entry.Response, "response", visit)
+	}
+
+	paths := make([]string, 0, len(fields))
+	for path := range fields {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	comparison := models.HistoryComparison{
+		Entries:        entries,
+		Differences:    []models.FieldDiff{},
+		SameCalculator: true,
+	}
+	for _, entry := range entries[1:] {
+		if entry.Calculator != entries[0].Calculator {
+			comparison.SameCalculator = false
+		}
+	}
+
+	for _, path := range paths {
+		values := fields[path]
+		if allEqual(values) {
+			comparison.UnchangedFields++
+			continue
+		}
+
+		diff := models.FieldDiff{Path: path, Values: values}
+		first, firstOK := values[0].(float64)
+		last, lastOK := values[len(values)-1].(float64)
+		if firstOK && lastOK {
+			delta := utils.RoundToTwoDecimals(last - first)
+			diff.Delta = &delta
+		}
+		comparison.Differences = append(comparison.Differences, diff)
+	}
+
+	return comparison
+}
+
+// flatten visits every leaf value of decoded JSON with its path
+func flatten(value interface{}, path string, visit func(string, interface{})) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if skippedFields[key] {
+				continue
+			}
+			flatten(child, path+"."+key, visit)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flatten(child, path+"["+strconv.Itoa(i)+"]", visit)
+		}
+	default:
+		visit(path, v)
+	}
+}
+
+func allEqual(values []interface{}) bool {
+	for _, value := range values[1:] {
+		if !reflect.DeepEqual(value, values[0]) {
+			return false
+		}
+	}
+	return true
+}
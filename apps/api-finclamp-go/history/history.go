@@ -0,0 +1,330 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"finclamp-api/models"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxEntriesPerUser caps each user's history; the oldest entries are dropped first
+const MaxEntriesPerUser = 1000
+
+// minCompactLines is the number of lines below which the history file is not
+// compacted
+const minCompactLines = 1024
+
+// ErrNotFound is returned for an entry that does not exist or belongs to another user
+var ErrNotFound = errors.New("history entry not found")
+
+// record is an entry as stored, with the user it belongs to
+type record struct {
+	UserID  string              `json:"userId"`
+	Deleted bool                `json:"deleted,omitempty"`
+	Entry   models.HistoryEntry `json:"entry"`
+}
+
+var (
+	mu     sync.RWMutex
+	byUser = make(map[string][]models.HistoryEntry)
+	file   *os.File
+	path   string
+	// lines counts the records in the history file, including those of
+	// deleted and trimmed entries, which compacting the file drops
+	lines     int
+	compactAt int
+)
+
+// Open loads history persisted at path and appends new entries to it. When path
+// is empty history is kept in memory only and is lost on restart.
+func Open(historyPath string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if file != nil {
+		file.Close()
+		file = nil
+	}
+	byUser = make(map[string][]models.HistoryEntry)
+	path = historyPath
+	if path == "" {
+		log.Println("Calculation history kept in memory")
+		return nil
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("open history file: %w", err)
+	}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	lines = 0
+	for scanner.Scan() {
+		lines++
+		var rec record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			f.Close()
+			return fmt.Errorf("parse %s line %d: %w", path, lines, err)
+		}
+		if rec.Deleted {
+			remove(rec.UserID, rec.Entry.ID)
+			continue
+		}
+		appendEntry(rec.UserID, rec.Entry)
+	}
+	if err := scanner.Err(); err != nil {
+		f.Close()
+		return fmt.Errorf("read %s: %w", path, err)
+	}
+
+	file = f
+	count := liveEntries()
+	if lines > count {
+		if err := compact(); err != nil {
+			return err
+		}
+	}
+	compactAt = max(2*count, minCompactLines)
+	log.Printf("Calculation history loaded from %s: %d entries", path, count)
+	return nil
+}
+
+// Record stores a calculation a user ran. Request and response are stored as
+// their JSON representation.
+func Record(userID, calculator, version string, request, response interface{}) (models.HistoryEntry, error) {
+	entry := models.HistoryEntry{
+		ID:         uuid.New().String(),
+		Calculator: calculator,
+		Version:    version,
+		CreatedAt:  time.Now().UTC(),
+	}
+	var err error
+	if entry.Request, err = toMap(request); err != nil {
+		return models.HistoryEntry{}, err
+	}
+	if entry.Response, err = toMap(response); err != nil {
+		return models.HistoryEntry{}, err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if err := persist(record{UserID: userID, Entry: entry}); err != nil {
+		return models.HistoryEntry{}, err
+	}
+	appendEntry(userID, entry)
+	compactIfGrown()
+	return entry, nil
+}
+
+// Filter narrows a history listing. Zero values match everything.
+type Filter struct {
+	Calculator string
+	From       time.Time
+	To         time.Time
+}
+
+// List returns a user's entries matching filter, newest first
+func List(userID string, filter Filter) []models.HistoryEntry {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	entries := byUser[userID]
+	matched := make([]models.HistoryEntry, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if filter.Calculator != "" && entry.Calculator != filter.Calculator {
+			continue
+		}
+		if !filter.From.IsZero() && entry.CreatedAt.Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && !entry.CreatedAt.Before(filter.To) {
+			continue
+		}
+		matched = append(matched, entry)
+	}
+	return matched
+}
+
+// Get returns one of a user's entries
+func Get(userID, id string) (models.HistoryEntry, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for _, entry := range byUser[userID] {
+		if entry.ID == id {
+			return entry, nil
+		}
+	}
+	return models.HistoryEntry{}, ErrNotFound
+}
+
+// Delete removes one of a user's entries
+func Delete(userID, id string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if !remove(userID, id) {
+		return ErrNotFound
+	}
+	if err := persist(record{UserID: userID, Deleted: true, Entry: models.HistoryEntry{ID: id}}); err != nil {
+		return err
+	}
+	compactIfGrown()
+	return nil
+}
+
+// Calculators returns the calculators a user has history for, sorted by name
+func Calculators(userID string) []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	seen := make(map[string]bool)
+	for _, entry := range byUser[userID] {
+		seen[entry.Calculator] = true
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// appendEntry adds an entry and drops the user's oldest beyond the cap. The
+// caller holds mu.
+func appendEntry(userID string, entry models.HistoryEntry) {
+	entries := append(byUser[userID], entry)
+	if len(entries) > MaxEntriesPerUser {
+		entries = entries[len(entries)-MaxEntriesPerUser:]
+	}
+	byUser[userID] = entries
+}
+
+// remove deletes an entry and reports whether it existed. The caller holds mu.
+func remove(userID, id string) bool {
+	entries := byUser[userID]
+	for i, entry := range entries {
+		if entry.ID == id {
+			byUser[userID] = append(entries[:i:i], entries[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// persist appends a record to the history file, if there is one. The caller
+// holds mu.
+func persist(rec record) error {
+	if file == nil {
+		return nil
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write history file: %w", err)
+	}
+	lines++
+	return nil
+}
+
+// compactIfGrown drops deleted and trimmed entries from the history file once
+// it has doubled, so rewriting it stays amortized O(1) per record. A failed
+// rewrite leaves the file as it was, so it is logged rather than returned. The
+// caller holds mu and has applied every persisted record.
+func compactIfGrown() {
+	if file == nil || lines < compactAt {
+		return
+	}
+	if err := compact(); err != nil {
+		log.Printf("Failed to compact history: %v", err)
+	}
+	compactAt = max(2*lines, minCompactLines)
+}
+
+// compact rewrites the history file with only the entries still held, replacing
+// it in one rename so a crash leaves either the old file or the new one. The
+// caller holds mu.
+func compact() error {
+	users := make([]string, 0, len(byUser))
+	for userID := range byUser {
+		users = append(users, userID)
+	}
+	sort.Strings(users)
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("compact history file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	written := 0
+	for _, userID := range users {
+		for _, entry := range byUser[userID] {
+			line, err := json.Marshal(record{UserID: userID, Entry: entry})
+			if err != nil {
+				tmp.Close()
+				return err
+			}
+			w.Write(append(line, '\n'))
+			written++
+		}
+	}
+	err = w.Flush()
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		return fmt.Errorf("compact history file: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("open history file: %w", err)
+	}
+	file.Close()
+	file = f
+	lines = written
+	return nil
+}
+
+// liveEntries returns the number of entries held. The caller holds mu.
+func liveEntries() int {
+	count := 0
+	for _, entries := range byUser {
+		count += len(entries)
+	}
+	return count
+}
+
+func toMap(v interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
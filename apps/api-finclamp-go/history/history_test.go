@@ -0,0 +1,76 @@
+package history
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func fileLines(t *testing.T, path string) int {
+	t.Helper()
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytes.Count(raw, []byte("\n"))
+}
+
+func TestHistoryFileDropsTrimmedAndDeletedEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	if err := Open(path); err != nil {
+		t.Fatal(err)
+	}
+	defer Open("")
+
+	// Past the cap, so the oldest entries are trimmed, and far enough past it
+	// for the file to double and be compacted while running
+	records := 3*MaxEntriesPerUser + 10
+	for i := 0; i < records; i++ {
+		if _, err := Record("ada", "tip", "v1", map[string]int{"bill": i}, map[string]int{"tip": i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := Record("bob", "tip", "v1", map[string]int{"bill": 1}, map[string]int{"tip": 1}); err != nil {
+		t.Fatal(err)
+	}
+	kept := List("ada", Filter{})
+	if err := Delete("ada", kept[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := fileLines(t, path); got >= 2*minCompactLines+MaxEntriesPerUser {
+		t.Fatalf("history file has %d lines after compacting while running", got)
+	}
+
+	// Reopening drops whatever is left over
+	if err := Open(path); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fileLines(t, path), MaxEntriesPerUser; got != want {
+		t.Fatalf("history file has %d lines after reopening, want %d", got, want)
+	}
+	entries := List("ada", Filter{})
+	if len(entries) != MaxEntriesPerUser-1 {
+		t.Fatalf("%d entries for ada, want %d", len(entries), MaxEntriesPerUser-1)
+	}
+	if entries[0].ID != kept[1].ID || entries[len(entries)-1].ID != kept[len(kept)-1].ID {
+		t.Fatal("reopened history does not hold the newest entries in order")
+	}
+	if _, err := Get("ada", kept[0].ID); err != ErrNotFound {
+		t.Fatalf("deleted entry: error %v, want %v", err, ErrNotFound)
+	}
+	if got := len(List("bob", Filter{})); got != 1 {
+		t.Fatalf("%d entries for bob, want 1", got)
+	}
+
+	// Records still reach the file after it has been replaced
+	if _, err := Record("bob", "tip", "v1", map[string]int{"bill": 2}, map[string]int{"tip": 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Open(path); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(List("bob", Filter{})); got != 2 {
+		t.Fatalf("%d entries for bob after reopening, want 2", got)
+	}
+}
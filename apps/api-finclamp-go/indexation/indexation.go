@@ -0,0 +1,130 @@
+package indexation
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+//go:embed cost_inflation_index.csv
+var embeddedIndex []byte
+
+var (
+	mu      sync.RWMutex
+	indices map[string]float64
+	first   string
+	last    string
+)
+
+// Load parses the Cost Inflation Index table. When path is empty the table
+// embedded in the binary is used; otherwise the file at path replaces it.
+func Load(path string) error {
+	var reader io.Reader
+	source := "embedded index"
+
+	if path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("open cost inflation index file: %w", err)
+		}
+		defer file.Close()
+		reader = file
+		source = path
+	} else {
+		reader = bytes.NewReader(embeddedIndex)
+	}
+
+	parsed, earliest, latest, err := parse(reader)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", source, err)
+	}
+
+	mu.Lock()
+	indices, first, last = parsed, earliest, latest
+	mu.Unlock()
+
+	log.Printf("Cost inflation index loaded from %s: %s to %s", source, earliest, latest)
+	return nil
+}
+
+// Index returns the Cost Inflation Index for a financial year such as "2024-25"
+func Index(financialYear string) (float64, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	index, ok := indices[financialYear]
+	return index, ok
+}
+
+// Range returns the first and last financial years in the table
+func Range() (string, string) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return first, last
+}
+
+// FinancialYear returns the Indian financial year (April to March) containing
+// date, formatted like "2024-25"
+func FinancialYear(date time.Time) string {
+	start := date.Year()
+	if date.Month() < time.April {
+		start--
+	}
+	return fmt.Sprintf("%d-%02d", start, (start+1)%100)
+}
+
+func parse(r io.Reader) (map[string]float64, string, string, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	if _, err := reader.Read(); err != nil {
+		return nil, "", "", fmt.Errorf("read header: %w", err)
+	}
+
+	parsed := make(map[string]float64)
+	var earliest, latest string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, "", "", err
+		}
+
+		if len(record[0]) != 7 {
+			return nil, "", "", fmt.Errorf("invalid financial year %q", record[0])
+		}
+		start, err := strconv.Atoi(record[0][:4])
+		if err != nil || record[0] != fmt.Sprintf("%d-%02d", start, (start+1)%100) {
+			return nil, "", "", fmt.Errorf("invalid financial year %q", record[0])
+		}
+		index, err := strconv.ParseFloat(record[1], 64)
+		if err != nil || index <= 0 {
+			return nil, "", "", fmt.Errorf("%s: invalid index %q", record[0], record[1])
+		}
+
+		if latest != "" && record[0] <= latest {
+			return nil, "", "", fmt.Errorf("%s: financial years must be in ascending order", record[0])
+		}
+		if earliest == "" {
+			earliest = record[0]
+		}
+		latest = record[0]
+		parsed[record[0]] = index
+	}
+
+	if len(parsed) == 0 {
+		return nil, "", "", fmt.Errorf("no index values")
+	}
+	return parsed, earliest, latest, nil
+}
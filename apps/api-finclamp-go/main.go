@@ -2,6 +2,10 @@ package main
 
 import (
 	"finclamp-api/config"
+	"finclamp-api/currency"
+	"finclamp-api/history"
+	"finclamp-api/indexation"
+	"finclamp-api/marketdata"
 	"finclamp-api/routes"
 	"finclamp-api/server"
 	"log"
@@ -13,6 +17,32 @@ func main() {
 	// Load configuration
 	config.LoadConfig()
 	
+	// Load historical market data used for backtesting
+	if err := marketdata.Load(config.AppConfig.MarketDataFile); err != nil {
+		log.Fatalf("Failed to load market data: %v", err)
+	}
+	
+	// Load exchange rates used for conversion and formatting
+	if err := currency.Load(config.AppConfig.ExchangeRatesFile); err != nil {
+		log.Fatalf("Failed to load exchange rates: %v", err)
+	}
+	if _, ok := currency.Lookup(config.AppConfig.DefaultCurrency); !ok {
+		log.Fatalf("Default currency %s is not in the exchange rate table", config.AppConfig.DefaultCurrency)
+	}
+	
+	// Load the Cost Inflation Index used for capital gains indexation
+	if err := indexation.Load(config.AppConfig.CostInflationIndexFile); err != nil {
+		log.Fatalf("Failed to load cost inflation index: %v", err)
+	}
+	
+	// Open the per-user calculation history
+	if err := history.Open(config.AppConfig.HistoryFile); err != nil {
+		log.Fatalf("Failed to open calculation history: %v", err)
+	}
+	if config.AppConfig.AuthSecret == "" {
+		log.Println("AUTH_SECRET is not set: users cannot sign in and no history is recorded")
+	}
+	
 	// Initialize server
 	server.InitServer()
 	
@@ -0,0 +1,203 @@
+package marketdata
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed index_returns.csv
+var embeddedReturns []byte
+
+// MonthlyReturn represents one month of an index's total return
+type MonthlyReturn struct {
+	Month  time.Time
+	Return float64 // fractional, e.g. 0.012 for 1.2%
+}
+
+// Dataset describes where the loaded returns come from. A dataset declares
+// both in its leading comments, as "# source: ..." and "# illustrative: true";
+// a file that does not is attributed to its path and taken as real history.
+type Dataset struct {
+	Source       string
+	Illustrative bool
+}
+
+var (
+	mu      sync.RWMutex
+	series  map[string][]MonthlyReturn
+	dataset Dataset
+)
+
+// Load parses the index return dataset. When path is empty the dataset embedded
+// in the binary is used; otherwise the file at path replaces it.
+func Load(path string) error {
+	var reader io.Reader
+	source := "embedded dataset"
+
+	if path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("open market data file: %w", err)
+		}
+		defer file.Close()
+		reader = file
+		source = path
+	} else {
+		reader = bytes.NewReader(embeddedReturns)
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", source, err)
+	}
+	parsed, err := parse(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", source, err)
+	}
+	described, err := describe(data, source)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", source, err)
+	}
+
+	mu.Lock()
+	series = parsed
+	dataset = described
+	mu.Unlock()
+
+	if described.Illustrative {
+		log.Printf("Market data loaded from %s: %d indices of illustrative data", source, len(parsed))
+	} else {
+		log.Printf("Market data loaded from %s: %d indices", source, len(parsed))
+	}
+	return nil
+}
+
+// Source returns a description of the loaded dataset
+func Source() Dataset {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return dataset
+}
+
+// Series returns the monthly returns for an index in chronological order
+func Series(index string) ([]MonthlyReturn, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	returns, ok := series[strings.ToLower(index)]
+	return returns, ok
+}
+
+// Indices returns the names of all loaded indices
+func Indices() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(series))
+	for name := range series {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// describe reads the source and illustrative declarations from the comments
+// that open a dataset, attributing it to fallback when it names no source
+func describe(data []byte, fallback string) (Dataset, error) {
+	described := Dataset{Source: fallback}
+	for _, line := range strings.Split(string(data), "\n") {
+		comment, ok := strings.CutPrefix(strings.TrimSpace(line), "#")
+		if !ok {
+			break
+		}
+		key, value, _ := strings.Cut(comment, ":")
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "source":
+			described.Source = value
+		case "illustrative":
+			illustrative, err := strconv.ParseBool(value)
+			if err != nil {
+				return Dataset{}, fmt.Errorf("invalid illustrative declaration %q", value)
+			}
+			described.Illustrative = illustrative
+		}
+	}
+	return described, nil
+}
+
+func parse(r io.Reader) (map[string][]MonthlyReturn, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	if len(header) < 2 || !strings.EqualFold(header[0], "month") {
+		return nil, fmt.Errorf("header must start with \"month\" followed by index names")
+	}
+
+	names := make([]string, len(header)-1)
+	for i, name := range header[1:] {
+		names[i] = strings.ToLower(strings.TrimSpace(name))
+	}
+
+	parsed := make(map[string][]MonthlyReturn, len(names))
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		month, err := time.Parse("2006-01", record[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid month %q: %w", record[0], err)
+		}
+
+		for i, cell := range record[1:] {
+			cell = strings.TrimSpace(cell)
+			if cell == "" {
+				if len(parsed[names[i]]) > 0 {
+					return nil, fmt.Errorf("%s: gap at %s", names[i], record[0])
+				}
+				continue
+			}
+
+			value, err := strconv.ParseFloat(cell, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid return %q at %s", names[i], cell, record[0])
+			}
+
+			existing := parsed[names[i]]
+			if n := len(existing); n > 0 && !existing[n-1].Month.AddDate(0, 1, 0).Equal(month) {
+				return nil, fmt.Errorf("%s: months must be consecutive, got %s after %s",
+					names[i], record[0], existing[n-1].Month.Format("2006-01"))
+			}
+			parsed[names[i]] = append(existing, MonthlyReturn{Month: month, Return: value / 100})
+		}
+	}
+
+	for _, name := range names {
+		if len(parsed[name]) == 0 {
+			return nil, fmt.Errorf("%s: no data", name)
+		}
+	}
+
+	return parsed, nil
+}
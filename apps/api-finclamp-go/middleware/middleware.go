@@ -1,6 +1,12 @@
 package middleware
 
 import (
+	"finclamp-api/auth"
+	"finclamp-api/config"
+	"finclamp-api/utils"
+	"net/http"
+	"strings"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -18,3 +24,45 @@ func RequestID() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// Authenticate identifies the user from an "Authorization: Bearer" token and
+// stores their ID under "UserID". Requests without a token continue
+// anonymously; a token that fails verification is rejected.
+func Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if header == "" {
+			c.Next()
+			return
+		}
+
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || config.AppConfig.AuthSecret == "" {
+			utils.SendErrorResponse(c, http.StatusUnauthorized, "Invalid credentials", "expected a Bearer token signed with the configured secret")
+			c.Abort()
+			return
+		}
+
+		claims, err := auth.Verify(token, []byte(config.AppConfig.AuthSecret))
+		if err != nil {
+			utils.SendErrorResponse(c, http.StatusUnauthorized, "Invalid credentials", err.Error())
+			c.Abort()
+			return
+		}
+
+		c.Set("UserID", claims.Subject)
+		c.Next()
+	}
+}
+
+// RequireUser rejects requests that Authenticate did not identify a user for
+func RequireUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("UserID") == "" {
+			utils.SendErrorResponse(c, http.StatusUnauthorized, "Sign-in required", "send an Authorization: Bearer token")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
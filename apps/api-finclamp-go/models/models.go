@@ -12,43 +12,939 @@ type LoanCalculationRequest struct {
 
 // LoanCalculationResponse represents a loan calculation response
 type LoanCalculationResponse struct {
-	MonthlyPayment float64 `json:"monthlyPayment"`
-	TotalAmount    float64 `json:"totalAmount"`
-	TotalInterest  float64 `json:"totalInterest"`
-	NumPayments    int     `json:"numPayments"`
+	MonthlyPayment float64           `json:"monthlyPayment" money:"true"`
+	TotalAmount    float64           `json:"totalAmount" money:"true"`
+	TotalInterest  float64           `json:"totalInterest" money:"true"`
+	NumPayments    int               `json:"numPayments"`
+	Explanation    []ExplanationStep `json:"explanation,omitempty"`
 }
 
-// SavingsCalculationRequest represents a savings calculation request
+// SavingsCalculationRequest represents a savings calculation request. Either a
+// principal or a monthly contribution (a SIP or recurring deposit) is required.
 type SavingsCalculationRequest struct {
-	Principal            float64 `json:"principal" binding:"required,gt=0"`
-	Rate                 float64 `json:"rate" binding:"required,gt=0"`
-	Term                 int     `json:"term" binding:"required,gt=0"`
-	MonthlyContribution  float64 `json:"monthlyContribution"`
+	Principal           float64 `json:"principal" binding:"required_without=MonthlyContribution,gte=0"`
+	Rate                float64 `json:"rate" binding:"required,gt=0"`
+	Term                int     `json:"term" binding:"required,gt=0,lte=100"`
+	MonthlyContribution float64 `json:"monthlyContribution" binding:"gte=0"`
+	SeriesOptions
 }
 
 // SavingsCalculationResponse represents a savings calculation response
 type SavingsCalculationResponse struct {
-	FinalAmount        float64 `json:"finalAmount"`
-	TotalContributions float64 `json:"totalContributions"`
-	TotalInterest      float64 `json:"totalInterest"`
-	NumMonths          int     `json:"numMonths"`
+	FinalAmount        float64           `json:"finalAmount" money:"true"`
+	TotalContributions float64           `json:"totalContributions" money:"true"`
+	TotalInterest      float64           `json:"totalInterest" money:"true"`
+	NumMonths          int               `json:"numMonths"`
+	Series             []GrowthPoint     `json:"series,omitempty"`
+	Explanation        []ExplanationStep `json:"explanation,omitempty"`
 }
 
 // InvestmentCalculationRequest represents an investment calculation request
 type InvestmentCalculationRequest struct {
 	Amount         float64 `json:"amount" binding:"required,gt=0"`
 	ExpectedReturn float64 `json:"expectedReturn" binding:"required,gt=0"`
-	Term           int     `json:"term" binding:"required,gt=0"`
+	Term           int     `json:"term" binding:"required,gt=0,lte=100"`
 	RiskLevel      string  `json:"riskLevel"`
+	SeriesOptions
 }
 
 // InvestmentCalculationResponse represents an investment calculation response
 type InvestmentCalculationResponse struct {
-	ProjectedValue    float64 `json:"projectedValue"`
-	AdjustedValue     float64 `json:"adjustedValue"`
-	TotalGain         float64 `json:"totalGain"`
-	AdjustedGain      float64 `json:"adjustedGain"`
-	AnnualizedReturn  float64 `json:"annualizedReturn"`
+	ProjectedValue   float64           `json:"projectedValue" money:"true"`
+	AdjustedValue    float64           `json:"adjustedValue" money:"true"`
+	TotalGain        float64           `json:"totalGain" money:"true"`
+	AdjustedGain     float64           `json:"adjustedGain" money:"true"`
+	AnnualizedReturn float64           `json:"annualizedReturn"`
+	Series           []GrowthPoint     `json:"series,omitempty"`
+	Explanation      []ExplanationStep `json:"explanation,omitempty"`
+}
+
+// CurrencyOptions are accepted by every calculator. Currency is the ISO 4217
+// code the request's amounts are in (defaults to the configured currency);
+// Format adds locale-formatted strings for every monetary field, using Locale
+// or the currency's default locale.
+type CurrencyOptions struct {
+	Currency string `json:"currency" binding:"omitempty,len=3"`
+	Locale   string `json:"locale"`
+	Format   bool   `json:"format"`
+}
+
+// CurrencyConversionRequest represents a currency conversion request
+type CurrencyConversionRequest struct {
+	Amount float64 `json:"amount" binding:"gte=0"`
+	From   string  `json:"from" binding:"required,len=3"`
+	To     string  `json:"to" binding:"required,len=3"`
+	Locale string  `json:"locale"`
+}
+
+// CurrencyConversionResponse represents a currency conversion response
+type CurrencyConversionResponse struct {
+	Amount             float64           `json:"amount"`
+	From               string            `json:"from"`
+	To                 string            `json:"to"`
+	Rate               float64           `json:"rate"`
+	Converted          float64           `json:"converted"`
+	AsOf               string            `json:"asOf"`
+	AmountFormatted    string            `json:"amountFormatted"`
+	ConvertedFormatted string            `json:"convertedFormatted"`
+	Explanation        []ExplanationStep `json:"explanation,omitempty"`
+}
+
+// ExchangeRate represents one row of the exchange-rate table
+type ExchangeRate struct {
+	Code     string  `json:"code"`
+	Name     string  `json:"name"`
+	Symbol   string  `json:"symbol"`
+	Decimals int     `json:"decimals"`
+	Locale   string  `json:"locale"`
+	PerUSD   float64 `json:"perUsd"`
+	AsOf     string  `json:"asOf"`
+}
+
+// ExchangeRatesResponse represents the exchange-rate table
+type ExchangeRatesResponse struct {
+	Base    string         `json:"base"`
+	Rates   []ExchangeRate `json:"rates"`
+	Locales []string       `json:"locales"`
+}
+
+// SeriesOptions requests a balance-over-time series for charting. Series is
+// "monthly" or "yearly" (omit for none); MaxPoints caps the number of points
+// returned (120 when omitted), and InflationRate (annual %) is used to compute
+// real values.
+type SeriesOptions struct {
+	Series        string  `json:"series" binding:"omitempty,oneof=monthly yearly"`
+	MaxPoints     int     `json:"maxPoints" binding:"gte=0,lte=1200"`
+	InflationRate float64 `json:"inflationRate" binding:"gte=0,lte=50"`
+}
+
+// GrowthPoint represents one point of a balance-over-time series
+type GrowthPoint struct {
+	Month         int     `json:"month"`
+	Balance       float64 `json:"balance" money:"true"`
+	Contributions float64 `json:"contributions" money:"true"`
+	Interest      float64 `json:"interest" money:"true"`
+	RealValue     float64 `json:"realValue" money:"true"`
+}
+
+// RefinanceRequest represents a loan refinance / balance transfer comparison request
+type RefinanceRequest struct {
+	OutstandingBalance float64          `json:"outstandingBalance" binding:"required,gt=0"`
+	CurrentRate        float64          `json:"currentRate" binding:"required,gt=0"`
+	RemainingMonths    int              `json:"remainingMonths" binding:"required,gt=0,lte=600"`
+	Offers             []RefinanceOffer `json:"offers" binding:"required,min=1,max=10,dive"`
+}
+
+// RefinanceOffer represents a refinance offer to compare against the existing loan
+type RefinanceOffer struct {
+	Name                 string  `json:"name"`
+	Rate                 float64 `json:"rate" binding:"required,gt=0"`
+	TenureMonths         int     `json:"tenureMonths" binding:"required,gt=0,lte=600"`
+	ProcessingFee        float64 `json:"processingFee" binding:"gte=0"`
+	ProcessingFeePercent float64 `json:"processingFeePercent" binding:"gte=0"`
+}
+
+// AmortizationEntry represents one month of a loan repayment schedule
+type AmortizationEntry struct {
+	Month     int     `json:"month"`
+	Payment   float64 `json:"payment" money:"true"`
+	Principal float64 `json:"principal" money:"true"`
+	Interest  float64 `json:"interest" money:"true"`
+	Balance   float64 `json:"balance" money:"true"`
+}
+
+// RefinanceOption represents the cost of one repayment option
+type RefinanceOption struct {
+	Name           string              `json:"name"`
+	Rate           float64             `json:"rate"`
+	TenureMonths   int                 `json:"tenureMonths"`
+	MonthlyPayment float64             `json:"monthlyPayment" money:"true"`
+	TotalPayment   float64             `json:"totalPayment" money:"true"`
+	TotalInterest  float64             `json:"totalInterest" money:"true"`
+	Fees           float64             `json:"fees" money:"true"`
+	TotalCost      float64             `json:"totalCost" money:"true"`
+	NetSavings     float64             `json:"netSavings" money:"true"`
+	BreakEvenMonth *int                `json:"breakEvenMonth"`
+	Schedule       []AmortizationEntry `json:"schedule"`
+}
+
+// RefinanceResponse represents a refinance comparison response
+type RefinanceResponse struct {
+	Current     RefinanceOption   `json:"current"`
+	Offers      []RefinanceOption `json:"offers"`
+	BestOffer   string            `json:"bestOffer,omitempty"`
+	Explanation []ExplanationStep `json:"explanation,omitempty"`
+}
+
+// BacktestRequest represents a historical backtest request for a SIP or lump-sum plan
+type BacktestRequest struct {
+	Index  string  `json:"index" binding:"required"`
+	Mode   string  `json:"mode"`
+	Amount float64 `json:"amount" binding:"required,gt=0"`
+	Term   int     `json:"term" binding:"required,gt=0"`
+}
+
+// BacktestPeriod represents the outcome of one rolling start date
+type BacktestPeriod struct {
+	StartMonth       string  `json:"startMonth"`
+	EndMonth         string  `json:"endMonth"`
+	TotalInvested    float64 `json:"totalInvested" money:"true"`
+	FinalValue       float64 `json:"finalValue" money:"true"`
+	AnnualizedReturn float64 `json:"annualizedReturn"`
+	MaxDrawdown      float64 `json:"maxDrawdown"`
+}
+
+// ReturnDistribution summarizes annualized returns across all rolling periods
+type ReturnDistribution struct {
+	Min             float64 `json:"min"`
+	P10             float64 `json:"p10"`
+	P25             float64 `json:"p25"`
+	Median          float64 `json:"median"`
+	P75             float64 `json:"p75"`
+	P90             float64 `json:"p90"`
+	Max             float64 `json:"max"`
+	Mean            float64 `json:"mean"`
+	LossProbability float64 `json:"lossProbability"`
+}
+
+// BacktestResponse represents a historical backtest response. Illustrative is
+// true when the returns replayed are not real market history.
+type BacktestResponse struct {
+	Index           string             `json:"index"`
+	Mode            string             `json:"mode"`
+	PeriodMonths    int                `json:"periodMonths"`
+	DataFrom        string             `json:"dataFrom"`
+	DataTo          string             `json:"dataTo"`
+	DataSource      string             `json:"dataSource"`
+	Illustrative    bool               `json:"illustrative"`
+	PeriodsTested   int                `json:"periodsTested"`
+	Distribution    ReturnDistribution `json:"distribution"`
+	Best            BacktestPeriod     `json:"best"`
+	Worst           BacktestPeriod     `json:"worst"`
+	DeepestDrawdown BacktestPeriod     `json:"deepestDrawdown"`
+	Explanation     []ExplanationStep  `json:"explanation,omitempty"`
+}
+
+// GoalPlanRequest represents a multi-goal savings allocation request
+type GoalPlanRequest struct {
+	MonthlyCapacity float64         `json:"monthlyCapacity" binding:"required,gt=0"`
+	StartMonth      string          `json:"startMonth"`
+	Goals           []FinancialGoal `json:"goals" binding:"required,min=1,max=20,dive"`
+}
+
+// FinancialGoal represents one savings goal. Priority 1 is the most important;
+// TargetMonth and StartMonth use the "2006-01" format.
+type FinancialGoal struct {
+	Name           string  `json:"name" binding:"required"`
+	TargetAmount   float64 `json:"targetAmount" binding:"required,gt=0"`
+	TargetMonth    string  `json:"targetMonth" binding:"required"`
+	Priority       int     `json:"priority" binding:"gte=0"`
+	ExpectedReturn float64 `json:"expectedReturn" binding:"gte=0"`
+	CurrentSavings float64 `json:"currentSavings" binding:"gte=0"`
+}
+
+// AllocationPhase represents a run of months with the same monthly contribution
+type AllocationPhase struct {
+	FromMonth     string  `json:"fromMonth"`
+	ToMonth       string  `json:"toMonth"`
+	MonthlyAmount float64 `json:"monthlyAmount" money:"true"`
+}
+
+// GoalAllocation represents the funding plan and outcome for one goal
+type GoalAllocation struct {
+	Name            string            `json:"name"`
+	Priority        int               `json:"priority"`
+	TargetAmount    float64           `json:"targetAmount" money:"true"`
+	TargetMonth     string            `json:"targetMonth"`
+	Months          int               `json:"months"`
+	RequiredMonthly float64           `json:"requiredMonthly" money:"true"`
+	Allocation      []AllocationPhase `json:"allocation"`
+	ProjectedAmount float64           `json:"projectedAmount" money:"true"`
+	Shortfall       float64           `json:"shortfall" money:"true"`
+	Achievable      bool              `json:"achievable"`
+}
+
+// PlanPhase represents how monthly savings are split across goals over a run of months
+type PlanPhase struct {
+	FromMonth   string             `json:"fromMonth"`
+	ToMonth     string             `json:"toMonth"`
+	Allocations map[string]float64 `json:"allocations" money:"true"`
+	Unallocated float64            `json:"unallocated" money:"true"`
+}
+
+// GoalPlanResponse represents a multi-goal savings allocation plan
+type GoalPlanResponse struct {
+	StartMonth           string            `json:"startMonth"`
+	MonthlyCapacity      float64           `json:"monthlyCapacity" money:"true"`
+	TotalRequiredMonthly float64           `json:"totalRequiredMonthly" money:"true"`
+	AchievableGoals      int               `json:"achievableGoals"`
+	TotalShortfall       float64           `json:"totalShortfall" money:"true"`
+	Goals                []GoalAllocation  `json:"goals"`
+	Plan                 []PlanPhase       `json:"plan"`
+	Explanation          []ExplanationStep `json:"explanation,omitempty"`
+}
+
+// RebalanceRequest represents a portfolio rebalancing request. Holdings and
+// TargetAllocation are keyed by asset class; TargetAllocation is in percent and
+// may be omitted in favour of a RiskProfile (low, medium or high).
+type RebalanceRequest struct {
+	Holdings         map[string]float64 `json:"holdings" binding:"required,min=1,dive,gte=0"`
+	TargetAllocation map[string]float64 `json:"targetAllocation" binding:"omitempty,dive,gte=0"`
+	RiskProfile      string             `json:"riskProfile" binding:"omitempty,oneof=low medium high"`
+	ToleranceBand    float64            `json:"toleranceBand" binding:"gte=0"`
+	ToleranceBands   map[string]float64 `json:"toleranceBands" binding:"omitempty,dive,gte=0"`
+	FreshCash        float64            `json:"freshCash" binding:"gte=0"`
+	BuyOnly          bool               `json:"buyOnly"`
+}
+
+// AssetPosition represents one asset class before and after rebalancing
+type AssetPosition struct {
+	AssetClass     string  `json:"assetClass"`
+	CurrentValue   float64 `json:"currentValue" money:"true"`
+	CurrentPercent float64 `json:"currentPercent"`
+	TargetPercent  float64 `json:"targetPercent"`
+	Drift          float64 `json:"drift"`
+	Band           float64 `json:"band"`
+	OutOfBand      bool    `json:"outOfBand"`
+	AfterValue     float64 `json:"afterValue" money:"true"`
+	AfterPercent   float64 `json:"afterPercent"`
+}
+
+// RebalanceTrade represents a single buy or sell
+type RebalanceTrade struct {
+	AssetClass string  `json:"assetClass"`
+	Action     string  `json:"action"`
+	Amount     float64 `json:"amount" money:"true"`
+}
+
+// RebalanceResponse represents a portfolio rebalancing plan
+type RebalanceResponse struct {
+	TotalValue       float64            `json:"totalValue" money:"true"`
+	TargetAllocation map[string]float64 `json:"targetAllocation"`
+	RebalanceNeeded  bool               `json:"rebalanceNeeded"`
+	Trades           []RebalanceTrade   `json:"trades"`
+	TotalTraded      float64            `json:"totalTraded" money:"true"`
+	Positions        []AssetPosition    `json:"positions"`
+	Explanation      []ExplanationStep  `json:"explanation,omitempty"`
+}
+
+// PropertyCapitalGainsRequest represents a capital gains calculation for a
+// property sale. Dates use the "2006-01-02" format; SlabRate is the seller's
+// marginal income-tax rate in percent, applied to short-term gains.
+type PropertyCapitalGainsRequest struct {
+	PurchaseDate        string                `json:"purchaseDate" binding:"required"`
+	PurchasePrice       float64               `json:"purchasePrice" binding:"gte=0"`
+	FairMarketValue2001 float64               `json:"fairMarketValue2001" binding:"gte=0"`
+	SaleDate            string                `json:"saleDate" binding:"required"`
+	SalePrice           float64               `json:"salePrice" binding:"required,gt=0"`
+	TransferExpenses    float64               `json:"transferExpenses" binding:"gte=0"`
+	Improvements        []PropertyImprovement `json:"improvements" binding:"omitempty,dive"`
+	ResidentialProperty bool                  `json:"residentialProperty"`
+	Reinvestments       []GainsReinvestment   `json:"reinvestments" binding:"omitempty,dive"`
+	SlabRate            float64               `json:"slabRate" binding:"gte=0,lte=100"`
+}
+
+// PropertyImprovement represents a capital improvement made to the property
+type PropertyImprovement struct {
+	Date string  `json:"date" binding:"required"`
+	Cost float64 `json:"cost" binding:"required,gt=0"`
+}
+
+// GainsReinvestment represents an amount reinvested to claim an exemption
+type GainsReinvestment struct {
+	Section string  `json:"section" binding:"required,oneof=54 54EC 54F"`
+	Amount  float64 `json:"amount" binding:"required,gt=0"`
+}
+
+// ExemptionApplied represents the exemption allowed for one reinvestment
+type ExemptionApplied struct {
+	Section  string  `json:"section"`
+	Invested float64 `json:"invested" money:"true"`
+	Exempt   float64 `json:"exempt" money:"true"`
+	Note     string  `json:"note,omitempty"`
+}
+
+// CapitalGainsOption represents the gain and tax under one computation method
+type CapitalGainsOption struct {
+	Method        string             `json:"method"`
+	CostDeduction float64            `json:"costDeduction" money:"true"`
+	Gain          float64            `json:"gain" money:"true"`
+	Exemptions    []ExemptionApplied `json:"exemptions"`
+	TaxableGain   float64            `json:"taxableGain" money:"true"`
+	TaxRate       float64            `json:"taxRate"`
+	Tax           float64            `json:"tax" money:"true"`
+	Cess          float64            `json:"cess" money:"true"`
+	TotalTax      float64            `json:"totalTax" money:"true"`
+}
+
+// PropertyCapitalGainsResponse represents a property capital gains calculation
+type PropertyCapitalGainsResponse struct {
+	HoldingMonths            int                  `json:"holdingMonths"`
+	Term                     string               `json:"term"`
+	PurchaseYear             string               `json:"purchaseYear"`
+	SaleYear                 string               `json:"saleYear"`
+	NetSaleConsideration     float64              `json:"netSaleConsideration" money:"true"`
+	CostOfAcquisition        float64              `json:"costOfAcquisition" money:"true"`
+	IndexedCostOfAcquisition float64              `json:"indexedCostOfAcquisition" money:"true"`
+	CostOfImprovement        float64              `json:"costOfImprovement" money:"true"`
+	IndexedCostOfImprovement float64              `json:"indexedCostOfImprovement" money:"true"`
+	Options                  []CapitalGainsOption `json:"options"`
+	SelectedMethod           string               `json:"selectedMethod"`
+	TaxableGain              float64              `json:"taxableGain" money:"true"`
+	TotalTax                 float64              `json:"totalTax" money:"true"`
+	Explanation              []ExplanationStep    `json:"explanation,omitempty"`
+}
+
+// ProjectionOptions controls the cost projection returned by the everyday
+// calculators. ProjectionYears defaults to 10 and ExpectedReturn (annual %) to
+// 12; the opportunity cost is what the money would have grown to had it been
+// invested monthly at that return instead of spent.
+type ProjectionOptions struct {
+	ProjectionYears float64  `json:"projectionYears" binding:"gte=0,lte=60"`
+	ExpectedReturn  *float64 `json:"expectedReturn" binding:"omitempty,gte=0,lte=50"`
+}
+
+// CostProjection represents a recurring cost annualized and projected forward
+type CostProjection struct {
+	Daily           float64 `json:"daily" money:"true"`
+	Weekly          float64 `json:"weekly" money:"true"`
+	Monthly         float64 `json:"monthly" money:"true"`
+	Yearly          float64 `json:"yearly" money:"true"`
+	Years           float64 `json:"years"`
+	Lifetime        float64 `json:"lifetime" money:"true"`
+	ExpectedReturn  float64 `json:"expectedReturn"`
+	InvestedValue   float64 `json:"investedValue" money:"true"`
+	OpportunityCost float64 `json:"opportunityCost" money:"true"`
+}
+
+// Frequency describes how often something happens, e.g. 3 times per week. For
+// a per-day frequency DaysPerWeek limits it to the days it applies (0 means
+// every day).
+type Frequency struct {
+	Times       float64 `json:"times" binding:"required,gt=0"`
+	Per         string  `json:"per" binding:"required,oneof=day week month quarter year"`
+	DaysPerWeek float64 `json:"daysPerWeek" binding:"gte=0,lte=7"`
+}
+
+// RecurringCost represents a fixed amount paid once per period
+type RecurringCost struct {
+	Name   string  `json:"name"`
+	Amount float64 `json:"amount" binding:"required,gt=0"`
+	Per    string  `json:"per" binding:"required,oneof=day week month quarter year"`
+}
+
+// VehicleFuel describes a vehicle's running cost. FuelPrice is per VolumeUnit
+// (default litres) and Efficiency is in EfficiencyUnit (default km/l).
+type VehicleFuel struct {
+	FuelPrice      float64 `json:"fuelPrice" binding:"required,gt=0"`
+	VolumeUnit     string  `json:"volumeUnit" binding:"omitempty,oneof=l gal-us gal-uk"`
+	Efficiency     float64 `json:"efficiency" binding:"required,gt=0"`
+	EfficiencyUnit string  `json:"efficiencyUnit" binding:"omitempty,oneof=km/l l/100km mpg-us mpg-uk"`
+}
+
+// TipRequest represents a tip calculation request. TipPercent overrides the
+// percentage implied by ServiceQuality; Frequency (how often the bill recurs)
+// adds a cost projection.
+type TipRequest struct {
+	BillAmount     float64    `json:"billAmount" binding:"required,gt=0"`
+	TipPercent     *float64   `json:"tipPercent" binding:"omitempty,gte=0,lte=100"`
+	ServiceQuality string     `json:"serviceQuality" binding:"omitempty,oneof=poor average good excellent outstanding"`
+	People         int        `json:"people" binding:"gte=0"`
+	RoundUp        bool       `json:"roundUp"`
+	Frequency      *Frequency `json:"frequency"`
+	ProjectionOptions
+}
+
+// TipResponse represents a tip calculation response
+type TipResponse struct {
+	TipPercent     float64           `json:"tipPercent"`
+	TipAmount      float64           `json:"tipAmount" money:"true"`
+	TotalAmount    float64           `json:"totalAmount" money:"true"`
+	People         int               `json:"people"`
+	PerPersonBill  float64           `json:"perPersonBill" money:"true"`
+	PerPersonTip   float64           `json:"perPersonTip" money:"true"`
+	PerPersonTotal float64           `json:"perPersonTotal" money:"true"`
+	Projection     *CostProjection   `json:"projection,omitempty"`
+	Explanation    []ExplanationStep `json:"explanation,omitempty"`
+}
+
+// DiscountRequest represents a discount calculation request. The additional
+// discount applies to the already discounted price and tax to the final price.
+type DiscountRequest struct {
+	OriginalPrice      float64    `json:"originalPrice" binding:"required,gt=0"`
+	DiscountPercent    float64    `json:"discountPercent" binding:"gte=0,lte=100"`
+	AdditionalDiscount float64    `json:"additionalDiscount" binding:"gte=0,lte=100"`
+	TaxPercent         float64    `json:"taxPercent" binding:"gte=0,lte=100"`
+	Frequency          *Frequency `json:"frequency"`
+	ProjectionOptions
+}
+
+// DiscountResponse represents a discount calculation response
+type DiscountResponse struct {
+	DiscountAmount           float64           `json:"discountAmount" money:"true"`
+	AdditionalDiscountAmount float64           `json:"additionalDiscountAmount" money:"true"`
+	PriceAfterDiscount       float64           `json:"priceAfterDiscount" money:"true"`
+	TaxAmount                float64           `json:"taxAmount" money:"true"`
+	FinalPrice               float64           `json:"finalPrice" money:"true"`
+	TotalSavings             float64           `json:"totalSavings" money:"true"`
+	EffectiveDiscount        float64           `json:"effectiveDiscount"`
+	Projection               *CostProjection   `json:"projection,omitempty"`
+	Explanation              []ExplanationStep `json:"explanation,omitempty"`
+}
+
+// FuelCostRequest represents a fuel cost calculation for a distance driven
+// per period, e.g. 40 km per day on 5 days a week
+type FuelCostRequest struct {
+	VehicleFuel
+	Distance     float64 `json:"distance" binding:"required,gt=0"`
+	DistanceUnit string  `json:"distanceUnit" binding:"omitempty,oneof=km mi"`
+	Per          string  `json:"per" binding:"required,oneof=day week month quarter year"`
+	DaysPerWeek  float64 `json:"daysPerWeek" binding:"gte=0,lte=7"`
+	ProjectionOptions
+}
+
+// FuelCostResponse represents a fuel cost calculation response
+type FuelCostResponse struct {
+	CostPerDistance float64           `json:"costPerDistance" money:"true"`
+	DistanceUnit    string            `json:"distanceUnit"`
+	KmPerLitre      float64           `json:"kmPerLitre"`
+	YearlyDistance  float64           `json:"yearlyDistance"`
+	YearlyFuel      float64           `json:"yearlyFuel"`
+	VolumeUnit      string            `json:"volumeUnit"`
+	Projection      CostProjection    `json:"projection"`
+	Explanation     []ExplanationStep `json:"explanation,omitempty"`
+}
+
+// CommuteRequest compares ways of making the same commute. OneWayDistance is
+// doubled for the round trip; DaysPerWeek defaults to 5.
+type CommuteRequest struct {
+	OneWayDistance float64       `json:"oneWayDistance" binding:"required,gt=0"`
+	DistanceUnit   string        `json:"distanceUnit" binding:"omitempty,oneof=km mi"`
+	DaysPerWeek    float64       `json:"daysPerWeek" binding:"gte=0,lte=7"`
+	Modes          []CommuteMode `json:"modes" binding:"required,min=1,dive"`
+	ProjectionOptions
+}
+
+// CommuteMode represents one way of commuting. Vehicle adds fuel for the
+// distance, FarePerDistance a metered fare (e.g. taxi), DailyCosts parking,
+// tolls or tickets per commuting day, and FixedCosts passes or maintenance.
+type CommuteMode struct {
+	Name            string          `json:"name" binding:"required"`
+	Vehicle         *VehicleFuel    `json:"vehicle"`
+	FarePerDistance float64         `json:"farePerDistance" binding:"gte=0"`
+	DailyCosts      float64         `json:"dailyCosts" binding:"gte=0"`
+	FixedCosts      []RecurringCost `json:"fixedCosts" binding:"omitempty,dive"`
+}
+
+// CommuteModeCost represents the cost of one commuting mode
+type CommuteModeCost struct {
+	Name            string         `json:"name"`
+	DailyCost       float64        `json:"dailyCost" money:"true"`
+	CostPerDistance float64        `json:"costPerDistance" money:"true"`
+	ExtraPerYear    float64        `json:"extraPerYear" money:"true"`
+	Projection      CostProjection `json:"projection"`
+}
+
+// CommuteResponse represents a commute comparison, cheapest mode first
+type CommuteResponse struct {
+	DistanceUnit   string            `json:"distanceUnit"`
+	YearlyDistance float64           `json:"yearlyDistance"`
+	CommutingDays  float64           `json:"commutingDays"`
+	Modes          []CommuteModeCost `json:"modes"`
+	Cheapest       string            `json:"cheapest"`
+	Explanation    []ExplanationStep `json:"explanation,omitempty"`
+}
+
+// SubscriptionRequest represents a subscription cost calculation request
+type SubscriptionRequest struct {
+	Subscriptions []Subscription `json:"subscriptions" binding:"required,min=1,dive"`
+	ProjectionOptions
+}
+
+// Subscription represents one subscription billed once per Billing period
+type Subscription struct {
+	Name     string  `json:"name" binding:"required"`
+	Cost     float64 `json:"cost" binding:"required,gt=0"`
+	Billing  string  `json:"billing" binding:"required,oneof=week month quarter year"`
+	Category string  `json:"category"`
+}
+
+// SubscriptionCost represents the annualized cost of one subscription
+type SubscriptionCost struct {
+	Name     string  `json:"name"`
+	Category string  `json:"category"`
+	Monthly  float64 `json:"monthly" money:"true"`
+	Yearly   float64 `json:"yearly" money:"true"`
+	Share    float64 `json:"share"`
+}
+
+// SubscriptionResponse represents a subscription cost calculation response
+type SubscriptionResponse struct {
+	Subscriptions  []SubscriptionCost `json:"subscriptions"`
+	ByCategory     map[string]float64 `json:"byCategory" money:"true"`
+	ByBilling      map[string]float64 `json:"byBilling" money:"true"`
+	AverageMonthly float64            `json:"averageMonthly" money:"true"`
+	Projection     CostProjection     `json:"projection"`
+	Explanation    []ExplanationStep  `json:"explanation,omitempty"`
+}
+
+// HabitCostRequest represents a habit cost calculation request
+type HabitCostRequest struct {
+	Habits []Habit `json:"habits" binding:"required,min=1,dive"`
+	ProjectionOptions
+}
+
+// Habit represents a recurring purchase, e.g. 2 coffees per day at 150 each
+type Habit struct {
+	Name      string    `json:"name" binding:"required"`
+	UnitCost  float64   `json:"unitCost" binding:"required,gt=0"`
+	Frequency Frequency `json:"frequency" binding:"required"`
+}
+
+// HabitCost represents the projected cost of one habit
+type HabitCost struct {
+	Name       string         `json:"name"`
+	Projection CostProjection `json:"projection"`
+}
+
+// HabitCostResponse represents a habit cost calculation response
+type HabitCostResponse struct {
+	Habits      []HabitCost       `json:"habits"`
+	Projection  CostProjection    `json:"projection"`
+	Explanation []ExplanationStep `json:"explanation,omitempty"`
+}
+
+// WFHSavingsRequest represents a work-from-home savings calculation. Commute
+// and food costs are per office day and saved on every day worked from home;
+// OfficeCosts (e.g. formal clothing) shrink in proportion to office days
+// avoided; HomeCosts are the extra running costs of working from home, and
+// HomeOfficeSetup is a one-off cost paid back from the savings.
+type WFHSavingsRequest struct {
+	WFHDaysPerWeek    float64         `json:"wfhDaysPerWeek" binding:"required,gt=0,lte=7"`
+	WorkDaysPerWeek   float64         `json:"workDaysPerWeek" binding:"gte=0,lte=7"`
+	OneWayDistance    float64         `json:"oneWayDistance" binding:"gte=0"`
+	DistanceUnit      string          `json:"distanceUnit" binding:"omitempty,oneof=km mi"`
+	Vehicle           *VehicleFuel    `json:"vehicle"`
+	DailyCommuteCosts float64         `json:"dailyCommuteCosts" binding:"gte=0"`
+	DailyFoodCosts    float64         `json:"dailyFoodCosts" binding:"gte=0"`
+	OfficeCosts       []RecurringCost `json:"officeCosts" binding:"omitempty,dive"`
+	HomeCosts         []RecurringCost `json:"homeCosts" binding:"omitempty,dive"`
+	HomeOfficeSetup   float64         `json:"homeOfficeSetup" binding:"gte=0"`
+	ProjectionOptions
+}
+
+// WFHSavingsResponse represents a work-from-home savings calculation response
+type WFHSavingsResponse struct {
+	SavedPerWFHDay   float64           `json:"savedPerWfhDay" money:"true"`
+	CommuteSavings   float64           `json:"commuteSavings" money:"true"`
+	FoodSavings      float64           `json:"foodSavings" money:"true"`
+	OfficeSavings    float64           `json:"officeSavings" money:"true"`
+	HomeCosts        float64           `json:"homeCosts" money:"true"`
+	NetYearlySavings float64           `json:"netYearlySavings" money:"true"`
+	PaybackMonths    *float64          `json:"paybackMonths"`
+	Projection       CostProjection    `json:"projection"`
+	Explanation      []ExplanationStep `json:"explanation,omitempty"`
+}
+
+// LifeInsuranceRequest represents a term insurance needs analysis. Rates are
+// annual percentages: IncomeGrowth for the human-life-value method, Inflation
+// for the expense-replacement method, and DiscountRate the return the payout is
+// assumed to earn. YearsToSupport defaults to the longest dependant's support
+// period, or the years to retirement when no dependants are given.
+type LifeInsuranceRequest struct {
+	Age                    int             `json:"age" binding:"required,gte=18,lt=100"`
+	RetirementAge          int             `json:"retirementAge" binding:"gte=0,lte=100"`
+	AnnualIncome           float64         `json:"annualIncome" binding:"required,gt=0"`
+	AnnualPersonalExpenses float64         `json:"annualPersonalExpenses" binding:"gte=0"`
+	IncomeGrowth           float64         `json:"incomeGrowth" binding:"gte=0,lte=50"`
+	AnnualFamilyExpenses   float64         `json:"annualFamilyExpenses" binding:"gte=0"`
+	Inflation              *float64        `json:"inflation" binding:"omitempty,gte=0,lte=50"`
+	DiscountRate           *float64        `json:"discountRate" binding:"omitempty,gte=0,lte=50"`
+	YearsToSupport         int             `json:"yearsToSupport" binding:"gte=0,lte=80"`
+	Dependants             []Dependant     `json:"dependants" binding:"omitempty,dive"`
+	Liabilities            float64         `json:"liabilities" binding:"gte=0"`
+	Goals                  []FutureExpense `json:"goals" binding:"omitempty,dive"`
+	ExistingCover          float64         `json:"existingCover" binding:"gte=0"`
+	ExistingAssets         float64         `json:"existingAssets" binding:"gte=0"`
+}
+
+// Dependant represents someone the insured supports until SupportUntilAge
+type Dependant struct {
+	Name            string `json:"name"`
+	Age             int    `json:"age" binding:"gte=0,lt=120"`
+	SupportUntilAge int    `json:"supportUntilAge" binding:"required,gt=0,lt=120"`
+}
+
+// FutureExpense represents a future commitment such as a child's education,
+// costing CostToday in today's money YearsAway years from now
+type FutureExpense struct {
+	Name      string  `json:"name" binding:"required"`
+	CostToday float64 `json:"costToday" binding:"required,gt=0"`
+	YearsAway int     `json:"yearsAway" binding:"gte=0,lte=80"`
+}
+
+// CoverEstimate represents the cover one method recommends
+type CoverEstimate struct {
+	Method           string  `json:"method"`
+	Years            int     `json:"years"`
+	PresentValue     float64 `json:"presentValue" money:"true"`
+	Liabilities      float64 `json:"liabilities" money:"true"`
+	Goals            float64 `json:"goals" money:"true"`
+	ExistingAssets   float64 `json:"existingAssets" money:"true"`
+	RecommendedCover float64 `json:"recommendedCover" money:"true"`
+	Gap              float64 `json:"gap" money:"true"`
+}
+
+// LifeInsuranceResponse represents a term insurance needs analysis. The
+// recommendation is the larger of the two methods' estimates.
+type LifeInsuranceResponse struct {
+	Estimates         []CoverEstimate   `json:"estimates"`
+	RecommendedMethod string            `json:"recommendedMethod"`
+	RecommendedCover  float64           `json:"recommendedCover" money:"true"`
+	ExistingCover     float64           `json:"existingCover" money:"true"`
+	Gap               float64           `json:"gap" money:"true"`
+	IncomeMultiple    float64           `json:"incomeMultiple"`
+	Explanation       []ExplanationStep `json:"explanation,omitempty"`
+}
+
+// EmergencyFundRequest represents an emergency fund target calculation.
+// JobStability is stable, moderate (default) or unstable; Earners defaults to 1.
+// MedicalBuffer is an extra amount set aside for uninsured medical costs.
+type EmergencyFundRequest struct {
+	MonthlyExpenses float64 `json:"monthlyExpenses" binding:"required,gt=0"`
+	MonthlyEMIs     float64 `json:"monthlyEmis" binding:"gte=0"`
+	JobStability    string  `json:"jobStability" binding:"omitempty,oneof=stable moderate unstable"`
+	Earners         int     `json:"earners" binding:"gte=0"`
+	Dependants      int     `json:"dependants" binding:"gte=0"`
+	MedicalBuffer   float64 `json:"medicalBuffer" binding:"gte=0"`
+	CurrentSavings  float64 `json:"currentSavings" binding:"gte=0"`
+	MonthlySaving   float64 `json:"monthlySaving" binding:"gte=0"`
+}
+
+// FundAdjustment represents one reason the target was raised
+type FundAdjustment struct {
+	Reason string `json:"reason"`
+	Months int    `json:"months"`
+}
+
+// EmergencyFundResponse represents an emergency fund target
+type EmergencyFundResponse struct {
+	MonthlyNeed    float64           `json:"monthlyNeed" money:"true"`
+	BaseMonths     int               `json:"baseMonths"`
+	Adjustments    []FundAdjustment  `json:"adjustments"`
+	TargetMonths   int               `json:"targetMonths"`
+	TargetAmount   float64           `json:"targetAmount" money:"true"`
+	CurrentSavings float64           `json:"currentSavings" money:"true"`
+	Gap            float64           `json:"gap" money:"true"`
+	MonthsToTarget *int              `json:"monthsToTarget"`
+	Explanation    []ExplanationStep `json:"explanation,omitempty"`
+}
+
+// GratuityRequest represents a gratuity calculation. MonthlySalary is the last
+// drawn basic pay plus dearness allowance; employees not covered by the Payment
+// of Gratuity Act are paid on AverageMonthlySalary (the average of the last ten
+// months), which defaults to MonthlySalary. Reason is resignation (default),
+// retirement, death or disability; the five-year minimum is waived for the last
+// two.
+type GratuityRequest struct {
+	MonthlySalary        float64 `json:"monthlySalary" binding:"required,gt=0"`
+	AverageMonthlySalary float64 `json:"averageMonthlySalary" binding:"gte=0"`
+	ServiceYears         int     `json:"serviceYears" binding:"gte=0,lte=60"`
+	ServiceMonths        int     `json:"serviceMonths" binding:"gte=0,lte=11"`
+	CoveredByAct         *bool   `json:"coveredByAct"`
+	Reason               string  `json:"reason" binding:"omitempty,oneof=resignation retirement death disability"`
+}
+
+// GratuityResponse represents a gratuity calculation
+type GratuityResponse struct {
+	Eligible        bool              `json:"eligible"`
+	Note            string            `json:"note,omitempty"`
+	CoveredByAct    bool              `json:"coveredByAct"`
+	Salary          float64           `json:"salary" money:"true"`
+	QualifyingYears int               `json:"qualifyingYears"`
+	Divisor         int               `json:"divisor"`
+	Gratuity        float64           `json:"gratuity" money:"true"`
+	ExemptLimit     float64           `json:"exemptLimit" money:"true"`
+	Exempt          float64           `json:"exempt" money:"true"`
+	Taxable         float64           `json:"taxable" money:"true"`
+	Explanation     []ExplanationStep `json:"explanation,omitempty"`
+}
+
+// HRAExemptionRequest represents an HRA exemption calculation. Amounts are
+// monthly; BasicSalary includes dearness allowance that counts for retirement
+// benefits. Months defaults to 12.
+type HRAExemptionRequest struct {
+	BasicSalary float64 `json:"basicSalary" binding:"required,gt=0"`
+	HRAReceived float64 `json:"hraReceived" binding:"gte=0"`
+	RentPaid    float64 `json:"rentPaid" binding:"gte=0"`
+	Metro       bool    `json:"metro"`
+	Months      int     `json:"months" binding:"gte=0,lte=12"`
+}
+
+// HRALimit represents one of the three limits on the HRA exemption
+type HRALimit struct {
+	Rule   string  `json:"rule"`
+	Amount float64 `json:"amount" money:"true"`
+}
+
+// HRAExemptionResponse represents an HRA exemption calculation. Amounts are for
+// the whole period.
+type HRAExemptionResponse struct {
+	Limits       []HRALimit        `json:"limits"`
+	LimitingRule string            `json:"limitingRule"`
+	HRAReceived  float64           `json:"hraReceived" money:"true"`
+	Exempt       float64           `json:"exempt" money:"true"`
+	Taxable      float64           `json:"taxable" money:"true"`
+	Explanation  []ExplanationStep `json:"explanation,omitempty"`
+}
+
+// SalaryRules configures how a CTC is split into components. Unset fields take
+// common defaults: basic 40% of CTC, HRA 50% of basic in metros and 40%
+// elsewhere, PF 12% of basic on wages up to 15,000 a month (0 removes the
+// ceiling), gratuity provision 4.81% of basic and professional tax 2,400 a year.
+// Employer PF and the gratuity provision are carved out of CTC unless
+// EmployerPFInCTC or GratuityInCTC is false.
+type SalaryRules struct {
+	BasicPercent    *float64 `json:"basicPercent" binding:"omitempty,gt=0,lte=100"`
+	HRAPercent      *float64 `json:"hraPercent" binding:"omitempty,gte=0,lte=100"`
+	VariablePercent float64  `json:"variablePercent" binding:"gte=0,lt=100"`
+	PFRate          *float64 `json:"pfRate" binding:"omitempty,gte=0,lte=100"`
+	PFWageCeiling   *float64 `json:"pfWageCeiling" binding:"omitempty,gte=0"`
+	GratuityPercent *float64 `json:"gratuityPercent" binding:"omitempty,gte=0,lte=100"`
+	ProfessionalTax *float64 `json:"professionalTax" binding:"omitempty,gte=0"`
+	EmployerPFInCTC *bool    `json:"employerPfInCtc"`
+	GratuityInCTC   *bool    `json:"gratuityInCtc"`
+}
+
+// SalaryStructureRequest represents a CTC to in-hand salary calculation. CTC is
+// annual; Regime is new (default) or old. MonthlyRent, Section80C (investments
+// besides employee PF, capped together at 1.5 lakh) and OtherDeductions (e.g.
+// 80D) only reduce tax under the old regime.
+type SalaryStructureRequest struct {
+	CTC             float64     `json:"ctc" binding:"required,gt=0"`
+	Regime          string      `json:"regime" binding:"omitempty,oneof=new old"`
+	Metro           bool        `json:"metro"`
+	MonthlyRent     float64     `json:"monthlyRent" binding:"gte=0"`
+	Section80C      float64     `json:"section80c" binding:"gte=0"`
+	OtherDeductions float64     `json:"otherDeductions" binding:"gte=0"`
+	Rules           SalaryRules `json:"rules"`
+}
+
+// SalaryComponent represents one line of a salary breakdown
+type SalaryComponent struct {
+	Name    string  `json:"name"`
+	Annual  float64 `json:"annual" money:"true"`
+	Monthly float64 `json:"monthly" money:"true"`
+}
+
+// SlabTax represents the tax on the part of income within one slab. To is zero
+// for the top slab.
+type SlabTax struct {
+	From   float64 `json:"from" money:"true"`
+	To     float64 `json:"to" money:"true"`
+	Rate   float64 `json:"rate"`
+	Income float64 `json:"income" money:"true"`
+	Tax    float64 `json:"tax" money:"true"`
+}
+
+// IncomeTaxBreakdown represents income tax computed under one regime
+type IncomeTaxBreakdown struct {
+	Regime        string    `json:"regime"`
+	FinancialYear string    `json:"financialYear"`
+	TaxableIncome float64   `json:"taxableIncome" money:"true"`
+	Slabs         []SlabTax `json:"slabs"`
+	SlabTax       float64   `json:"slabTax" money:"true"`
+	Rebate        float64   `json:"rebate" money:"true"`
+	Surcharge     float64   `json:"surcharge" money:"true"`
+	Cess          float64   `json:"cess" money:"true"`
+	TotalTax      float64   `json:"totalTax" money:"true"`
+}
+
+// SalaryStructureResponse represents a CTC to in-hand salary breakdown.
+// MonthlyInHand spreads the year's tax evenly and leaves out variable pay, which
+// is paid separately.
+type SalaryStructureResponse struct {
+	CTC                   float64            `json:"ctc" money:"true"`
+	Earnings              []SalaryComponent  `json:"earnings"`
+	EmployerContributions []SalaryComponent  `json:"employerContributions"`
+	GrossSalary           float64            `json:"grossSalary" money:"true"`
+	Deductions            []SalaryComponent  `json:"deductions"`
+	TaxExemptions         []SalaryComponent  `json:"taxExemptions"`
+	IncomeTax             IncomeTaxBreakdown `json:"incomeTax"`
+	AnnualInHand          float64            `json:"annualInHand" money:"true"`
+	MonthlyInHand         float64            `json:"monthlyInHand" money:"true"`
+	VariablePay           float64            `json:"variablePay" money:"true"`
+	Explanation           []ExplanationStep  `json:"explanation,omitempty"`
+}
+
+// ParseQueryRequest represents a natural-language calculation query
+type ParseQueryRequest struct {
+	Query string `json:"query" binding:"required"`
+}
+
+// ParseQueryResponse represents a parsed query. Result is set when the query
+// had everything its calculator needs; otherwise Missing, Unreadable and
+// Clarification say what to add or rewrite.
+type ParseQueryResponse struct {
+	Query         string                 `json:"query"`
+	Calculator    string                 `json:"calculator"`
+	Request       map[string]interface{} `json:"request"`
+	Assumptions   []string               `json:"assumptions,omitempty"`
+	Missing       []string               `json:"missing,omitempty"`
+	Unreadable    []string               `json:"unreadable,omitempty"`
+	Clarification string                 `json:"clarification,omitempty"`
+	Result        interface{}            `json:"result,omitempty"`
+}
+
+// HistoryEntry represents a calculation a signed-in user ran
+type HistoryEntry struct {
+	ID         string                 `json:"id"`
+	Calculator string                 `json:"calculator"`
+	Version    string                 `json:"version"`
+	CreatedAt  time.Time              `json:"createdAt"`
+	Request    map[string]interface{} `json:"request"`
+	Response   map[string]interface{} `json:"response"`
+}
+
+// HistoryListResponse represents a page of a user's calculation history.
+// Calculators lists every calculator the user has history for, for filtering.
+type HistoryListResponse struct {
+	Entries     []HistoryEntry `json:"entries"`
+	Total       int            `json:"total"`
+	Limit       int            `json:"limit"`
+	Offset      int            `json:"offset"`
+	Calculators []string       `json:"calculators"`
+}
+
+// HistoryCompareRequest represents a request to compare history entries
+type HistoryCompareRequest struct {
+	IDs []string `json:"ids" binding:"required,min=2,max=10,dive,required"`
+}
+
+// FieldDiff represents one field that differs between compared entries.
+// Values line up with the compared entries (null where an entry lacks the
+// field); Delta is last minus first when both are numbers.
+type FieldDiff struct {
+	Path   string        `json:"path"`
+	Values []interface{} `json:"values"`
+	Delta  *float64      `json:"delta,omitempty"`
+}
+
+// HistoryComparison represents history entries compared side by side
+type HistoryComparison struct {
+	Entries         []HistoryEntry `json:"entries"`
+	SameCalculator  bool           `json:"sameCalculator"`
+	Differences     []FieldDiff    `json:"differences"`
+	UnchangedFields int            `json:"unchangedFields"`
+}
+
+// ExplanationStep represents one step in the derivation of a calculation result
+type ExplanationStep struct {
+	Step        int                `json:"step"`
+	Description string             `json:"description"`
+	Formula     string             `json:"formula,omitempty"`
+	Values      map[string]float64 `json:"values,omitempty"`
+	Result      float64            `json:"result"`
 }
 
 // APIResponse represents a standard API response
@@ -61,6 +957,25 @@ type APIResponse struct {
 	RequestID string      `json:"requestId,omitempty"`
 }
 
+// CalculatorInfo describes a registered calculator and its request/response JSON Schemas
+type CalculatorInfo struct {
+	Name           string                 `json:"name"`
+	Title          string                 `json:"title"`
+	Description    string                 `json:"description"`
+	Version        string                 `json:"version"`
+	Group          string                 `json:"group,omitempty"`
+	Method         string                 `json:"method"`
+	Path           string                 `json:"path"`
+	RequestSchema  map[string]interface{} `json:"requestSchema"`
+	ResponseSchema map[string]interface{} `json:"responseSchema"`
+}
+
+// CalculatorsResponse represents the calculator discovery response
+type CalculatorsResponse struct {
+	Calculators []CalculatorInfo `json:"calculators"`
+	Total       int              `json:"total"`
+}
+
 // HealthResponse represents a health check response
 type HealthResponse struct {
 	Status    string    `json:"status"`
@@ -72,8 +987,15 @@ type HealthResponse struct {
 
 // ServiceInfo represents service information
 type ServiceInfo struct {
-	Service     string   `json:"service"`
-	Version     string   `json:"version"`
-	Description string   `json:"description"`
-	Endpoints   []string `json:"endpoints"`
+	Service     string     `json:"service"`
+	Version     string     `json:"version"`
+	Description string     `json:"description"`
+	Endpoints   []string   `json:"endpoints"`
+	MarketData  DataSource `json:"marketData"`
+}
+
+// DataSource describes the dataset behind backtests
+type DataSource struct {
+	Source       string `json:"source"`
+	Illustrative bool   `json:"illustrative"`
 }
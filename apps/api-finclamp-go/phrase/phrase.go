@@ -0,0 +1,288 @@
+package phrase
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Query is a calculator request extracted from a phrase. Missing lists the
+// required inputs the phrase did not supply, Unreadable the numbers it wrote in
+// a form that cannot be read without guessing, such as "1.5e6", and
+// Assumptions explains any reading that was not literal, such as rounding a
+// term up to whole years. A query with Missing or Unreadable set is incomplete.
+type Query struct {
+	Calculator    string
+	Request       map[string]interface{}
+	Missing       []string
+	Unreadable    []string
+	Clarification string
+	Assumptions   []string
+}
+
+// ErrNoCalculator is returned when no calculator's keywords appear in a phrase
+var ErrNoCalculator = errors.New("could not tell which calculator the query is for")
+
+// Examples are phrases the parser understands, one per supported calculator
+var Examples = []string{
+	"EMI for 50 lakh at 8.5% for 20 years",
+	"SIP 10k monthly 12% 15 years",
+	"FD of 2 lakh at 7% for 5 years",
+	"invest 1 lakh at 12% for 10 years, high risk",
+	"tip 15% on 2400 split 3 ways",
+	"25% off 4999 plus 18% gst",
+}
+
+// hints describe how to supply each input in a follow-up phrase
+var hints = map[string]string{
+	"principal":       `the amount, e.g. "50 lakh"`,
+	"rate":            `the interest rate, e.g. "at 8.5%"`,
+	"term":            `the term, e.g. "for 20 years"`,
+	"amount":          `the amount, e.g. "10k monthly" or "2 lakh"`,
+	"expectedReturn":  `the expected return, e.g. "12%"`,
+	"billAmount":      `the bill amount, e.g. "on 2400"`,
+	"originalPrice":   `the price, e.g. "4999"`,
+	"discountPercent": `the discount, e.g. "25% off"`,
+}
+
+// intent recognises phrases for one calculator and builds its request
+type intent struct {
+	calculator string
+	matches    func(words map[string]bool, tokens []token) bool
+	build      func(b *builder)
+}
+
+// intents are tried in order, so more specific phrasings come first
+var intents = []intent{
+	{calculator: "tip", matches: hasWord("tip", "tips", "tipping"), build: buildTip},
+	{calculator: "discount", matches: hasWord("discount", "off", "sale"), build: buildDiscount},
+	{calculator: "loan", matches: hasWord("emi", "loan", "mortgage", "borrow"), build: buildLoan},
+	{calculator: "savings", matches: isSavings, build: buildSavings},
+	{calculator: "investment", matches: hasWord("invest", "investment", "lumpsum", "lump", "returns", "mutual"), build: buildInvestment},
+}
+
+// Parse extracts a calculator request from a natural-language phrase
+func Parse(text string) (Query, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	tokens := tokenize(text)
+	w := words(text)
+
+	for _, in := range intents {
+		if !in.matches(w, tokens) {
+			continue
+		}
+
+		b := &builder{
+			words:  w,
+			tokens: tokens,
+			used:   make([]bool, len(tokens)),
+			query:  Query{Calculator: in.calculator, Request: make(map[string]interface{})},
+		}
+		in.build(b)
+
+		for i, tok := range tokens {
+			switch {
+			case tok.kind == unreadable:
+				b.query.Unreadable = append(b.query.Unreadable, tok.raw)
+			case !b.used[i]:
+				b.assume(fmt.Sprintf("ignored %q: unclear what it refers to", tok.raw))
+			}
+		}
+
+		var clarifications []string
+		if len(b.query.Unreadable) > 0 {
+			clarifications = append(clarifications, fmt.Sprintf(`Could not read %s; write numbers as digits with an optional unit, e.g. "15 lakh" or "1500000"`,
+				strings.Join(quoteAll(b.query.Unreadable), ", ")))
+		}
+		if len(b.query.Missing) > 0 {
+			needed := make([]string, len(b.query.Missing))
+			for i, field := range b.query.Missing {
+				needed[i] = hints[field]
+			}
+			clarifications = append(clarifications, "Please include "+strings.Join(needed, " and "))
+		}
+		b.query.Clarification = strings.Join(clarifications, ". ")
+		return b.query, nil
+	}
+
+	return Query{}, ErrNoCalculator
+}
+
+// Complete reports whether the query has everything its calculator needs
+func (q Query) Complete() bool {
+	return len(q.Missing) == 0 && len(q.Unreadable) == 0
+}
+
+func quoteAll(values []string) []string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = strconv.Quote(value)
+	}
+	return quoted
+}
+
+func hasWord(keywords ...string) func(map[string]bool, []token) bool {
+	return func(words map[string]bool, _ []token) bool {
+		for _, keyword := range keywords {
+			if words[keyword] {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// isSavings matches deposits and SIPs, and investing a monthly amount
+func isSavings(words map[string]bool, tokens []token) bool {
+	if hasWord("sip", "rd", "recurring", "fd", "deposit", "savings", "save")(words, tokens) {
+		return true
+	}
+	for _, tok := range tokens {
+		if tok.kind == amount && tok.monthly {
+			return hasWord("invest", "investment", "mutual")(words, tokens)
+		}
+	}
+	return false
+}
+
+func buildTip(b *builder) {
+	b.require("billAmount", isAmount)
+	if tok, ok := b.take(isKind(percent)); ok {
+		b.query.Request["tipPercent"] = tok.value
+	}
+	if tok, ok := b.take(isKind(count)); ok {
+		b.query.Request["people"] = int(tok.value)
+	}
+}
+
+func buildDiscount(b *builder) {
+	if tok, ok := b.take(isTax); ok {
+		b.query.Request["taxPercent"] = tok.value
+	}
+	b.require("discountPercent", isKind(percent))
+	if tok, ok := b.take(isKind(percent)); ok {
+		b.query.Request["additionalDiscount"] = tok.value
+	}
+	b.require("originalPrice", isAmount)
+}
+
+func buildLoan(b *builder) {
+	b.require("principal", isAmount)
+	b.require("rate", isKind(percent))
+	b.years("term")
+}
+
+func buildSavings(b *builder) {
+	monthly, hasMonthly := b.take(func(tok token) bool { return tok.kind == amount && tok.monthly })
+	lumpSum, hasLumpSum := b.take(isAmount)
+
+	// A lone SIP or RD amount is a monthly contribution even when not marked as one
+	if !hasMonthly && hasLumpSum && (b.words["sip"] || b.words["rd"] || b.words["recurring"]) {
+		monthly, hasMonthly, hasLumpSum = lumpSum, true, false
+		b.assume(fmt.Sprintf("%q taken as a monthly contribution", lumpSum.raw))
+	}
+
+	switch {
+	case hasMonthly || hasLumpSum:
+		if hasMonthly {
+			b.query.Request["monthlyContribution"] = monthly.value
+		}
+		if hasLumpSum {
+			b.query.Request["principal"] = lumpSum.value
+		}
+	default:
+		b.query.Missing = append(b.query.Missing, "amount")
+	}
+	b.require("rate", isKind(percent))
+	b.years("term")
+}
+
+func buildInvestment(b *builder) {
+	b.require("amount", isAmount)
+	b.require("expectedReturn", isKind(percent))
+	b.years("term")
+	for _, level := range []string{"low", "medium", "high"} {
+		if b.words[level] {
+			b.query.Request["riskLevel"] = level
+			break
+		}
+	}
+}
+
+// builder accumulates a Query while an intent consumes tokens
+type builder struct {
+	words  map[string]bool
+	tokens []token
+	used   []bool
+	query  Query
+}
+
+// take returns the first unused token that satisfies match and marks it used
+func (b *builder) take(match func(token) bool) (token, bool) {
+	for i, tok := range b.tokens {
+		if !b.used[i] && match(tok) {
+			b.used[i] = true
+			return tok, true
+		}
+	}
+	return token{}, false
+}
+
+// require sets field from the first unused token that satisfies match, or
+// records it as missing
+func (b *builder) require(field string, match func(token) bool) {
+	tok, ok := b.take(match)
+	if !ok {
+		b.query.Missing = append(b.query.Missing, field)
+		return
+	}
+	b.query.Request[field] = tok.value
+}
+
+// years sets field to a duration in whole years, rounding part years up
+func (b *builder) years(field string) {
+	tok, ok := b.take(isKind(duration))
+	if !ok {
+		b.query.Missing = append(b.query.Missing, field)
+		return
+	}
+	years := int(math.Ceil(float64(tok.months) / 12))
+	if tok.months%12 != 0 {
+		b.assume(fmt.Sprintf("%q rounded up to %d whole years", tok.raw, years))
+	}
+	b.query.Request[field] = years
+}
+
+func (b *builder) assume(note string) {
+	b.query.Assumptions = append(b.query.Assumptions, note)
+}
+
+func isKind(k kind) func(token) bool {
+	return func(tok token) bool { return tok.kind == k }
+}
+
+func isAmount(tok token) bool {
+	return tok.kind == amount
+}
+
+// isTax matches a percentage labelled as tax, e.g. "18% gst" or "gst of 18%"
+func isTax(tok token) bool {
+	if tok.kind != percent {
+		return false
+	}
+	switch tok.after {
+	case "gst", "tax", "vat":
+		return true
+	}
+	return containsAny(lastWords(tok.context, 2), "gst", "tax", "vat")
+}
+
+func lastWords(text string, n int) string {
+	fields := strings.Fields(text)
+	if len(fields) > n {
+		fields = fields[len(fields)-n:]
+	}
+	return strings.Join(fields, " ")
+}
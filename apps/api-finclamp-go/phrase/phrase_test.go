@@ -0,0 +1,62 @@
+package phrase
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRejectsNumbersItCannotReadWhole(t *testing.T) {
+	cases := []struct {
+		query      string
+		unreadable []string
+		missing    []string
+	}{
+		{"emi of 1.5e6 at 8% for 10 years", []string{"1.5e6"}, []string{"principal"}},
+		{"emi of 1e308 crore at 8% for 10 years", []string{"1e308"}, []string{"principal"}},
+		{"emi of 1.2.3 lakh at 8% for 2 years", []string{"1.2.3"}, []string{"principal"}},
+		{"sip 5000x at 12% for 10 years", []string{"5000x"}, []string{"amount"}},
+	}
+
+	for _, tc := range cases {
+		query, err := Parse(tc.query)
+		if err != nil {
+			t.Fatalf("%q: %v", tc.query, err)
+		}
+		if query.Complete() {
+			t.Errorf("%q: parsed as complete with request %v", tc.query, query.Request)
+		}
+		if !reflect.DeepEqual(query.Unreadable, tc.unreadable) {
+			t.Errorf("%q: unreadable %q, want %q", tc.query, query.Unreadable, tc.unreadable)
+		}
+		if !reflect.DeepEqual(query.Missing, tc.missing) {
+			t.Errorf("%q: missing %q, want %q", tc.query, query.Missing, tc.missing)
+		}
+		if query.Clarification == "" {
+			t.Errorf("%q: no clarification", tc.query)
+		}
+	}
+}
+
+func TestParseReadsUnitsWrittenAgainstNumbers(t *testing.T) {
+	cases := []struct {
+		query   string
+		request map[string]interface{}
+	}{
+		{"emi for 50l at 8.5% for 20yrs", map[string]interface{}{"principal": 5e6, "rate": 8.5, "term": 20}},
+		{"sip 5000/month at 12pc for 10 years", map[string]interface{}{"monthlyContribution": 5000.0, "rate": 12.0, "term": 10}},
+		{"fd of 2 lakh at 7% for 60mths", map[string]interface{}{"principal": 2e5, "rate": 7.0, "term": 5}},
+	}
+
+	for _, tc := range cases {
+		query, err := Parse(tc.query)
+		if err != nil {
+			t.Fatalf("%q: %v", tc.query, err)
+		}
+		if !query.Complete() {
+			t.Errorf("%q: incomplete, missing %q, unreadable %q", tc.query, query.Missing, query.Unreadable)
+		}
+		if !reflect.DeepEqual(query.Request, tc.request) {
+			t.Errorf("%q: request %v, want %v", tc.query, query.Request, tc.request)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package phrase
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type kind int
+
+const (
+	amount kind = iota
+	percent
+	duration
+	count
+	// unreadable is a number the phrase runs into something that cannot be read
+	// as a unit, such as "1.5e6", or one too large to represent
+	unreadable
+)
+
+// token is a number found in a phrase together with what it was read as
+type token struct {
+	kind    kind
+	value   float64
+	months  int    // durations only
+	monthly bool   // amounts only: a per-month amount such as "10k monthly"
+	context string // words between the previous number and this one
+	after   string // the word following the number
+	raw     string
+}
+
+// multipliers maps number suffixes to their value, including the Indian lakh
+// and crore
+var multipliers = map[string]float64{
+	"k": 1e3, "thousand": 1e3,
+	"l": 1e5, "lac": 1e5, "lacs": 1e5, "lakh": 1e5, "lakhs": 1e5,
+	"cr": 1e7, "crore": 1e7, "crores": 1e7,
+	"m": 1e6, "mn": 1e6, "million": 1e6,
+	"bn": 1e9, "billion": 1e9,
+}
+
+var (
+	yearWords    = map[string]bool{"y": true, "yr": true, "yrs": true, "year": true, "years": true}
+	monthWords   = map[string]bool{"mo": true, "mos": true, "mth": true, "mths": true, "month": true, "months": true}
+	percentWords = map[string]bool{"%": true, "percent": true, "pc": true}
+	countWords   = map[string]bool{"people": true, "persons": true, "ways": true, "friends": true}
+	perWords     = map[string]bool{"per": true, "a": true, "every": true, "each": true, "/": true}
+)
+
+var numberPattern = regexp.MustCompile(`(₹|rs\.?|inr|\$|usd)?\s*(\d+(?:,\d+)*(?:\.\d+)?)`)
+
+// ordinalSuffixes may follow a number directly without making it unreadable,
+// as in "1st"
+var ordinalSuffixes = map[string]bool{"st": true, "nd": true, "rd": true, "th": true}
+
+// maxDurationMonths keeps durations within int; anything this long is rejected
+// by the calculators' own term limits
+const maxDurationMonths = math.MaxInt32
+
+// tokenize finds the numbers in a lower-cased phrase and classifies each as an
+// amount, a percentage, a duration or a head count from the words around it
+func tokenize(text string) []token {
+	var tokens []token
+	previousEnd := 0
+	for _, match := range numberPattern.FindAllStringSubmatchIndex(text, -1) {
+		if match[0] < previousEnd {
+			continue
+		}
+		tok := token{kind: amount, context: text[previousEnd:match[0]]}
+		value, err := strconv.ParseFloat(strings.ReplaceAll(text[match[4]:match[5]], ",", ""), 64)
+		if err != nil || runsOn(text, match[1]) {
+			end := match[1]
+			for end < len(text) && text[end] != ' ' {
+				end++
+			}
+			tok.kind, tok.raw = unreadable, strings.TrimSpace(text[match[0]:end])
+			previousEnd = end
+			tokens = append(tokens, tok)
+			continue
+		}
+
+		hasCurrency := match[2] >= 0
+		end := match[1]
+
+		word, next := nextWord(text, end)
+		multiplied := false
+		if multiplier, ok := multipliers[word]; ok {
+			value, end, multiplied = value*multiplier, next, true
+			word, next = nextWord(text, end)
+		}
+		if math.IsInf(value, 0) {
+			tok.kind, tok.raw = unreadable, strings.TrimSpace(text[match[0]:end])
+			previousEnd = end
+			tokens = append(tokens, tok)
+			continue
+		}
+
+		switch {
+		case percentWords[word]:
+			tok.kind, end = percent, next
+		case !hasCurrency && !multiplied && yearWords[word]:
+			tok.kind, tok.months, end = duration, int(math.Min(math.Round(value*12), maxDurationMonths)), next
+		case !hasCurrency && !multiplied && monthWords[word]:
+			tok.kind, tok.months, end = duration, int(math.Min(math.Round(value), maxDurationMonths)), next
+		case !hasCurrency && !multiplied && countWords[word]:
+			tok.kind, end = count, next
+		case !hasCurrency && !multiplied && value <= 50 && lastWord(tok.context) == "at":
+			// "at 8.5" reads as a rate even without a percent sign
+			tok.kind = percent
+		case word == "monthly" || word == "pm":
+			tok.monthly, end = true, next
+		case perWords[word]:
+			if unit, after := nextWord(text, next); monthWords[unit] {
+				tok.monthly, end = true, after
+			}
+		default:
+			tok.monthly = strings.Contains(tok.context, "monthly") || strings.Contains(tok.context, "per month")
+		}
+
+		tok.value = value
+		tok.after, _ = nextWord(text, end)
+		tok.raw = strings.TrimSpace(text[match[0]:end])
+		previousEnd = end
+		tokens = append(tokens, tok)
+	}
+	return tokens
+}
+
+// runsOn reports whether the number ending at offset runs straight into text
+// that is not a unit it can take, such as the "e6" of "1.5e6" or the ".3" of
+// "1.2.3", so that reading the digits alone would truncate it
+func runsOn(text string, offset int) bool {
+	if offset >= len(text) {
+		return false
+	}
+	if c := text[offset]; (c == '.' || c == ',') && offset+1 < len(text) && text[offset+1] >= '0' && text[offset+1] <= '9' {
+		return true
+	}
+	word, _ := nextWord(text, offset)
+	if word == "" || text[offset] == ' ' {
+		return false
+	}
+	_, multiplier := multipliers[word]
+	return !multiplier && !yearWords[word] && !monthWords[word] && !percentWords[word] &&
+		!countWords[word] && !perWords[word] && !ordinalSuffixes[word] && word != "monthly" && word != "pm"
+}
+
+// nextWord returns the word starting at or after offset (a run of letters, or a
+// single "%", "/" or "@") and the offset just past it
+func nextWord(text string, offset int) (string, int) {
+	start := offset
+	for start < len(text) && text[start] == ' ' {
+		start++
+	}
+	if start < len(text) && (text[start] == '%' || text[start] == '/' || text[start] == '@') {
+		return text[start : start+1], start + 1
+	}
+	end := start
+	for end < len(text) && text[end] >= 'a' && text[end] <= 'z' {
+		end++
+	}
+	return text[start:end], end
+}
+
+func lastWord(text string) string {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return ""
+	}
+	last := fields[len(fields)-1]
+	if strings.HasSuffix(last, "@") {
+		return "at"
+	}
+	return last
+}
+
+// words splits a phrase into lower-case words for keyword matching
+func words(text string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	}) {
+		set[word] = true
+	}
+	return set
+}
+
+func containsAny(text string, needles ...string) bool {
+	for _, needle := range needles {
+		if strings.Contains(text, needle) {
+			return true
+		}
+	}
+	return false
+}
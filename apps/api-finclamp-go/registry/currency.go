@@ -0,0 +1,109 @@
+package registry
+
+import (
+	"encoding/json"
+	"finclamp-api/config"
+	"finclamp-api/currency"
+	"finclamp-api/models"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var currencyOptionsType = reflect.TypeOf(models.CurrencyOptions{})
+
+// resolveCurrency validates the requested currency and locale, falling back to
+// the configured default currency
+func resolveCurrency(opts models.CurrencyOptions) (currency.Currency, error) {
+	code := opts.Currency
+	if code == "" {
+		code = config.AppConfig.DefaultCurrency
+	}
+
+	cur, ok := currency.Lookup(code)
+	if !ok {
+		return currency.Currency{}, &InputError{Message: "Invalid currency", Detail: "Available currencies: " + strings.Join(currency.Codes(), ", ")}
+	}
+	if opts.Locale != "" && !currency.SupportsLocale(opts.Locale) {
+		return currency.Currency{}, &InputError{Message: "Invalid locale", Detail: "Available locales: " + strings.Join(currency.Locales(), ", ")}
+	}
+	return cur, nil
+}
+
+// withCurrency adds the currency code to a response and, when requested, a
+// "formatted" map from each monetary field's JSON path to its formatted value
+func withCurrency(response interface{}, cur currency.Currency, opts models.CurrencyOptions) (map[string]interface{}, error) {
+	raw, err := json.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+
+	var decorated map[string]interface{}
+	if err := json.Unmarshal(raw, &decorated); err != nil {
+		return nil, err
+	}
+
+	decorated["currency"] = cur.Code
+	if opts.Format {
+		formatted := make(map[string]string)
+		collectMoney(reflect.ValueOf(response), "", false, func(path string, amount float64) {
+			formatted[path] = currency.Format(amount, cur, opts.Locale)
+		})
+		if len(formatted) > 0 {
+			decorated["formatted"] = formatted
+		}
+	}
+
+	return decorated, nil
+}
+
+// collectMoney walks a value and calls visit for every float reached through a
+// field tagged money:"true", passing its JSON path (e.g. "offers[0].totalCost")
+func collectMoney(v reflect.Value, path string, money bool, visit func(path string, amount float64)) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			collectMoney(v.Elem(), path, money, visit)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "-" {
+				continue
+			}
+			fieldPath := path
+			if !field.Anonymous || name != "" {
+				if name == "" {
+					name = field.Name
+				}
+				fieldPath = joinPath(path, name)
+			}
+			collectMoney(v.Field(i), fieldPath, field.Tag.Get("money") == "true", visit)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			collectMoney(v.Index(i), path+"["+strconv.Itoa(i)+"]", money, visit)
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			collectMoney(v.MapIndex(key), joinPath(path, key.String()), money, visit)
+		}
+	case reflect.Float32, reflect.Float64:
+		if money {
+			visit(path, v.Float())
+		}
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
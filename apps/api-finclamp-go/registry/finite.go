@@ -0,0 +1,81 @@
+package registry
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// checkFinite returns an *InputError naming the first NaN or infinite number in
+// a calculator's result. Such values come from inputs too extreme to calculate
+// with, and JSON cannot encode them.
+func checkFinite(result interface{}) error {
+	path, found := findNonFinite(reflect.ValueOf(result), "")
+	if !found {
+		return nil
+	}
+	if path == "" {
+		path = "the result"
+	}
+	return &InputError{
+		Message: "Result out of range",
+		Detail:  fmt.Sprintf("%s is not a finite number; use smaller amounts, rates or terms", path),
+	}
+}
+
+// findNonFinite walks a value and returns the JSON path of the first NaN or
+// infinite float in it. Map keys are visited in sorted order so the path
+// reported for a given result is always the same.
+func findNonFinite(v reflect.Value, path string) (string, bool) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			return findNonFinite(v.Elem(), path)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "-" {
+				continue
+			}
+			fieldPath := path
+			if !field.Anonymous || name != "" {
+				if name == "" {
+					name = field.Name
+				}
+				fieldPath = joinPath(path, name)
+			}
+			if found, ok := findNonFinite(v.Field(i), fieldPath); ok {
+				return found, true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if found, ok := findNonFinite(v.Index(i), path+"["+strconv.Itoa(i)+"]"); ok {
+				return found, true
+			}
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		for _, key := range keys {
+			if found, ok := findNonFinite(v.MapIndex(key), joinPath(path, fmt.Sprint(key))); ok {
+				return found, true
+			}
+		}
+	case reflect.Float32, reflect.Float64:
+		if f := v.Float(); math.IsNaN(f) || math.IsInf(f, 0) {
+			return path, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,223 @@
+package registry
+
+import (
+	"errors"
+	"finclamp-api/explain"
+	"finclamp-api/history"
+	"finclamp-api/models"
+	"finclamp-api/utils"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// Meta describes a calculator for routing and discovery
+type Meta struct {
+	Name        string // route segment under /calculate, e.g. "loan"
+	Title       string // used in success messages, e.g. "Loan calculation"
+	Description string
+	Version     string
+	Group       string // optional discovery group, e.g. "everyday"
+}
+
+// Calculator is a registered calculator that can describe and serve itself
+type Calculator interface {
+	Meta() Meta
+	Info() models.CalculatorInfo
+	Handle(c *gin.Context)
+	Run(body []byte, enabled bool) (interface{}, error)
+}
+
+// InputError reports a request that passed binding but cannot be calculated.
+// Message is a short summary and Detail explains how to correct the input; they
+// map onto the message and error fields of an API error response.
+type InputError struct {
+	Message string
+	Detail  string
+}
+
+func (e *InputError) Error() string {
+	return e.Message + ": " + e.Detail
+}
+
+type definition[Req any, Resp any] struct {
+	meta    Meta
+	compute func(Req, *explain.Trace) (Resp, error)
+}
+
+// Define declares a calculator from its metadata and compute function. The
+// request type's json and binding tags drive both validation and the published
+// request schema.
+func Define[Req any, Resp any](meta Meta, compute func(Req, *explain.Trace) (Resp, error)) Calculator {
+	return &definition[Req, Resp]{meta: meta, compute: compute}
+}
+
+// Infallible adapts a compute function that cannot fail to the signature Define expects
+func Infallible[Req any, Resp any](compute func(Req, *explain.Trace) Resp) func(Req, *explain.Trace) (Resp, error) {
+	return func(req Req, t *explain.Trace) (Resp, error) {
+		return compute(req, t), nil
+	}
+}
+
+func (d *definition[Req, Resp]) Meta() Meta {
+	return d.meta
+}
+
+func (d *definition[Req, Resp]) Info() models.CalculatorInfo {
+	requestSchema := Schema(reflect.TypeOf((*Req)(nil)).Elem())
+	requestProperties := requestSchema["properties"].(map[string]interface{})
+	for name, property := range typeSchema(currencyOptionsType)["properties"].(map[string]interface{}) {
+		if _, exists := requestProperties[name]; !exists {
+			requestProperties[name] = property
+		}
+	}
+
+	responseSchema := Schema(reflect.TypeOf((*Resp)(nil)).Elem())
+	responseProperties := responseSchema["properties"].(map[string]interface{})
+	responseProperties["currency"] = map[string]interface{}{"type": "string"}
+	responseProperties["formatted"] = map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": map[string]interface{}{"type": "string"},
+	}
+
+	return models.CalculatorInfo{
+		Name:           d.meta.Name,
+		Title:          d.meta.Title,
+		Description:    d.meta.Description,
+		Version:        d.meta.Version,
+		Group:          d.meta.Group,
+		Method:         http.MethodPost,
+		Path:           Path(d.meta.Name),
+		RequestSchema:  requestSchema,
+		ResponseSchema: responseSchema,
+	}
+}
+
+func (d *definition[Req, Resp]) Handle(c *gin.Context) {
+	var req Req
+	var opts models.CurrencyOptions
+
+	// The body is bound twice: once into the calculator's request and once into
+	// the currency options every calculator accepts
+	if err := c.ShouldBindBodyWith(&req, binding.JSON); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+	if err := c.ShouldBindBodyWith(&opts, binding.JSON); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	cur, err := resolveCurrency(opts)
+	if err != nil {
+		d.sendError(c, err)
+		return
+	}
+
+	enabled, _ := strconv.ParseBool(c.Query("explain"))
+	response, err := d.calculate(req, enabled)
+	if err != nil {
+		d.sendError(c, err)
+		return
+	}
+	RecordHistory(c, d.meta, req, response)
+
+	c.Header("X-Calculator-Version", d.meta.Version)
+	if opts.Currency == "" && !opts.Format {
+		utils.SendSuccessResponse(c, response, d.meta.Title+" completed successfully")
+		return
+	}
+
+	decorated, err := withCurrency(response, cur, opts)
+	if err != nil {
+		d.sendError(c, err)
+		return
+	}
+	utils.SendSuccessResponse(c, decorated, d.meta.Title+" completed successfully")
+}
+
+// Run binds and validates a JSON request body and computes the result, for
+// callers that build requests themselves rather than receiving them over HTTP.
+// Binding failures are reported as an *InputError.
+func (d *definition[Req, Resp]) Run(body []byte, enabled bool) (interface{}, error) {
+	var req Req
+	if err := binding.JSON.BindBody(body, &req); err != nil {
+		return nil, &InputError{Message: "Invalid request data", Detail: err.Error()}
+	}
+	return d.calculate(req, enabled)
+}
+
+// calculate computes the response to a bound request, rejecting results that
+// are not finite numbers as an *InputError
+func (d *definition[Req, Resp]) calculate(req Req, enabled bool) (Resp, error) {
+	response, err := d.compute(req, explain.New(enabled))
+	if err != nil {
+		return response, err
+	}
+	if err := checkFinite(response); err != nil {
+		var zero Resp
+		return zero, err
+	}
+	return response, nil
+}
+
+func (d *definition[Req, Resp]) sendError(c *gin.Context, err error) {
+	var inputErr *InputError
+	if errors.As(err, &inputErr) {
+		utils.SendErrorResponse(c, http.StatusBadRequest, inputErr.Message, inputErr.Detail)
+		return
+	}
+	utils.SendErrorResponse(c, http.StatusInternalServerError, "Calculation failed", err.Error())
+}
+
+// RecordHistory saves a calculation to the signed-in user's history, if there
+// is one. A failure to record is logged rather than failing a calculation that
+// succeeded.
+func RecordHistory(c *gin.Context, meta Meta, request, response interface{}) {
+	userID := c.GetString("UserID")
+	if userID == "" {
+		return
+	}
+	if _, err := history.Record(userID, meta.Name, meta.Version, request, response); err != nil {
+		log.Printf("Failed to record %s calculation history: %v", meta.Name, err)
+	}
+}
+
+var (
+	calculators []Calculator
+	byName      = make(map[string]Calculator)
+)
+
+// Register adds calculators to the registry. It panics on duplicate names since
+// that is a programming error caught at startup.
+func Register(calcs ...Calculator) {
+	for _, calc := range calcs {
+		name := calc.Meta().Name
+		if _, exists := byName[name]; exists {
+			panic(fmt.Sprintf("registry: calculator %q registered twice", name))
+		}
+		byName[name] = calc
+		calculators = append(calculators, calc)
+	}
+}
+
+// All returns registered calculators in registration order
+func All() []Calculator {
+	return calculators
+}
+
+// Lookup returns the calculator registered under name
+func Lookup(name string) (Calculator, bool) {
+	calc, ok := byName[name]
+	return calc, ok
+}
+
+// Path returns the API path a calculator is served on
+func Path(name string) string {
+	return "/api/v1/calculate/" + name
+}
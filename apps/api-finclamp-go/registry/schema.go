@@ -0,0 +1,170 @@
+package registry
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const schemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Schema builds a JSON Schema document for a Go type from its json and binding
+// tags, so the published schema always matches what the handler validates
+func Schema(t reflect.Type) map[string]interface{} {
+	schema := typeSchema(t)
+	schema["$schema"] = schemaDialect
+	return schema
+}
+
+func typeSchema(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return structSchema(t)
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+func structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	required := []string{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		// Embedded structs without a json name are flattened, as encoding/json does
+		if field.Anonymous && field.Tag.Get("json") == "" && field.Type.Kind() == reflect.Struct {
+			embedded := structSchema(field.Type)
+			for name, property := range embedded["properties"].(map[string]interface{}) {
+				properties[name] = property
+			}
+			if names, ok := embedded["required"].([]string); ok {
+				required = append(required, names...)
+			}
+			continue
+		}
+
+		name := field.Name
+		if tag := field.Tag.Get("json"); tag != "" {
+			name = strings.Split(tag, ",")[0]
+			if name == "-" {
+				continue
+			}
+		}
+
+		property := typeSchema(field.Type)
+		if field.Type.Kind() == reflect.Ptr {
+			if schemaType, ok := property["type"].(string); ok {
+				property["type"] = []string{schemaType, "null"}
+			}
+		}
+		if applyBinding(property, field.Tag.Get("binding")) {
+			required = append(required, name)
+		}
+		properties[name] = property
+	}
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// applyBinding translates validator rules into schema keywords and reports
+// whether the field is required. Rules after "dive" apply to array items or
+// map values.
+func applyBinding(property map[string]interface{}, binding string) bool {
+	if binding == "" {
+		return false
+	}
+
+	required, dived := false, false
+	target := property
+	for _, rule := range strings.Split(binding, ",") {
+		key, value, _ := strings.Cut(rule, "=")
+
+		switch key {
+		case "required":
+			required = required || !dived
+		case "dive":
+			if items, ok := target["items"].(map[string]interface{}); ok {
+				target, dived = items, true
+			} else if values, ok := target["additionalProperties"].(map[string]interface{}); ok {
+				target, dived = values, true
+			}
+		case "gt":
+			target["exclusiveMinimum"] = number(value)
+		case "gte":
+			target["minimum"] = number(value)
+		case "lt":
+			target["exclusiveMaximum"] = number(value)
+		case "lte":
+			target["maximum"] = number(value)
+		case "min", "max":
+			target[lengthKeyword(target["type"], key)] = number(value)
+		case "oneof":
+			enum := []interface{}{}
+			for _, option := range strings.Fields(value) {
+				enum = append(enum, option)
+			}
+			target["enum"] = enum
+		}
+	}
+
+	return required
+}
+
+// lengthKeyword picks the schema keyword a min/max rule maps to for a given type
+func lengthKeyword(schemaType interface{}, rule string) string {
+	switch schemaType {
+	case "array":
+		return rule + "Items"
+	case "string":
+		return rule + "Length"
+	case "object":
+		return rule + "Properties"
+	}
+
+	if rule == "min" {
+		return "minimum"
+	}
+	return "maximum"
+}
+
+func number(value string) interface{} {
+	if n, err := strconv.ParseFloat(value, 64); err == nil {
+		return n
+	}
+	return value
+}
@@ -1,7 +1,10 @@
 package routes
 
 import (
+	_ "finclamp-api/calculators" // registers calculators
 	"finclamp-api/handlers"
+	"finclamp-api/middleware"
+	"finclamp-api/registry"
 	"finclamp-api/server"
 	"log"
 )
@@ -14,12 +17,29 @@ func RegisterRoutes() {
 	server.API.GET("/", handlers.GetServiceInfo)
 	server.API.GET("/health", handlers.HealthCheck)
 	
-	// Calculation routes
+	// Calculator discovery routes
+	server.API.GET("/calculators", handlers.ListCalculators)
+	server.API.GET("/calculators/:name", handlers.GetCalculator)
+	
+	// Currency routes
+	server.API.GET("/currency/rates", handlers.GetExchangeRates)
+	
+	// Calculation history routes, for signed-in users
+	hist := server.API.Group("/history", middleware.RequireUser())
+	{
+		hist.GET("", handlers.ListHistory)
+		hist.POST("/compare", handlers.CompareHistory)
+		hist.GET("/:id", handlers.GetHistoryEntry)
+		hist.DELETE("/:id", handlers.DeleteHistoryEntry)
+	}
+	
+	// Calculation routes, one per registered calculator
 	calc := server.API.Group("/calculate")
 	{
-		calc.POST("/loan", handlers.CalculateLoan)
-		calc.POST("/savings", handlers.CalculateSavings)
-		calc.POST("/investment", handlers.CalculateInvestment)
+		calc.POST("/parse", handlers.ParseQuery)
+		for _, calculator := range registry.All() {
+			calc.POST("/"+calculator.Meta().Name, calculator.Handle)
+		}
 	}
 	
 	log.Println("Routes registered successfully")
@@ -59,6 +59,9 @@ func InitServer() {
 		MaxAge:           12 * time.Hour,
 	}))
 
+	// Authenticate after CORS, so browsers can read the rejection of a bad token
+	Router.Use(middleware.Authenticate())
+
 	// Create API group
 	API = Router.Group("/api/v1")
 	
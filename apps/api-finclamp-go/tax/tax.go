@@ -0,0 +1,185 @@
+package tax
+
+import (
+	"finclamp-api/models"
+	"finclamp-api/utils"
+	"math"
+	"sort"
+)
+
+// Slab is a band of taxable income taxed at Rate percent. Upper is zero for
+// the top band.
+type Slab struct {
+	Lower float64
+	Upper float64
+	Rate  float64
+}
+
+// SurchargeBand applies Rate percent of tax as surcharge on income above Above
+type SurchargeBand struct {
+	Above float64
+	Rate  float64
+}
+
+// Regime holds the income-tax rules of one Indian tax regime for resident
+// individuals below 60
+type Regime struct {
+	Name              string
+	FinancialYear     string
+	Slabs             []Slab
+	StandardDeduction float64
+	// Section 87A: tax up to MaxRebate is rebated when taxable income is at
+	// most RebateLimit. With MarginalRelief, tax just above the limit is
+	// capped at the income above it.
+	RebateLimit    float64
+	MaxRebate      float64
+	MarginalRelief bool
+	Surcharge      []SurchargeBand
+	CessPercent    float64
+	// AllowsDeductions reports whether HRA exemption, Chapter VI-A deductions
+	// and professional tax reduce taxable income
+	AllowsDeductions bool
+}
+
+// Regimes are the rules for FY 2025-26 (assessment year 2026-27)
+var Regimes = map[string]Regime{
+	"new": {
+		Name:          "new",
+		FinancialYear: "2025-26",
+		Slabs: []Slab{
+			{0, 400000, 0},
+			{400000, 800000, 5},
+			{800000, 1200000, 10},
+			{1200000, 1600000, 15},
+			{1600000, 2000000, 20},
+			{2000000, 2400000, 25},
+			{2400000, 0, 30},
+		},
+		StandardDeduction: 75000,
+		RebateLimit:       1200000,
+		MaxRebate:         60000,
+		MarginalRelief:    true,
+		Surcharge:         []SurchargeBand{{5000000, 10}, {10000000, 15}, {20000000, 25}},
+		CessPercent:       4,
+	},
+	"old": {
+		Name:          "old",
+		FinancialYear: "2025-26",
+		Slabs: []Slab{
+			{0, 250000, 0},
+			{250000, 500000, 5},
+			{500000, 1000000, 20},
+			{1000000, 0, 30},
+		},
+		StandardDeduction: 50000,
+		RebateLimit:       500000,
+		MaxRebate:         12500,
+		Surcharge:         []SurchargeBand{{5000000, 10}, {10000000, 15}, {20000000, 25}, {50000000, 37}},
+		CessPercent:       4,
+		AllowsDeductions:  true,
+	},
+}
+
+// DefaultRegime is used when a request does not choose one
+const DefaultRegime = "new"
+
+// Names returns the available regime names, sorted
+func Names() []string {
+	names := make([]string, 0, len(Regimes))
+	for name := range Regimes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Compute returns the tax on taxable income (after deductions) with a slab by
+// slab breakdown
+func (r Regime) Compute(taxable float64) models.IncomeTaxBreakdown {
+	breakdown := models.IncomeTaxBreakdown{
+		Regime:        r.Name,
+		FinancialYear: r.FinancialYear,
+		TaxableIncome: utils.RoundToTwoDecimals(taxable),
+		Slabs:         []models.SlabTax{},
+	}
+
+	slabTax := 0.0
+	for _, slab := range r.Slabs {
+		if taxable <= slab.Lower {
+			break
+		}
+		upper := taxable
+		if slab.Upper > 0 {
+			upper = math.Min(taxable, slab.Upper)
+		}
+		tax := (upper - slab.Lower) * slab.Rate / 100
+		slabTax += tax
+		breakdown.Slabs = append(breakdown.Slabs, models.SlabTax{
+			From:   slab.Lower,
+			To:     slab.Upper,
+			Rate:   slab.Rate,
+			Income: utils.RoundToTwoDecimals(upper - slab.Lower),
+			Tax:    utils.RoundToTwoDecimals(tax),
+		})
+	}
+
+	rebate := r.rebate(taxable, slabTax)
+	surcharge := r.surcharge(taxable)
+	cess := (slabTax - rebate + surcharge) * r.CessPercent / 100
+
+	breakdown.SlabTax = utils.RoundToTwoDecimals(slabTax)
+	breakdown.Rebate = utils.RoundToTwoDecimals(rebate)
+	breakdown.Surcharge = utils.RoundToTwoDecimals(surcharge)
+	breakdown.Cess = utils.RoundToTwoDecimals(cess)
+	breakdown.TotalTax = utils.RoundToTwoDecimals(slabTax - rebate + surcharge + cess)
+	return breakdown
+}
+
+// slabTax returns the tax from the slabs alone
+func (r Regime) slabTax(taxable float64) float64 {
+	tax := 0.0
+	for _, slab := range r.Slabs {
+		if taxable <= slab.Lower {
+			break
+		}
+		upper := taxable
+		if slab.Upper > 0 {
+			upper = math.Min(taxable, slab.Upper)
+		}
+		tax += (upper - slab.Lower) * slab.Rate / 100
+	}
+	return tax
+}
+
+func (r Regime) rebate(taxable, slabTax float64) float64 {
+	if taxable <= r.RebateLimit {
+		return math.Min(slabTax, r.MaxRebate)
+	}
+	if r.MarginalRelief && slabTax > taxable-r.RebateLimit {
+		return slabTax - (taxable - r.RebateLimit)
+	}
+	return 0
+}
+
+// surcharge applies the band rate for the income, with marginal relief so that
+// crossing a band threshold never costs more than the income above it
+func (r Regime) surcharge(taxable float64) float64 {
+	rate, threshold := 0.0, 0.0
+	for _, band := range r.Surcharge {
+		if taxable > band.Above {
+			rate, threshold = band.Rate, band.Above
+		}
+	}
+	if rate == 0 {
+		return 0
+	}
+
+	tax := r.slabTax(taxable) - r.rebate(taxable, r.slabTax(taxable))
+	surcharge := tax * rate / 100
+
+	thresholdTax := r.slabTax(threshold) - r.rebate(threshold, r.slabTax(threshold)) + r.surcharge(threshold)
+	if limit := thresholdTax + (taxable - threshold) - tax; surcharge > limit {
+		return math.Max(limit, 0)
+	}
+	return surcharge
+}
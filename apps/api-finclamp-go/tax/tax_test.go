@@ -0,0 +1,97 @@
+package tax
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// Worked examples from the Finance Act 2025 rates and the Income Tax
+// Department's section 87A guidance
+func TestComputeExamples(t *testing.T) {
+	cases := []struct {
+		name      string
+		regime    string
+		taxable   float64
+		rebate    float64
+		surcharge float64
+		total     float64
+	}{
+		{"new regime at the rebate limit pays nothing", "new", 1200000, 60000, 0, 0},
+		{"new regime just above the rebate limit gets marginal relief", "new", 1210000, 51500, 0, 10400},
+		{"new regime past marginal relief", "new", 1300000, 0, 0, 78000},
+		{"new regime top slab", "new", 2400000, 0, 0, 312000},
+		{"new regime with 10% surcharge", "new", 6000000, 0, 138000, 1578720},
+		{"old regime within the rebate limit", "old", 500000, 12500, 0, 0},
+		{"old regime has no marginal relief", "old", 510000, 0, 0, 15080},
+		{"old regime 30% slab", "old", 1000000, 0, 0, 117000},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Regimes[tc.regime].Compute(tc.taxable)
+			if got.Rebate != tc.rebate || got.Surcharge != tc.surcharge || got.TotalTax != tc.total {
+				t.Errorf("%s regime on %.0f: rebate %.2f, surcharge %.2f, total %.2f; want rebate %.2f, surcharge %.2f, total %.2f",
+					tc.regime, tc.taxable, got.Rebate, got.Surcharge, got.TotalTax, tc.rebate, tc.surcharge, tc.total)
+			}
+		})
+	}
+}
+
+// TestMarginalRelief checks that earning more never leaves less after tax, the
+// guarantee that 87A and surcharge marginal relief exist to give. Incomes are
+// drawn around every threshold where relief applies; the old regime has no 87A
+// relief, so its rebate limit is a genuine cliff.
+func TestMarginalRelief(t *testing.T) {
+	r := rand.New(rand.NewSource(20250401))
+	for _, name := range Names() {
+		regime := Regimes[name]
+		thresholds := []float64{}
+		if regime.MarginalRelief {
+			thresholds = append(thresholds, regime.RebateLimit)
+		}
+		for _, band := range regime.Surcharge {
+			thresholds = append(thresholds, band.Above)
+		}
+
+		for _, threshold := range thresholds {
+			for i := 0; i < 500; i++ {
+				income := math.Round(threshold * (0.9 + r.Float64()*0.2))
+				raise := math.Round(1 + r.Float64()*threshold*0.05)
+				before, after := regime.Compute(income), regime.Compute(income+raise)
+
+				if after.TotalTax < before.TotalTax {
+					t.Fatalf("%s regime: tax fell from %.2f to %.2f when income rose from %.0f to %.0f",
+						name, before.TotalTax, after.TotalTax, income, income+raise)
+				}
+				// Cess is charged on the relieved tax, so allow for it and for rounding
+				if extra := after.TotalTax - before.TotalTax; extra > raise*(1+regime.CessPercent/100)+0.02 {
+					t.Fatalf("%s regime: a raise of %.0f from %.0f cost %.2f in tax",
+						name, raise, income, extra)
+				}
+			}
+		}
+	}
+}
+
+func TestSlabsAddUpToSlabTax(t *testing.T) {
+	r := rand.New(rand.NewSource(20250402))
+	for _, name := range Names() {
+		for i := 0; i < 500; i++ {
+			income := math.Round(r.Float64() * 3e7)
+			got := Regimes[name].Compute(income)
+
+			slabTax, slabIncome := 0.0, 0.0
+			for _, slab := range got.Slabs {
+				slabTax += slab.Tax
+				slabIncome += slab.Income
+			}
+			if math.Abs(slabTax-got.SlabTax) > 0.005*float64(len(got.Slabs))+1e-6 {
+				t.Fatalf("%s regime on %.0f: slabs add up to %.2f, slab tax is %.2f", name, income, slabTax, got.SlabTax)
+			}
+			if math.Abs(slabIncome-income) > 1e-6 {
+				t.Fatalf("%s regime on %.0f: slabs cover %.2f of income", name, income, slabIncome)
+			}
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package units
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Distances converts distance units to kilometres
+var Distances = map[string]float64{
+	"km": 1,
+	"mi": 1.609344,
+}
+
+// Volumes converts fuel volume units to litres
+var Volumes = map[string]float64{
+	"l":      1,
+	"gal-us": 3.785411784,
+	"gal-uk": 4.54609,
+}
+
+// Periods gives how many times each period occurs in a year. A day is handled
+// separately by PerYear since it depends on how many days a week apply.
+var Periods = map[string]float64{
+	"day":     365,
+	"week":    52,
+	"month":   12,
+	"quarter": 4,
+	"year":    1,
+}
+
+// efficiencies lists the supported fuel efficiency units. l/100km is an inverse
+// measure, so conversion is not a single factor.
+var efficiencies = []string{"km/l", "l/100km", "mpg-us", "mpg-uk"}
+
+// DefaultDistance, DefaultVolume and DefaultEfficiency are assumed when a
+// request leaves the unit empty
+const (
+	DefaultDistance   = "km"
+	DefaultVolume     = "l"
+	DefaultEfficiency = "km/l"
+)
+
+// ToKilometres converts a distance in unit to kilometres
+func ToKilometres(distance float64, unit string) (float64, error) {
+	factor, ok := Distances[orDefault(unit, DefaultDistance)]
+	if !ok {
+		return 0, fmt.Errorf("unknown distance unit %q. Available distance units: %s", unit, strings.Join(keys(Distances), ", "))
+	}
+	return distance * factor, nil
+}
+
+// FromKilometres converts a distance in kilometres to unit
+func FromKilometres(km float64, unit string) (float64, error) {
+	factor, ok := Distances[orDefault(unit, DefaultDistance)]
+	if !ok {
+		return 0, fmt.Errorf("unknown distance unit %q. Available distance units: %s", unit, strings.Join(keys(Distances), ", "))
+	}
+	return km / factor, nil
+}
+
+// ToLitres converts a volume in unit to litres
+func ToLitres(volume float64, unit string) (float64, error) {
+	factor, ok := Volumes[orDefault(unit, DefaultVolume)]
+	if !ok {
+		return 0, fmt.Errorf("unknown volume unit %q. Available volume units: %s", unit, strings.Join(keys(Volumes), ", "))
+	}
+	return volume * factor, nil
+}
+
+// KilometresPerLitre converts a fuel efficiency figure in unit to km/l
+func KilometresPerLitre(efficiency float64, unit string) (float64, error) {
+	if efficiency <= 0 {
+		return 0, fmt.Errorf("fuel efficiency must be greater than zero")
+	}
+
+	switch orDefault(unit, DefaultEfficiency) {
+	case "km/l":
+		return efficiency, nil
+	case "l/100km":
+		return 100 / efficiency, nil
+	case "mpg-us":
+		return efficiency * Distances["mi"] / Volumes["gal-us"], nil
+	case "mpg-uk":
+		return efficiency * Distances["mi"] / Volumes["gal-uk"], nil
+	}
+	return 0, fmt.Errorf("unknown fuel efficiency unit %q. Available efficiency units: %s", unit, strings.Join(efficiencies, ", "))
+}
+
+// PerYear returns how many times something that happens times per period
+// happens in a year. For "day", daysPerWeek limits it to the days it applies
+// (e.g. 5 for working days); zero means every day.
+func PerYear(times float64, period string, daysPerWeek float64) (float64, error) {
+	if period == "day" && daysPerWeek > 0 {
+		return times * daysPerWeek * Periods["week"], nil
+	}
+
+	occurrences, ok := Periods[period]
+	if !ok {
+		return 0, fmt.Errorf("unknown period %q. Available periods: %s", period, strings.Join(keys(Periods), ", "))
+	}
+	return times * occurrences, nil
+}
+
+func orDefault(unit, fallback string) string {
+	if unit == "" {
+		return fallback
+	}
+	return strings.ToLower(unit)
+}
+
+func keys(m map[string]float64) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}